@@ -0,0 +1,89 @@
+package glager
+
+import "github.com/onsi/gomega/types"
+
+// anchor specifies the position in the log a sequence has to be found at.
+type anchor int
+
+const (
+	anchorNone anchor = iota
+	anchorStart
+	anchorEnd
+)
+
+// BeginWith checks if the specified entries appear inside the log in the right
+// order, just like ContainSequence does, and that the first of them is the
+// very first entry of the log.
+//
+// Example:
+//
+//	Expect(logger).To(BeginWith(Info(Action("test.starting"))))
+func BeginWith(expectedSequence ...SequenceEntry) types.GomegaMatcher {
+	return &logMatcher{
+		expected: compileSequence(sequenceOf(expectedSequence)),
+		anchor:   anchorStart,
+	}
+}
+
+// EndWith checks if the specified entries appear inside the log in the right
+// order, just like ContainSequence does, and that the last of them is the very
+// last entry of the log.
+//
+// Example:
+//
+//	Expect(logger).To(EndWith(Info(Action("test.exited"))))
+func EndWith(expectedSequence ...SequenceEntry) types.GomegaMatcher {
+	return &logMatcher{
+		expected: compileSequence(sequenceOf(expectedSequence)),
+		anchor:   anchorEnd,
+	}
+}
+
+// matchAnchoredSequence matches the expected sequence like matchSequence does,
+// additionally requiring its first entry to match the first actual entry, or
+// its last entry to match the last actual entry, depending on the anchor.
+func (entries logEntries) matchAnchoredSequence(expectedSequence []logEntry, at anchor) ([]int, error) {
+	if len(expectedSequence) == 0 || len(entries) == 0 {
+		return entries.matchSequence(expectedSequence)
+	}
+
+	switch at {
+	case anchorStart:
+		containsEntry, err := entries[0].contains(expectedSequence[0])
+		if err != nil || !containsEntry {
+			return []int{}, err
+		}
+
+		matched, err := entries[1:].matchSequence(expectedSequence[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		result := []int{0}
+		for _, i := range matched {
+			result = append(result, i+1)
+		}
+		return result, nil
+	case anchorEnd:
+		last := len(entries) - 1
+
+		matched, err := entries[:last].matchSequence(expectedSequence[:len(expectedSequence)-1])
+		if err != nil || len(matched) < len(expectedSequence)-1 {
+			return matched, err
+		}
+
+		containsEntry, err := entries[last].contains(expectedSequence[len(expectedSequence)-1])
+		if err != nil || !containsEntry {
+			return matched, err
+		}
+
+		absent, err := entries.ensureAbsent(expectedSequence, matched, len(matched)-1, expectedSequence[len(expectedSequence)-1].absentBefore, last)
+		if err != nil || !absent {
+			return matched, err
+		}
+
+		return append(matched, last), nil
+	default:
+		return entries.matchSequence(expectedSequence)
+	}
+}
@@ -0,0 +1,69 @@
+package glager_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("anchored sequences", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		logger.Info("starting")
+		logger.Info("started")
+		logger.Error("failed", errors.New("boom"))
+		logger.Info("exited")
+	})
+
+	Describe(".BeginWith", func() {
+		It("matches a sequence starting with the first entry", func() {
+			Expect(logger).To(BeginWith(Info(Action("test.starting"))))
+			Expect(logger).To(BeginWith(Info(Action("test.starting")), Info(Action("test.exited"))))
+		})
+
+		It("does not match a sequence starting later", func() {
+			Expect(logger).To(ContainSequence(Info(Action("test.started"))))
+			Expect(logger).ToNot(BeginWith(Info(Action("test.started"))))
+		})
+
+		It("reports the first entry as closest candidate", func() {
+			matcher := BeginWith(Info(Action("test.started")))
+			Expect(matcher.Match(logger)).To(BeFalse())
+
+			message := matcher.FailureMessage(logger)
+			Expect(message).To(ContainSubstring("to contain log sequence at its start"))
+			Expect(message).To(ContainSubstring("Closest candidate, entry 0:\n\tmessage: expected \"test.started\", got \"test.starting\""))
+		})
+	})
+
+	Describe(".EndWith", func() {
+		It("matches a sequence ending with the last entry", func() {
+			Expect(logger).To(EndWith(Info(Action("test.exited"))))
+			Expect(logger).To(EndWith(Info(Action("test.starting")), Error(AnyErr), Info(Action("test.exited"))))
+		})
+
+		It("does not match a sequence ending earlier", func() {
+			Expect(logger).ToNot(EndWith(Error(AnyErr)))
+			Expect(logger).ToNot(EndWith(Info(Action("test.started")), Info(Action("test.starting"))))
+		})
+
+		It("reports the last entry as closest candidate", func() {
+			matcher := EndWith(Info(Action("test.starting")), Info(Action("test.started")))
+			Expect(matcher.Match(logger)).To(BeFalse())
+
+			message := matcher.FailureMessage(logger)
+			Expect(message).To(ContainSubstring("to contain log sequence at its end"))
+			Expect(message).To(ContainSubstring("Closest candidate, entry 3:"))
+		})
+
+		It("returns an error for invalid actuals", func() {
+			_, err := EndWith(Info()).Match(42)
+			Expect(err).To(MatchError(HavePrefix("EndWith must be passed")))
+		})
+	})
+})
@@ -0,0 +1,78 @@
+package glager
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnyOf specifies a log entry that is matched by any entry matching one of the
+// given alternatives. Use it for positions of a sequence that can be satisfied
+// by different entries, e.g. depending on timing. Captures of the first
+// alternative that matches are applied.
+//
+// Example:
+//
+//	Expect(logger).To(ContainSequence(
+//	  Info(Action("cache.lookup")),
+//	  AnyOf(Info(Action("cache.hit")), Info(Action("cache.miss"))),
+//	  Info(Action("cache.done")),
+//	))
+func AnyOf(alternatives ...logEntry) logEntry {
+	if len(alternatives) == 0 {
+		panic(fmt.Errorf("AnyOf must be passed at least one entry. Got none."))
+	}
+
+	return logEntry{
+		alternatives: alternatives,
+	}
+}
+
+// containsAny reports whether the actual entry matches any of the alternatives
+// of the expected entry.
+func (actual LogEntry) containsAny(expected logEntry) (bool, error) {
+	for _, alternative := range expected.alternatives {
+		containsEntry, err := actual.contains(alternative)
+		if err != nil || containsEntry {
+			return containsEntry, err
+		}
+	}
+	return false, nil
+}
+
+// captureAny applies the captures of the first alternative of the expected
+// entry that matches the actual entry.
+func (entry logEntry) captureAny(actual LogEntry) {
+	for _, alternative := range entry.alternatives {
+		if containsEntry, err := actual.contains(alternative); err == nil && containsEntry {
+			alternative.capture(actual)
+			return
+		}
+	}
+}
+
+// alternativeDiffs describes how the actual entry differs from the alternative
+// of the expected entry that it comes closest to.
+func (actual LogEntry) alternativeDiffs(expected logEntry) ([]string, error) {
+	var best []string
+	for i, alternative := range expected.alternatives {
+		diffs, err := actual.fieldDiffs(alternative)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 || len(diffs) < len(best) {
+			best = diffs
+		}
+	}
+	return best, nil
+}
+
+// renderAlternatives renders an entry with alternatives the way it has been
+// constructed.
+func renderAlternatives(alternatives []logEntry) string {
+	rendered := make([]string, len(alternatives))
+	for i, alternative := range alternatives {
+		rendered[i] = alternative.GomegaString()
+	}
+	return fmt.Sprintf("AnyOf(%s)", strings.Join(rendered, ", "))
+}
@@ -0,0 +1,66 @@
+package glager_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".AnyOf", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("cache")
+		logger.Info("lookup")
+		logger.Info("miss", lager.Data{"key": "user-1"})
+		logger.Info("done")
+	})
+
+	It("matches an entry matching any of the alternatives", func() {
+		Expect(logger).To(ContainSequence(
+			Info(Action("cache.lookup")),
+			AnyOf(Info(Action("cache.hit")), Info(Action("cache.miss"))),
+			Info(Action("cache.done")),
+		))
+	})
+
+	It("does not match if none of the alternatives matches", func() {
+		Expect(logger).ToNot(ContainSequence(
+			Info(Action("cache.lookup")),
+			AnyOf(Info(Action("cache.hit")), Error(AnyErr, Action("cache.miss"))),
+		))
+	})
+
+	It("applies the captures of the matching alternative", func() {
+		var hit, miss LogEntry
+		Expect(logger).To(ContainSequence(
+			AnyOf(Info(Action("cache.hit"), CaptureInto(&hit)), Info(Action("cache.miss"), CaptureInto(&miss))),
+		))
+
+		Expect(hit).To(BeZero())
+		Expect(miss.Data).To(HaveKeyWithValue("key", "user-1"))
+	})
+
+	It("reports the closest alternative", func() {
+		logger.Error("failed", errors.New("boom"))
+
+		matcher := ContainSequence(
+			Info(Action("cache.done")),
+			AnyOf(Info(Action("cache.failed")), Debug(Action("cache.evicted"))),
+		)
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		message := matcher.FailureMessage(logger)
+		Expect(message).To(ContainSubstring(`1: AnyOf(Info(Message("cache.failed")), Debug(Message("cache.evicted")))`))
+		Expect(message).To(ContainSubstring("Closest candidate, entry 3:\n\tlevel: expected info, got error"))
+	})
+
+	It("panics without alternatives", func() {
+		Expect(func() { AnyOf() }).To(PanicWith(MatchError("AnyOf must be passed at least one entry. Got none.")))
+	})
+})
@@ -0,0 +1,175 @@
+package glager
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/onsi/ginkgo"
+)
+
+// LogCapture is the log written during the current spec, see AutoCapture.
+//
+// LogCapture implements ContentsProvider, i.e. it can be passed to any of the
+// matchers.
+type LogCapture struct {
+	actual interface{}
+	writer io.Writer
+	log    interface{}
+	offset int
+	lock   sync.Mutex
+}
+
+// AutoCapture captures the log written during every spec of the enclosing
+// Ginkgo container and writes its entries, pretty-printed, to GinkgoWriter if
+// the spec fails, whose output Ginkgo reports along with the failure. This
+// saves writing a JustAfterEach for every suite that
+// wants to see the log of failing specs. The log can be anything that is
+// accepted by the ContainSequence matcher, e.g. a TestLogger or a
+// gbytes.Buffer, or a function without arguments returning it, e.g. if the
+// logger is created in a BeforeEach. Functions are called at the beginning and
+// the end of every spec. Entries logged by BeforeEach blocks that have been
+// declared before AutoCapture has been called are not captured, unless the
+// log has been replaced by them.
+//
+// Since Ginkgo does not allow adding setup nodes while specs are running,
+// AutoCapture has to be called in the body of a Describe, Context, or When
+// instead of a BeforeEach.
+//
+// Example:
+//
+//	var logger *TestLogger
+//
+//	BeforeEach(func() {
+//	  logger = NewLogger("test")
+//	})
+//
+//	AutoCapture(func() *TestLogger { return logger })
+func AutoCapture(actual interface{}) *LogCapture {
+	return AutoCaptureTo(ginkgo.GinkgoWriter, actual)
+}
+
+// AutoCaptureTo is like AutoCapture, but writes the entries logged during
+// failed specs to the given writer, e.g. a file collected by CI.
+//
+// Example:
+//
+//	AutoCaptureTo(os.Stderr, func() *TestLogger { return logger })
+func AutoCaptureTo(writer io.Writer, actual interface{}) *LogCapture {
+	capture := &LogCapture{actual: actual, writer: writer}
+
+	ginkgo.BeforeEach(capture.start)
+
+	ginkgo.JustAfterEach(func() {
+		if ginkgo.CurrentGinkgoTestDescription().Failed {
+			fmt.Fprintln(capture.writer, capture.String())
+		}
+	})
+
+	return capture
+}
+
+// start marks the beginning of a spec. Everything that has been logged so far
+// is excluded from the capture.
+func (c *LogCapture) start() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.log, c.offset = c.resolve(), 0
+	if contents, err := readCaptured(c.log); err == nil {
+		c.offset = len(contents)
+	}
+}
+
+// resolve returns the log that is captured, calling the function that has
+// been passed to AutoCapture if necessary.
+func (c *LogCapture) resolve() interface{} {
+	value := reflect.ValueOf(c.actual)
+	if value.Kind() != reflect.Func || value.IsNil() || value.Type().NumIn() != 0 || value.Type().NumOut() != 1 {
+		return c.actual
+	}
+	return value.Call(nil)[0].Interface()
+}
+
+// read returns the contents of the log that have been written during the
+// current spec. If the log has been replaced since the spec started, e.g. by
+// a logger created in a BeforeEach, its entire contents are returned.
+func (c *LogCapture) read() ([]byte, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	log := c.resolve()
+
+	contents, err := readCaptured(log)
+	if err != nil {
+		return nil, err
+	}
+
+	if !sameLog(log, c.log) || len(contents) < c.offset {
+		return contents, nil
+	}
+	return contents[c.offset:], nil
+}
+
+// Contents implements ContentsProvider. It returns the log written during the
+// current spec, or nil if the log cannot be read.
+func (c *LogCapture) Contents() []byte {
+	contents, _ := c.read()
+	return contents
+}
+
+// String returns the entries that have been logged during the current spec,
+// pretty-printed, the way they are written for failed specs.
+func (c *LogCapture) String() string {
+	contents, err := c.read()
+	if err != nil {
+		return fmt.Sprintf("Failed to read the log captured during the spec: %s", err)
+	}
+
+	entries, err := parseEntries("AutoCapture", snapshot(contents))
+	if err != nil {
+		return fmt.Sprintf("Log captured during the spec, which cannot be parsed (%s):\n%s", err, contents)
+	}
+
+	if len(entries) == 0 {
+		return "No entries have been logged during the spec."
+	}
+
+	rendered := make([]string, len(entries))
+	for i, entry := range entries {
+		rendered[i] = entry.render(nil)
+	}
+
+	return fmt.Sprintf("Log entries captured during the spec:\n%s", strings.Join(rendered, "\n"))
+}
+
+// readCaptured reads the current contents of the given log.
+func readCaptured(log interface{}) ([]byte, error) {
+	if value := reflect.ValueOf(log); !value.IsValid() || value.Kind() == reflect.Ptr && value.IsNil() {
+		return nil, nil
+	}
+
+	reader, err := contentsReader("AutoCapture", log)
+	if err != nil {
+		return nil, err
+	}
+	defer closeContents(reader)
+
+	return io.ReadAll(reader)
+}
+
+// sameLog reports whether the given logs are identical. Logs that are no
+// pointers are considered identical if they are of the same type.
+func sameLog(a, b interface{}) bool {
+	valueA, valueB := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !valueA.IsValid() || !valueB.IsValid() || valueA.Type() != valueB.Type() {
+		return false
+	}
+
+	if valueA.Kind() == reflect.Ptr {
+		return valueA.Pointer() == valueB.Pointer()
+	}
+	return true
+}
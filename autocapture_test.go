@@ -0,0 +1,105 @@
+package glager_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".AutoCapture", func() {
+	Context("with a logger created for every spec", func() {
+		var logger *TestLogger
+
+		capture := AutoCapture(func() *TestLogger { return logger })
+
+		BeforeEach(func() {
+			logger = NewLogger("test")
+			logger.Info("setup")
+		})
+
+		It("captures the entries logged during the spec", func() {
+			logger.Error("failed", errors.New("boom"), lager.Data{"attempt": 2})
+
+			Expect(capture).To(ContainExactSequence(
+				Info(Action("test.setup")),
+				Error(errors.New("boom"), Action("test.failed"), Data("attempt", 2)),
+			))
+		})
+
+		It("pretty-prints the captured entries", func() {
+			logger.Error("failed", errors.New("boom"))
+
+			rendered := capture.String()
+			Expect(rendered).To(HavePrefix("Log entries captured during the spec:\nglager.LogEntry{"))
+			Expect(rendered).To(ContainSubstring(`Message:   "test.setup",`))
+			Expect(rendered).To(ContainSubstring(`Message:   "test.failed",`))
+			Expect(rendered).To(ContainSubstring(`"error": "boom",`))
+		})
+	})
+
+	Context("with a log shared by all specs", func() {
+		buffer := gbytes.NewBuffer()
+		logger := lager.NewLogger("shared")
+		logger.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+
+		capture := AutoCapture(buffer)
+
+		It("captures the entries of the first spec", func() {
+			logger.Info("first")
+			Expect(capture).To(ContainExactSequence(Info(Action("shared.first"))))
+		})
+
+		It("does not capture the entries of previous specs", func() {
+			logger.Info("second")
+			Expect(capture).To(ContainExactSequence(Info(Action("shared.second"))))
+		})
+	})
+
+	Context("without any entries", func() {
+		capture := AutoCapture(gbytes.NewBuffer())
+
+		It("reports that nothing has been logged", func() {
+			Expect(capture.String()).To(Equal("No entries have been logged during the spec."))
+		})
+	})
+
+	Context("when the spec fails", func() {
+		// the failing spec is only run by the spec below, in a separate process
+		if os.Getenv("GLAGER_AUTOCAPTURE_FAILING_SPEC") != "" {
+			logger := NewLogger("failing")
+			AutoCaptureTo(os.Stdout, logger)
+
+			It("fails deliberately", func() {
+				logger.Info("captured")
+				Fail("Deliberate failure.")
+			})
+		}
+
+		It("writes the captured entries to the writer", func() {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestGlager$", "-ginkgo.focus=fails deliberately", "-ginkgo.noColor")
+			cmd.Env = append(os.Environ(), "GLAGER_AUTOCAPTURE_FAILING_SPEC=true")
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).To(HaveOccurred())
+			Expect(string(output)).To(ContainSubstring("Deliberate failure."))
+			Expect(string(output)).To(ContainSubstring("Log entries captured during the spec:\nglager.LogEntry{"))
+			Expect(string(output)).To(ContainSubstring(`Message:   "failing.captured",`))
+		})
+	})
+
+	Context("with an invalid log", func() {
+		capture := AutoCapture(42)
+
+		It("reports the error", func() {
+			Expect(capture.String()).To(HavePrefix("Failed to read the log captured during the spec: AutoCapture must be passed"))
+		})
+	})
+})
@@ -0,0 +1,87 @@
+package glager_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+// benchmarkLog returns a buffer holding a log of the given number of entries
+// with a mix of levels and data, similar to the logs of a typical component.
+func benchmarkLog(size int) *gbytes.Buffer {
+	buffer := gbytes.NewBuffer()
+	logger := lager.NewLogger("api")
+	logger.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+
+	for i := 0; i < size-2; i++ {
+		switch i % 3 {
+		case 0:
+			logger.Debug("poll", lager.Data{"attempt": i, "endpoint": "/v2/info"})
+		case 1:
+			logger.Info("request", lager.Data{"method": "GET", "path": fmt.Sprintf("/v2/apps/%d", i), "status": 200})
+		default:
+			logger.Error("request-failed", errors.New("timeout"), lager.Data{"retry": true})
+		}
+	}
+
+	logger.Info("shutdown", lager.Data{"signal": "TERM", "exit": map[string]interface{}{"code": 0}})
+	logger.Info("done")
+
+	return buffer
+}
+
+var benchmarkSequence = []SequenceEntry{
+	Info(Source("api"), Message("api.request"), Data("method", "GET", "status", 200)),
+	Error(AnyErr, Message("api.request-failed"), Data("retry", true)),
+	Info(Message("api.shutdown"), Data("signal", "TERM", "exit", map[string]interface{}{"code": 0})),
+	Info(Message("api.done")),
+}
+
+func BenchmarkContainSequence(b *testing.B) {
+	buffer := benchmarkLog(1000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		matched, err := ContainSequence(benchmarkSequence...).Match(buffer)
+		if err != nil || !matched {
+			b.Fatalf("expected the sequence to match, got %t, %v", matched, err)
+		}
+	}
+}
+
+func BenchmarkContainSequenceEntries(b *testing.B) {
+	entries, err := Entries(benchmarkLog(1000))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		matched, err := ContainSequence(benchmarkSequence...).Match(entries)
+		if err != nil || !matched {
+			b.Fatalf("expected the sequence to match, got %t, %v", matched, err)
+		}
+	}
+}
+
+func BenchmarkEntries(b *testing.B) {
+	buffer := benchmarkLog(1000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Entries(buffer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package glager
+
+import "code.cloudfoundry.org/lager"
+
+// BufferedLogger is a TestLogger that additionally implements ContentsProvider
+// and can be reset, i.e. the same value can be passed to the code under test,
+// to any of the matchers, and to helpers expecting a gbytes.BufferProvider,
+// e.g. LoggedDuring, throughout all steps of a test.
+type BufferedLogger struct {
+	*TestLogger
+}
+
+// NewTestLogger returns a new BufferedLogger for the given source. The
+// returned logger uses log level lager.DEBUG.
+//
+// Example:
+//
+//	logger := NewTestLogger("test")
+//	myFunc(logger)
+//	Expect(logger).To(ContainSequence(Info(Action("test.myFunc"))))
+func NewTestLogger(source string) *BufferedLogger {
+	return &BufferedLogger{NewLoggerWithLevel(source, lager.DEBUG)}
+}
+
+// Contents implements ContentsProvider. It returns everything that has been
+// logged since the logger has been created or last reset.
+func (l *BufferedLogger) Contents() []byte {
+	return l.buf.Contents()
+}
+
+// Entries parses and returns the entries that have been logged since the
+// logger has been created or last reset.
+func (l *BufferedLogger) Entries() ([]LogEntry, error) {
+	return Entries(l)
+}
+
+// Reset discards everything that has been logged so far, e.g. to reuse a
+// logger across the steps of a test. Cursors created for the logger must not
+// be used after resetting it.
+func (l *BufferedLogger) Reset() {
+	// the buffer is never closed, i.e. clearing it cannot fail
+	_ = l.buf.Clear()
+}
@@ -0,0 +1,57 @@
+package glager_test
+
+import (
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("BufferedLogger", func() {
+	var logger *BufferedLogger
+
+	BeforeEach(func() {
+		logger = NewTestLogger("test")
+		logger.Debug("first")
+		logger.Info("second")
+	})
+
+	It("can be passed to code expecting a lager.Logger", func() {
+		var log lager.Logger = logger
+		log.Session("session").Info("third")
+
+		Expect(logger).To(ContainSequence(Info(Action("test.session.third"))))
+	})
+
+	It("returns the logged contents", func() {
+		Expect(string(logger.Contents())).To(ContainSubstring("test.first"))
+	})
+
+	It("returns the logged entries", func() {
+		entries, err := logger.Entries()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0].LogLevel).To(Equal(lager.DEBUG))
+		Expect(entries[1].Message).To(Equal("test.second"))
+	})
+
+	It("can be passed to helpers expecting a gbytes.BufferProvider", func() {
+		log := LoggedDuring(logger, func() {
+			logger.Info("third")
+		})
+
+		Expect(log).To(ContainSequence(Info(Action("test.third"))))
+		Expect(log).ToNot(ContainSequence(Info(Action("test.second"))))
+	})
+
+	It("discards everything logged before a reset", func() {
+		logger.Reset()
+		Expect(logger.Contents()).To(BeEmpty())
+
+		logger.Info("third")
+		Expect(logger).ToNot(ContainSequence(Info(Action("test.first"))))
+		Expect(logger).To(ContainSequence(Info(Action("test.third"))))
+	})
+})
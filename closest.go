@@ -0,0 +1,115 @@
+package glager
+
+import (
+	"fmt"
+	"strings"
+)
+
+// closest describes how the actual entry that comes closest to the expected
+// one differs from it, field by field. The closest entry is the one with the
+// fewest differing fields out of level, source, message, data keys, and
+// custom checks. Ties are resolved in favor of the earliest entry. The offset is added
+// to the reported entry index. The differences are followed by the closest
+// entry, with the differing fields highlighted. An empty string is returned if
+// there are no entries.
+func (entries logEntries) closest(expected logEntry, offset int) (string, error) {
+	var candidate closestCandidate
+	for i, actual := range entries {
+		if err := candidate.consider(expected, actual, offset+i); err != nil {
+			return "", err
+		}
+	}
+	return candidate.String(), nil
+}
+
+// closestCandidate is the actual entry that comes closest to an expected entry
+// out of the entries that have been considered so far, see closest.
+type closestCandidate struct {
+	entry LogEntry
+	index int
+	diffs []string
+	found bool
+}
+
+// consider compares the given actual entry, found at the given index, with the
+// expected entry, and retains it if it comes closer than the current one.
+func (c *closestCandidate) consider(expected logEntry, actual LogEntry, index int) error {
+	diffs, err := actual.fieldDiffs(expected)
+	if err != nil {
+		return err
+	}
+
+	if !c.found || len(diffs) < len(c.diffs) {
+		*c = closestCandidate{entry: actual, index: index, diffs: diffs, found: true}
+	}
+	return nil
+}
+
+// String renders the differences of the closest entry, followed by the entry
+// itself, or returns an empty string if there is none.
+func (c closestCandidate) String() string {
+	if !c.found || len(c.diffs) == 0 {
+		return ""
+	}
+
+	rendered := truncate(c.entry.render(highlightedFields(c.diffs)))
+
+	return fmt.Sprintf(
+		"\nClosest candidate, entry %d:\n\t%s\n\t%s",
+		c.index,
+		strings.Join(c.diffs, "\n\t"),
+		strings.ReplaceAll(rendered, "\n", "\n\t"),
+	)
+}
+
+// fieldDiffs describes every field of the actual entry that does not match the
+// expected entry. Custom checks are only evaluated if all fields match.
+func (actual LogEntry) fieldDiffs(expected logEntry) ([]string, error) {
+	if len(expected.alternatives) > 0 {
+		return actual.alternativeDiffs(expected)
+	}
+
+	var diffs []string
+
+	expected, actual = ignoreData(expected, actual)
+
+	if !expected.matchesLevel(actual.LogLevel) {
+		qualifier := ""
+		if expected.atLeast {
+			qualifier = "at least "
+		}
+		diffs = append(diffs, fmt.Sprintf("level: expected %s%s, got %s", qualifier, levelName(expected.LogLevel), levelName(actual.LogLevel)))
+	}
+
+	if expected.Source != "" && actual.Source != expected.Source {
+		diffs = append(diffs, fmt.Sprintf("source: expected %q, got %q", expected.Source, actual.Source))
+	}
+
+	if expected.Message != "" && actual.Message != expected.Message {
+		diffs = append(diffs, fmt.Sprintf("message: expected %q, got %q", expected.Message, actual.Message))
+	}
+
+	mismatches, err := actual.logData().mismatches(expected.logData())
+	if err != nil {
+		return nil, err
+	}
+	diffs = append(diffs, mismatches...)
+
+	if len(diffs) > 0 {
+		return diffs, nil
+	}
+
+	for _, check := range expected.checks {
+		matches, err := check.match(actual)
+		if err != nil {
+			return nil, err
+		}
+
+		if !matches {
+			diffs = append(diffs, fmt.Sprintf("check: expected %s", check.description))
+			break
+		}
+	}
+
+	return diffs, nil
+}
@@ -0,0 +1,81 @@
+package glager_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("closest candidate", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		logger.Info("start", lager.Data{"user": "admin"})
+		logger.Error("stop", errors.New("boom"), lager.Data{"user": "guest"})
+	})
+
+	It("reports the field-level differences of the closest entry", func() {
+		matcher := ContainSequence(Info(Action("test.start")), Info(Action("test.stop"), Data("user", "guest")))
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		message := matcher.FailureMessage(logger)
+		Expect(message).To(ContainSubstring("Closest candidate, entry 1:\n\tlevel: expected info, got error\n"))
+		Expect(message).ToNot(ContainSubstring("message:"))
+	})
+
+	It("prefers the entry with the fewest differences", func() {
+		matcher := ContainSequence(Debug(Action("test.stop"), Data("user", "admin")))
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		Expect(matcher.FailureMessage(logger)).To(ContainSubstring(
+			"Closest candidate, entry 0:\n\tlevel: expected debug, got info\n\tmessage: expected \"test.stop\", got \"test.start\"\n",
+		))
+	})
+
+	It("reports failing custom checks", func() {
+		matcher := ContainSequence(Info(Action("test.start"), Check("a short user", func(actual LogEntry) (bool, error) {
+			return len(actual.Data["user"].(string)) < 3, nil
+		})))
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		Expect(matcher.FailureMessage(logger)).To(ContainSubstring("Closest candidate, entry 0:\n\tcheck: expected a short user"))
+	})
+
+	It("reports every mismatching data key", func() {
+		logger.Info("request", lager.Data{"user": "admin", "port": 8080})
+
+		matcher := ContainSequence(Info(Action("test.request"), Data("user", "guest", "port", "8080", "tls", true)))
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		Expect(matcher.FailureMessage(logger)).To(ContainSubstring(
+			"Data mismatches:\n" +
+				"\tentry 2: data.port: type mismatch, expected a string \"8080\", got a number 8080\n" +
+				"\tentry 2: data.tls: missing\n" +
+				"\tentry 2: data.user: expected \"guest\", got \"admin\"",
+		))
+	})
+
+	It("prefers the entry with the fewest mismatching data keys", func() {
+		logger.Info("stop", lager.Data{"user": "guest", "port": 8080})
+
+		matcher := ContainSequence(Debug(Data("user", "guest", "port", 8080)))
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		Expect(matcher.FailureMessage(logger)).To(ContainSubstring("Closest candidate, entry 2:\n\tlevel: expected debug, got info\n\t"))
+	})
+
+	It("is omitted if data mismatches are reported", func() {
+		matcher := ContainSequence(Info(Action("test.start"), Data("user", "root")))
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		message := matcher.FailureMessage(logger)
+		Expect(message).To(ContainSubstring("Data mismatches:"))
+		Expect(message).ToNot(ContainSubstring("Closest candidate"))
+	})
+})
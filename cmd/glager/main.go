@@ -0,0 +1,99 @@
+// Command glager asserts that a lager log file contains a given sequence of
+// entries. It exits non-zero and prints the same failure message as the
+// ContainSequence matcher if it does not. Use it to reuse glager's matching
+// outside of Go tests, e.g. in BOSH errands or shell-based acceptance tests.
+//
+// Usage:
+//
+//	glager [-verbosity summary|window|full] <sequence-file> <log-file>
+//
+// The sequence file is a YAML or JSON list of glager.SpecEntry objects, see
+// glager.LoadSequence. Use "-" as log file to read the log from stdin.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/st3v/glager"
+)
+
+const (
+	exitMatched  = 0
+	exitMismatch = 1
+	exitError    = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("glager", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	verbosity := flags.String("verbosity", "full", "amount of the log included in failure messages: summary, window, or full")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: glager [-verbosity summary|window|full] <sequence-file> <log-file>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitError
+	}
+
+	v, err := parseVerbosity(*verbosity)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	sequence, err := glager.LoadSequence(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	log := stdin
+	if flags.Arg(1) != "-" {
+		file, err := os.Open(flags.Arg(1))
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		defer file.Close()
+		log = file
+	}
+
+	matcher := glager.WithVerbosity(v, glager.ContainSequence(sequence...))
+	matched, err := matcher.Match(log)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	if !matched {
+		fmt.Fprintln(stdout, matcher.FailureMessage(log))
+		return exitMismatch
+	}
+
+	return exitMatched
+}
+
+func parseVerbosity(name string) (glager.Verbosity, error) {
+	switch name {
+	case "summary":
+		return glager.VerbositySummary, nil
+	case "window":
+		return glager.VerbosityWindow, nil
+	case "full":
+		return glager.VerbosityFull, nil
+	}
+	return 0, fmt.Errorf("Invalid verbosity %q.", name)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+func TestGlagerCommand(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Glager Command Test Suite")
+}
+
+var _ = Describe("glager", func() {
+	var (
+		dir            string
+		spec           string
+		log            string
+		stdout, stderr *gbytes.Buffer
+	)
+
+	write := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "glager")
+		Expect(err).ToNot(HaveOccurred())
+
+		stdout, stderr = gbytes.NewBuffer(), gbytes.NewBuffer()
+
+		log = write("app.log", strings.Join([]string{
+			`{"timestamp":"1.0","source":"app","message":"app.start","log_level":1,"data":{}}`,
+			`{"timestamp":"2.0","source":"app","message":"app.failed","log_level":2,"data":{"error":"boom"}}`,
+		}, "\n")+"\n")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Context("when the log contains the sequence", func() {
+		BeforeEach(func() {
+			spec = write("sequence.json", `[
+				{"level": "info", "message": "app.start"},
+				{"level": "error", "error": "boom"}
+			]`)
+		})
+
+		It("exits zero", func() {
+			Expect(run([]string{spec, log}, nil, stdout, stderr)).To(Equal(exitMatched))
+			Expect(stdout.Contents()).To(BeEmpty())
+		})
+
+		It("accepts YAML sequence files", func() {
+			spec = write("sequence.yml", "- level: info\n  message: app.start\n- level: error\n")
+			Expect(run([]string{spec, log}, nil, stdout, stderr)).To(Equal(exitMatched))
+		})
+
+		It("reads the log from stdin", func() {
+			file, err := os.Open(log)
+			Expect(err).ToNot(HaveOccurred())
+			defer file.Close()
+
+			Expect(run([]string{spec, "-"}, file, stdout, stderr)).To(Equal(exitMatched))
+		})
+	})
+
+	Context("when the log does not contain the sequence", func() {
+		BeforeEach(func() {
+			spec = write("sequence.json", `[
+				{"level": "error", "error": "boom"},
+				{"level": "info", "message": "app.start"}
+			]`)
+		})
+
+		It("exits with a failure message", func() {
+			Expect(run([]string{spec, log}, nil, stdout, stderr)).To(Equal(exitMismatch))
+			Expect(stdout).To(gbytes.Say("to contain log sequence"))
+			Expect(stdout).To(gbytes.Say(`1: not found`))
+		})
+
+		It("honors the verbosity", func() {
+			Expect(run([]string{"-verbosity", "summary", spec, log}, nil, stdout, stderr)).To(Equal(exitMismatch))
+			Expect(string(stdout.Contents())).ToNot(ContainSubstring("app.failed"))
+		})
+	})
+
+	Context("when the arguments are invalid", func() {
+		It("exits with usage for a missing argument", func() {
+			Expect(run([]string{log}, nil, stdout, stderr)).To(Equal(exitError))
+			Expect(stderr).To(gbytes.Say("Usage: glager"))
+		})
+
+		It("exits with an error for an invalid verbosity", func() {
+			Expect(run([]string{"-verbosity", "loud", log, log}, nil, stdout, stderr)).To(Equal(exitError))
+			Expect(stderr).To(gbytes.Say(`Invalid verbosity "loud".`))
+		})
+
+		It("exits with an error for an invalid spec", func() {
+			Expect(run([]string{log, log}, nil, stdout, stderr)).To(Equal(exitError))
+			Expect(stderr).To(gbytes.Say("Invalid sequence spec"))
+		})
+
+		It("exits with an error for a missing log file", func() {
+			spec = write("sequence.json", `[]`)
+			Expect(run([]string{spec, filepath.Join(dir, "missing.log")}, nil, stdout, stderr)).To(Equal(exitError))
+			Expect(stderr).To(gbytes.Say("no such file"))
+		})
+	})
+})
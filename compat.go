@@ -0,0 +1,35 @@
+package glager
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// LogFormatter is implemented by the LogFormat type of every version of
+// lager, i.e. pivotal-golang/lager as well as code.cloudfoundry.org/lager and
+// code.cloudfoundry.org/lager/v3. A LogFormatter, or a slice of them, can be
+// passed to any of the matchers, e.g. the entries collected by a custom sink,
+// regardless of the lager version the sink is written against.
+type LogFormatter interface {
+	ToJSON() []byte
+}
+
+// encodeFormats encodes the given LogFormatter, or slice of LogFormatters, as
+// newline-delimited JSON. It reports false for any other actual.
+func encodeFormats(actual interface{}) ([]byte, bool) {
+	if formatter, ok := actual.(LogFormatter); ok {
+		return append(formatter.ToJSON(), '\n'), true
+	}
+
+	value := reflect.ValueOf(actual)
+	if value.Kind() != reflect.Slice || !value.Type().Elem().Implements(reflect.TypeOf((*LogFormatter)(nil)).Elem()) {
+		return nil, false
+	}
+
+	buf := &bytes.Buffer{}
+	for i := 0; i < value.Len(); i++ {
+		buf.Write(value.Index(i).Interface().(LogFormatter).ToJSON())
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), true
+}
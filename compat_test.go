@@ -0,0 +1,50 @@
+package glager_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+// v3LogFormat mimics the LogFormat of lager v3, which is a distinct type with
+// RFC3339 timestamps.
+type v3LogFormat struct {
+	Timestamp string         `json:"timestamp"`
+	Source    string         `json:"source"`
+	Message   string         `json:"message"`
+	LogLevel  lager.LogLevel `json:"log_level"`
+	Data      lager.Data     `json:"data"`
+}
+
+func (f v3LogFormat) ToJSON() []byte {
+	encoded, _ := json.Marshal(f)
+	return encoded
+}
+
+var _ = Describe("lager compatibility", func() {
+	It("matches a LogFormat of lager v1", func() {
+		format := lager.LogFormat{Timestamp: "1.0", Source: "app", Message: "app.start", LogLevel: lager.INFO}
+		Expect(format).To(ContainSequence(Info(Action("app.start"))))
+	})
+
+	It("matches a slice of LogFormats of other lager versions", func() {
+		formats := []v3LogFormat{
+			{Timestamp: time.Unix(1, 0).UTC().Format(time.RFC3339Nano), Source: "app", Message: "app.start", LogLevel: lager.INFO},
+			{Timestamp: time.Unix(2, 0).UTC().Format(time.RFC3339Nano), Source: "app", Message: "app.failed", LogLevel: lager.ERROR, Data: lager.Data{"error": "boom"}},
+		}
+
+		Expect(formats).To(ContainSequence(Info(Action("app.start")), Error(AnyErr, Action("app.failed"))))
+		Expect(Latencies(formats, Info(), Error(AnyErr))).To(Equal([]time.Duration{time.Second}))
+	})
+
+	It("does not accept slices of other types", func() {
+		_, err := ContainSequence(Info()).Match([]int{1})
+		Expect(err).To(MatchError(HavePrefix("ContainSequence must be passed")))
+	})
+})
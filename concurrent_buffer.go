@@ -0,0 +1,95 @@
+package glager
+
+import (
+	"bytes"
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// ConcurrentBuffer collects the log of many goroutines logging at the same
+// time without tearing lines. Entries are only ever appended as complete
+// lines, and Contents only returns complete lines. It implements lager.Sink,
+// io.Writer, and ContentsProvider, i.e. it can be passed to any of the
+// matchers.
+type ConcurrentBuffer struct {
+	lock     sync.Mutex
+	contents []byte
+}
+
+var _ lager.Sink = &ConcurrentBuffer{}
+
+// NewConcurrentBuffer returns a new, empty ConcurrentBuffer.
+func NewConcurrentBuffer() *ConcurrentBuffer {
+	return &ConcurrentBuffer{}
+}
+
+// Log implements lager.Sink. Every entry is appended as a single line.
+func (b *ConcurrentBuffer) Log(log lager.LogFormat) {
+	b.append(append(log.ToJSON(), '\n'))
+}
+
+// Write implements io.Writer. Every call is appended atomically. Writers that
+// might split a line across several calls should use a line writer returned
+// by Writer instead.
+func (b *ConcurrentBuffer) Write(p []byte) (int, error) {
+	b.append(p)
+	return len(p), nil
+}
+
+// Writer returns an io.Writer that buffers partial lines and appends them to
+// the buffer once they are complete. Use one writer per goroutine or sink.
+// Call Flush to append a trailing partial line.
+func (b *ConcurrentBuffer) Writer() *LineWriter {
+	return &LineWriter{buffer: b}
+}
+
+// Contents implements ContentsProvider. It returns all complete lines written
+// so far.
+func (b *ConcurrentBuffer) Contents() []byte {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	end := bytes.LastIndexByte(b.contents, '\n') + 1
+	return append([]byte{}, b.contents[:end]...)
+}
+
+func (b *ConcurrentBuffer) append(p []byte) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.contents = append(b.contents, p...)
+}
+
+// LineWriter writes complete lines to a ConcurrentBuffer.
+type LineWriter struct {
+	buffer  *ConcurrentBuffer
+	lock    sync.Mutex
+	partial []byte
+}
+
+// Write implements io.Writer.
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.partial = append(w.partial, p...)
+
+	if end := bytes.LastIndexByte(w.partial, '\n') + 1; end > 0 {
+		w.buffer.append(w.partial[:end])
+		w.partial = append([]byte{}, w.partial[end:]...)
+	}
+
+	return len(p), nil
+}
+
+// Flush appends a trailing partial line, if any, to the buffer.
+func (w *LineWriter) Flush() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if len(w.partial) > 0 {
+		w.buffer.append(w.partial)
+		w.partial = nil
+	}
+}
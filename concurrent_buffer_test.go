@@ -0,0 +1,75 @@
+package glager_test
+
+import (
+	"fmt"
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("ConcurrentBuffer", func() {
+	var buffer *ConcurrentBuffer
+
+	BeforeEach(func() {
+		buffer = NewConcurrentBuffer()
+	})
+
+	It("collects entries of many goroutines without tearing lines", func() {
+		logger := lager.NewLogger("test")
+		logger.RegisterSink(buffer)
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					logger.Info("tick", lager.Data{"worker": i, "n": j})
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		Expect(buffer).To(BeNDJSON())
+		Expect(Entries(buffer)).To(HaveLen(1000))
+		Expect(CountByData(buffer, "worker")).To(HaveLen(20))
+	})
+
+	It("only returns complete lines", func() {
+		buffer.Write([]byte(`{"timestamp":"1.0","source":"test","message":"test.start","log_level":1,"data":{}}` + "\n"))
+		buffer.Write([]byte(`{"timestamp":"2.0","source":"te`))
+
+		Expect(buffer).To(BeNDJSON())
+		Expect(Entries(buffer)).To(HaveLen(1))
+	})
+
+	It("joins lines split across writes of a line writer", func() {
+		writers := []*LineWriter{buffer.Writer(), buffer.Writer()}
+		line := func(i int) string {
+			return fmt.Sprintf(`{"timestamp":"%d.0","source":"test","message":"test.tick","log_level":1,"data":{"i":%d}}`+"\n", i, i)
+		}
+
+		writers[0].Write([]byte(line(1)[:20]))
+		writers[1].Write([]byte(line(2)[:30]))
+		writers[0].Write([]byte(line(1)[20:]))
+		writers[1].Write([]byte(line(2)[30:]))
+
+		Expect(buffer).To(BeNDJSON())
+		Expect(buffer).To(ContainSequence(Info(Data("i", 1)), Info(Data("i", 2))))
+	})
+
+	It("flushes trailing partial lines", func() {
+		writer := buffer.Writer()
+		writer.Write([]byte(`{"timestamp":"1.0","source":"test","message":"test.start","log_level":1,"data":{}}`))
+		Expect(Entries(buffer)).To(BeEmpty())
+
+		writer.Flush()
+		buffer.Write([]byte("\n"))
+		Expect(Entries(buffer)).To(HaveLen(1))
+	})
+})
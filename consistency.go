@@ -0,0 +1,469 @@
+package glager
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+type sourceMatcher struct {
+	expected  string
+	source    string
+	offending *LogEntry
+}
+
+// HaveConsistentSource checks that every entry in the log shares the same
+// source. If a source is specified, every entry has to have that particular
+// source. Use it to catch code paths that construct a fresh logger with the
+// wrong component name.
+//
+// Example:
+//
+//	Expect(logger).To(HaveConsistentSource("my-component"))
+func HaveConsistentSource(source ...string) types.GomegaMatcher {
+	if len(source) > 1 {
+		panic(fmt.Errorf("HaveConsistentSource accepts at most one source. Got %d.", len(source)))
+	}
+
+	matcher := &sourceMatcher{}
+	if len(source) == 1 {
+		matcher.expected = source[0]
+	}
+
+	return matcher
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (sm *sourceMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("HaveConsistentSource", actual)
+	if err != nil {
+		return false, err
+	}
+
+	sm.source = sm.expected
+	sm.offending = nil
+
+	for i, entry := range entries {
+		if sm.source == "" {
+			sm.source = entry.Source
+		}
+
+		if entry.Source != sm.source {
+			sm.offending = &entries[i]
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (sm *sourceMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected all log entries to have source %q, found\n%s",
+		sm.source,
+		format.IndentString(sm.offending.String(), 1),
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (sm *sourceMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected log entries not to have the consistent source %q", sm.source)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (sm *sourceMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+type allEntriesDataMatcher struct {
+	data      logEntryData
+	offending *LogEntry
+	mismatch  string
+}
+
+// HaveDataOnAllEntries checks that every entry in the log contains the given
+// data key with the given value. Values are matched just like the values of
+// the Data option. Use it to verify that data attached with lager's WithData
+// or Session is inherited by all child loggers. Combine it with InSession to
+// only check the entries of a given session.
+//
+// Example:
+//
+//	Expect(logger).To(InSession("3", HaveDataOnAllEntries("request_id", "abc")))
+func HaveDataOnAllEntries(key string, value interface{}) types.GomegaMatcher {
+	return &allEntriesDataMatcher{
+		data: logEntryData{key: value},
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (am *allEntriesDataMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("HaveDataOnAllEntries", actual)
+	if err != nil {
+		return false, err
+	}
+
+	am.offending = nil
+	am.mismatch = ""
+
+	for i, entry := range entries {
+		mismatch, err := entry.logData().mismatch(am.data)
+		if err != nil {
+			return false, err
+		}
+
+		if mismatch != "" {
+			am.offending = &entries[i]
+			am.mismatch = mismatch
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (am *allEntriesDataMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected all log entries to contain data\n%s\nfound (%s)\n%s",
+		format.Object(am.data, 1),
+		am.mismatch,
+		format.IndentString(am.offending.String(), 1),
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (am *allEntriesDataMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected at least one log entry not to contain data\n%s",
+		format.Object(am.data, 1),
+	)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (am *allEntriesDataMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+// AnyValue can be used with HaveCorrelatedData to only check that all entries
+// share the same value, without matching the value itself.
+var AnyValue interface{} = nil
+
+type correlationMatcher struct {
+	key       string
+	expected  interface{}
+	filters   []logEntry
+	value     interface{}
+	offending *LogEntry
+	reason    string
+}
+
+// HaveCorrelatedData checks that all entries matching any of the given entries
+// carry the same value for the given correlation key, e.g. "request_id". If
+// the expected value is not AnyValue, the shared value also has to match the
+// expected one. If no entries are given, all entries in the log are checked.
+// The matcher fails if no entries are checked at all. Use it to test that
+// tracing information is propagated into the logs.
+//
+// Example:
+//
+//	Expect(logger).To(HaveCorrelatedData("request_id", requestID,
+//	  Info(Source("api")),
+//	  Info(Source("worker")),
+//	))
+func HaveCorrelatedData(key string, expected interface{}, entries ...logEntry) types.GomegaMatcher {
+
+	return &correlationMatcher{
+		key:      key,
+		expected: expected,
+		filters:  entries,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (cm *correlationMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("HaveCorrelatedData", actual)
+	if err != nil {
+		return false, err
+	}
+
+	entries, err = entries.filter(cm.filters)
+	if err != nil {
+		return false, err
+	}
+
+	cm.value = nil
+	cm.offending = nil
+	cm.reason = ""
+
+	if len(entries) == 0 {
+		cm.reason = "no matching entries found"
+		return false, nil
+	}
+
+	expected := logEntryData{}
+	if cm.expected != AnyValue {
+		expected[cm.key] = cm.expected
+	}
+
+	for i, entry := range entries {
+		if len(expected) == 0 {
+			value, found := entry.Data[cm.key]
+			if !found {
+				cm.offending = &entries[i]
+				cm.reason = fmt.Sprintf("data.%s: missing", cm.key)
+				return false, nil
+			}
+			cm.value = value
+			expected[cm.key] = value
+		}
+
+		mismatch, err := entry.logData().mismatch(expected)
+		if err != nil {
+			return false, err
+		}
+
+		if mismatch != "" {
+			cm.offending = &entries[i]
+			cm.reason = mismatch
+			return false, nil
+		}
+	}
+
+	cm.value = entries[0].Data[cm.key]
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (cm *correlationMatcher) FailureMessage(actual interface{}) (message string) {
+	if cm.offending == nil {
+		return fmt.Sprintf("Expected entries correlated by data key %q, but %s", cm.key, cm.reason)
+	}
+
+	return fmt.Sprintf(
+		"Expected entries correlated by data key %q, found (%s)\n%s",
+		cm.key,
+		cm.reason,
+		format.IndentString(cm.offending.String(), 1),
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (cm *correlationMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected entries not to be correlated by data key %q, all of them share value %s",
+		cm.key,
+		format.Object(cm.value, 0),
+	)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (cm *correlationMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+type commonDataMatcher struct {
+	key      string
+	value    interface{}
+	expected []logEntry
+	matcher  types.GomegaMatcher
+}
+
+// HaveCommonData checks if the specified entries appear inside the log in the
+// right order, just like ContainSequence, and requires every matched entry to
+// carry the given value for the given data key. This saves repeating the same
+// Data option on every step of the sequence. If the value is AnyValue, the
+// matched entries have to share the same value for the key, whatever that
+// value is.
+//
+// Example:
+//
+//	Expect(log).To(HaveCommonData("task", AnyValue,
+//	  Info(Data("event", "start")),
+//	  Info(Data("event", "progress")),
+//	  Info(Data("event", "done")),
+//	))
+func HaveCommonData(key string, value interface{}, expectedSequence ...SequenceEntry) types.GomegaMatcher {
+	return &commonDataMatcher{
+		key:      key,
+		value:    value,
+		expected: sequenceOf(expectedSequence),
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (cm *commonDataMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("HaveCommonData", actual)
+	if err != nil {
+		return false, err
+	}
+
+	values := []interface{}{cm.value}
+	if cm.value == AnyValue {
+		values, err = entries.distinctValues(cm.key)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	cm.matcher = nil
+
+	for _, value := range values {
+		cm.matcher = &logMatcher{
+			expected: compileSequence(withCommonData(cm.expected, cm.key, value)),
+		}
+
+		success, err := cm.matcher.Match(entries)
+		if err != nil || success {
+			return success, err
+		}
+	}
+
+	return false, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (cm *commonDataMatcher) FailureMessage(actual interface{}) (message string) {
+	if cm.matcher == nil {
+		return fmt.Sprintf("Expected log entries with data key %q, found none", cm.key)
+	}
+
+	return fmt.Sprintf("With common data key %q:\n%s", cm.key, cm.matcher.FailureMessage(actual))
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (cm *commonDataMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("With common data key %q:\n%s", cm.key, cm.matcher.NegatedFailureMessage(actual))
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (cm *commonDataMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+func withCommonData(entries []logEntry, key string, value interface{}) []logEntry {
+	result := make([]logEntry, len(entries))
+	for i, entry := range entries {
+		if len(entry.alternatives) > 0 {
+			entry.alternatives = withCommonData(entry.alternatives, key, value)
+			result[i] = entry
+			continue
+		}
+
+		data := lager.Data{key: value}
+		for k, v := range entry.Data {
+			data[k] = v
+		}
+
+		entry.Data = data
+		result[i] = entry
+	}
+	return result
+}
+
+func (entries logEntries) distinctValues(key string) ([]interface{}, error) {
+	values := []interface{}{}
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		value, found := entry.Data[key]
+		if !found {
+			continue
+		}
+
+		encoded, err := canonicalJSON(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if !seen[string(encoded)] {
+			seen[string(encoded)] = true
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+type cardinalityMatcher struct {
+	key     string
+	count   interface{}
+	filters []logEntry
+	values  []interface{}
+}
+
+// HaveDataCardinality checks the number of distinct values of the given data
+// key across all entries matching any of the given entries. The count can be
+// an int or a Gomega matcher, e.g. BeNumerically(">=", 10). Values are
+// compared by their JSON representation. Entries without the key are ignored.
+// If no entries are given, all entries in the log are checked. Use it to
+// verify fan-out behavior, e.g. that work has been spread across all workers.
+//
+// Example:
+//
+//	Expect(logger).To(HaveDataCardinality("worker_id", 10,
+//	  Info(Action("pool.job")),
+//	))
+func HaveDataCardinality(key string, count interface{}, entries ...logEntry) types.GomegaMatcher {
+
+	return &cardinalityMatcher{
+		key:     key,
+		count:   count,
+		filters: entries,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (cm *cardinalityMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("HaveDataCardinality", actual)
+	if err != nil {
+		return false, err
+	}
+
+	entries, err = entries.filter(cm.filters)
+	if err != nil {
+		return false, err
+	}
+
+	cm.values, err = entries.distinctValues(cm.key)
+	if err != nil {
+		return false, err
+	}
+
+	switch count := cm.count.(type) {
+	case int:
+		return len(cm.values) == count, nil
+	case types.GomegaMatcher:
+		return count.Match(len(cm.values))
+	default:
+		return false, fmt.Errorf("HaveDataCardinality must be passed an int or a matcher as count. Got:\n%s", format.Object(cm.count, 1))
+	}
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (cm *cardinalityMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected the number of distinct values of data key %q to be %s, found %d\n%s",
+		cm.key,
+		describeValue(cm.count),
+		len(cm.values),
+		format.Object(cm.values, 1),
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (cm *cardinalityMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected the number of distinct values of data key %q not to be %s, found %d\n%s",
+		cm.key,
+		describeValue(cm.count),
+		len(cm.values),
+		format.Object(cm.values, 1),
+	)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (cm *cardinalityMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
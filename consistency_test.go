@@ -0,0 +1,226 @@
+package glager_test
+
+import (
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("consistency matchers", func() {
+	var (
+		buffer *gbytes.Buffer
+		logger lager.Logger
+	)
+
+	BeforeEach(func() {
+		buffer = gbytes.NewBuffer()
+		logger = lager.NewLogger("component")
+		logger.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+	})
+
+	Describe(".HaveConsistentSource", func() {
+		BeforeEach(func() {
+			logger.Info("first")
+			logger.Session("session").Info("second")
+		})
+
+		It("matches a log with a single source", func() {
+			Expect(buffer).To(HaveConsistentSource())
+			Expect(buffer).To(HaveConsistentSource("component"))
+		})
+
+		It("does not match a log with an unexpected source", func() {
+			matcher := HaveConsistentSource("other")
+			Expect(matcher.Match(buffer)).To(BeFalse())
+			Expect(matcher.FailureMessage(buffer)).To(ContainSubstring(`to have source "other"`))
+		})
+
+		It("does not match a log with different sources", func() {
+			wrong := lager.NewLogger("wrong-component")
+			wrong.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+			wrong.Info("third")
+
+			matcher := HaveConsistentSource()
+			Expect(matcher.Match(buffer)).To(BeFalse())
+			Expect(matcher.FailureMessage(buffer)).To(ContainSubstring(`to have source "component"`))
+			Expect(matcher.FailureMessage(buffer)).To(ContainSubstring("wrong-component.third"))
+		})
+
+		It("panics if more than one source is specified", func() {
+			Expect(func() { HaveConsistentSource("a", "b") }).To(Panic())
+		})
+	})
+
+	Describe(".HaveDataOnAllEntries", func() {
+		BeforeEach(func() {
+			request := logger.Session("request", lager.Data{"request_id": "abc"})
+			request.Info("start")
+			request.WithData(lager.Data{"user": "admin"}).Session("db").Info("query")
+			request.Info("done")
+		})
+
+		It("matches if all entries contain the data", func() {
+			Expect(buffer).To(HaveDataOnAllEntries("request_id", "abc"))
+			Expect(buffer).To(HaveDataOnAllEntries("request_id", HaveLen(3)))
+		})
+
+		It("does not match if an entry does not contain the data", func() {
+			logger.Info("unrelated")
+
+			matcher := HaveDataOnAllEntries("request_id", "abc")
+			Expect(matcher.Match(buffer)).To(BeFalse())
+			Expect(matcher.FailureMessage(buffer)).To(ContainSubstring("data.request_id: missing"))
+			Expect(matcher.FailureMessage(buffer)).To(ContainSubstring("component.unrelated"))
+		})
+
+		It("does not match if an entry contains a different value", func() {
+			logger.Session("request", lager.Data{"request_id": "def"}).Info("start")
+
+			matcher := HaveDataOnAllEntries("request_id", "abc")
+			Expect(matcher.Match(buffer)).To(BeFalse())
+			Expect(matcher.FailureMessage(buffer)).To(ContainSubstring(`data.request_id: expected "abc", got "def"`))
+		})
+
+		It("can be scoped to a session", func() {
+			logger.Info("unrelated")
+			logger.Session("request", lager.Data{"request_id": "def"}).Info("start")
+
+			Expect(buffer).To(InSession("1", HaveDataOnAllEntries("request_id", "abc")))
+			Expect(buffer).To(InSession("1.1", HaveDataOnAllEntries("user", "admin")))
+			Expect(buffer).ToNot(InSession("1", HaveDataOnAllEntries("user", "admin")))
+		})
+	})
+
+	Describe(".HaveCorrelatedData", func() {
+		BeforeEach(func() {
+			api := lager.NewLogger("api")
+			api.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+			worker := lager.NewLogger("worker")
+			worker.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+
+			api.Info("request", lager.Data{"request_id": "abc"})
+			logger.Info("unrelated")
+			worker.Info("job", lager.Data{"request_id": "abc"})
+		})
+
+		It("matches entries sharing the same value", func() {
+			Expect(buffer).To(HaveCorrelatedData("request_id", AnyValue, Info(Source("api")), Info(Source("worker"))))
+		})
+
+		It("matches entries sharing the expected value", func() {
+			Expect(buffer).To(HaveCorrelatedData("request_id", "abc", Info(Source("api")), Info(Source("worker"))))
+			Expect(buffer).ToNot(HaveCorrelatedData("request_id", "def", Info(Source("api")), Info(Source("worker"))))
+		})
+
+		It("checks all entries if no filter is given", func() {
+			matcher := HaveCorrelatedData("request_id", AnyValue)
+			Expect(matcher.Match(buffer)).To(BeFalse())
+			Expect(matcher.FailureMessage(buffer)).To(ContainSubstring("data.request_id: missing"))
+			Expect(matcher.FailureMessage(buffer)).To(ContainSubstring("component.unrelated"))
+		})
+
+		It("does not match entries with different values", func() {
+			logger.Session("job", lager.Data{"request_id": "def"}).Info("job")
+
+			matcher := HaveCorrelatedData("request_id", AnyValue, Info(Action("api.request")), Info(Action("component.job.job")))
+			Expect(matcher.Match(buffer)).To(BeFalse())
+			Expect(matcher.FailureMessage(buffer)).To(ContainSubstring(`data.request_id: expected "abc", got "def"`))
+		})
+
+		It("does not match if no entries match the filter", func() {
+			matcher := HaveCorrelatedData("request_id", AnyValue, Info(Source("missing")))
+			Expect(matcher.Match(buffer)).To(BeFalse())
+			Expect(matcher.FailureMessage(buffer)).To(ContainSubstring("no matching entries found"))
+		})
+	})
+
+	Describe(".HaveCommonData", func() {
+		BeforeEach(func() {
+			one := logger.WithData(lager.Data{"task": "one"})
+			two := logger.WithData(lager.Data{"task": "two"})
+
+			one.Info("job", lager.Data{"event": "start"})
+			two.Info("job", lager.Data{"event": "start"})
+			two.Info("job", lager.Data{"event": "done"})
+		})
+
+		It("matches a sequence sharing the given data", func() {
+			Expect(buffer).To(HaveCommonData("task", "two",
+				Info(Data("event", "start")),
+				Info(Data("event", "done")),
+			))
+		})
+
+		It("does not match a sequence with different data", func() {
+			matcher := HaveCommonData("task", "one",
+				Info(Data("event", "start")),
+				Info(Data("event", "done")),
+			)
+
+			Expect(matcher.Match(buffer)).To(BeFalse())
+			Expect(matcher.FailureMessage(buffer)).To(ContainSubstring(`With common data key "task"`))
+		})
+
+		It("matches a sequence sharing any value", func() {
+			Expect(buffer).To(HaveCommonData("task", AnyValue,
+				Info(Data("event", "start")),
+				Info(Data("event", "done")),
+			))
+		})
+
+		It("does not match a sequence spanning different values", func() {
+			logger.WithData(lager.Data{"task": "three"}).Info("job", lager.Data{"event": "start"})
+			logger.WithData(lager.Data{"task": "one"}).Info("job", lager.Data{"event": "progress"})
+
+			Expect(buffer).ToNot(HaveCommonData("task", AnyValue,
+				Info(Data("event", "start")),
+				Info(Data("event", "progress")),
+				Info(Data("event", "done")),
+			))
+		})
+
+		It("does not match if no entry carries the key", func() {
+			matcher := HaveCommonData("missing", AnyValue, Info())
+			Expect(matcher.Match(buffer)).To(BeFalse())
+			Expect(matcher.FailureMessage(buffer)).To(ContainSubstring("found none"))
+		})
+	})
+
+	Describe(".HaveDataCardinality", func() {
+		BeforeEach(func() {
+			for i := 0; i < 6; i++ {
+				logger.Info("job", lager.Data{"worker_id": i % 3})
+			}
+			logger.Info("idle", lager.Data{"worker_id": 7})
+			logger.Info("job")
+		})
+
+		It("matches the number of distinct values of matching entries", func() {
+			Expect(buffer).To(HaveDataCardinality("worker_id", 3, Info(Action("component.job"))))
+			Expect(buffer).ToNot(HaveDataCardinality("worker_id", 4, Info(Action("component.job"))))
+		})
+
+		It("checks all entries if no entries are given", func() {
+			Expect(buffer).To(HaveDataCardinality("worker_id", 4))
+		})
+
+		It("accepts a matcher as count", func() {
+			Expect(buffer).To(HaveDataCardinality("worker_id", BeNumerically(">=", 3)))
+		})
+
+		It("explains failures", func() {
+			matcher := HaveDataCardinality("worker_id", 10)
+			Expect(matcher.Match(buffer)).To(BeFalse())
+			Expect(matcher.FailureMessage(buffer)).To(HavePrefix(`Expected the number of distinct values of data key "worker_id" to be 10, found 4`))
+		})
+
+		It("returns an error for an invalid count", func() {
+			_, err := HaveDataCardinality("worker_id", "ten").Match(buffer)
+			Expect(err).To(MatchError(HavePrefix("HaveDataCardinality must be passed an int or a matcher as count.")))
+		})
+	})
+})
@@ -0,0 +1,66 @@
+package glager
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/onsi/gomega/gbytes"
+)
+
+// Cursor is a consuming view of a log. Every time the ContainSequence or
+// HaveLogged matcher succeeds against a cursor, the cursor advances past the
+// last matched entry. Subsequent assertions only see the entries that have
+// been logged after that point. Use it to assert strictly increasing progress
+// without keeping track of offsets manually.
+//
+// Cursor implements ContentsProvider, all other matchers can be used with it
+// as well but do not advance it.
+type Cursor struct {
+	provider gbytes.BufferProvider
+	offset   int
+	lock     sync.Mutex
+}
+
+// NewCursor returns a new Cursor positioned at the beginning of the log of the
+// given BufferProvider, e.g. a TestLogger.
+//
+// Example:
+//
+//	cursor := NewCursor(logger)
+//	Expect(cursor).To(HaveLogged(Info(Action("test.start"))))
+//	Expect(cursor).To(HaveLogged(Info(Action("test.done"))))
+//	Expect(cursor).ToNot(HaveLogged(Info(Action("test.start"))))
+func NewCursor(provider gbytes.BufferProvider) *Cursor {
+	return &Cursor{
+		provider: provider,
+	}
+}
+
+// Contents implements ContentsProvider. It returns the contents of the log
+// following the current position of the cursor.
+func (c *Cursor) Contents() []byte {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.provider.Buffer().Contents()[c.offset:]
+}
+
+// skip advances the cursor past the given number of entries.
+func (c *Cursor) skip(entries int) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	contents := c.provider.Buffer().Contents()[c.offset:]
+
+	_, offsets, err := decodeEntries(bytes.NewReader(contents))
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return err
+	}
+
+	if entries > 0 && entries <= len(offsets) {
+		c.offset += int(offsets[entries-1])
+	}
+
+	return nil
+}
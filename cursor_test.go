@@ -0,0 +1,55 @@
+package glager_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("Cursor", func() {
+	var (
+		logger *TestLogger
+		cursor *Cursor
+	)
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		cursor = NewCursor(logger)
+
+		logger.Info("start")
+		logger.Info("progress")
+		logger.Info("done")
+	})
+
+	It("advances past matched entries", func() {
+		Expect(cursor).To(HaveLogged(Info(Action("test.progress"))))
+		Expect(cursor).ToNot(HaveLogged(Info(Action("test.start"))))
+		Expect(cursor).To(HaveLogged(Info(Action("test.done"))))
+		Expect(cursor).ToNot(HaveLogged(Info()))
+	})
+
+	It("does not advance on failed matches", func() {
+		Expect(cursor).ToNot(HaveLogged(Info(Action("test.start")), Info(Action("test.missing"))))
+		Expect(cursor).To(HaveLogged(Info(Action("test.start"))))
+	})
+
+	It("sees entries logged after matching", func() {
+		Expect(cursor).To(HaveLogged(Info(Action("test.done"))))
+
+		logger.Info("restart")
+		Expect(cursor).To(HaveLogged(Info(Action("test.restart"))))
+	})
+
+	It("does not advance when using other matchers", func() {
+		Expect(cursor).To(HaveNoEntriesBelow(0))
+		Expect(Entries(cursor)).To(HaveLen(3))
+		Expect(cursor).To(HaveLogged(Info(Action("test.start"))))
+		Expect(Entries(cursor)).To(HaveLen(2))
+	})
+
+	It("does not affect the underlying logger", func() {
+		Expect(cursor).To(HaveLogged(Info(Action("test.done"))))
+		Expect(logger).To(HaveLogged(Info(Action("test.start"))))
+	})
+})
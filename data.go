@@ -0,0 +1,392 @@
+package glager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/onsi/gomega/types"
+)
+
+// mismatch returns a description of the first difference between the expected
+// and the actual data, or an empty string if the actual data contains all of
+// the expected data. Only the top-level keys and sub maps are matched
+// partially, other nested maps and slices have to be deeply equal. Gomega
+// matchers can be used as expected values at any level.
+func (actual logEntryData) mismatch(expected logEntryData) (string, error) {
+	for _, key := range sortedKeys(expected) {
+		path := "data." + key
+
+		actualVal, found := actual[key]
+		if !found {
+			return fmt.Sprintf("%s: missing", path), nil
+		}
+
+		mismatch, err := deepMismatch(path, expected[key], actualVal)
+		if err != nil || mismatch != "" {
+			return mismatch, err
+		}
+	}
+
+	return "", nil
+}
+
+// mismatches describes every top-level key of the expected data that is
+// missing from or does not match the actual data, i.e. missing keys as well as
+// differing values and types. For each key, only its first difference is
+// described.
+func (actual logEntryData) mismatches(expected logEntryData) ([]string, error) {
+	var mismatches []string
+
+	for _, key := range sortedKeys(expected) {
+		mismatch, err := actual.mismatch(logEntryData{key: expected[key]})
+		if err != nil {
+			return nil, err
+		}
+
+		if mismatch != "" {
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+
+	return mismatches, nil
+}
+
+// dataMismatches describes why the data of the given entries does not match the
+// data of the expected entry, key by key. Only entries that match the expected entry in
+// every other aspect are taken into account. The offset is added to the
+// reported entry indices. If a differ is given, it renders the mismatches.
+func (entries logEntries) dataMismatches(expected logEntry, offset int, differ Differ) ([]string, error) {
+	var mismatches []string
+
+	if len(expected.alternatives) > 0 {
+		return nil, nil
+	}
+
+	for i, actual := range entries {
+		expected, actual := ignoreData(expected, actual)
+
+		if !expected.matchesLevel(actual.LogLevel) ||
+			expected.Source != "" && actual.Source != expected.Source ||
+			expected.Message != "" && actual.Message != expected.Message {
+			continue
+		}
+
+		diffs, err := actual.logData().mismatches(expected.logData())
+		if err != nil {
+			return nil, err
+		}
+
+		if len(diffs) == 0 {
+			continue
+		}
+
+		if differ != nil {
+			diffs = []string{differ.Diff(expected.Data, actual.Data)}
+		}
+
+		for _, diff := range diffs {
+			mismatches = append(mismatches, fmt.Sprintf("entry %d: %s", offset+i, diff))
+		}
+	}
+
+	return mismatches, nil
+}
+
+// deepMismatch compares an expected value with an actual value that has been
+// decoded from JSON. It returns a description of the first difference,
+// prefixed with its path, e.g. `data.request.headers[2].name`.
+func deepMismatch(path string, expected, actual interface{}) (string, error) {
+	if matcher, ok := expected.(types.GomegaMatcher); ok {
+		matches, err := matcher.Match(actual)
+		if err != nil || matches {
+			return "", err
+		}
+		return fmt.Sprintf("%s: %s", path, matcher.FailureMessage(actual)), nil
+	}
+
+	// most expected values are plain strings, numbers and booleans, spare
+	// encoding them if they are equal to the actual value
+	if scalarEqual(expected, actual) {
+		return "", nil
+	}
+
+	if sub, ok := expected.(subMap); ok {
+		return mapMismatch(path, reflect.ValueOf(sub), actual, true)
+	}
+
+	value := reflect.ValueOf(expected)
+
+	switch value.Kind() {
+	case reflect.Map:
+		if !value.IsNil() && value.Type().Key().Kind() == reflect.String {
+			return mapMismatch(path, value, actual, false)
+		}
+	case reflect.Slice:
+		if !value.IsNil() && value.Type().Elem().Kind() != reflect.Uint8 {
+			return sliceMismatch(path, value, actual)
+		}
+	case reflect.Array:
+		return sliceMismatch(path, value, actual)
+	}
+
+	expected, err := canonical(expected)
+	if err != nil {
+		return "", err
+	}
+
+	expectedJSON, err := canonicalJSON(expected)
+	if err != nil {
+		return "", err
+	}
+
+	actualJSON, err := canonicalJSON(actual)
+	if err != nil {
+		return "", err
+	}
+
+	if string(expectedJSON) == string(actualJSON) {
+		return "", nil
+	}
+
+	// JSON types are never coerced, e.g. 1 does not match "1"
+	if expectedType, actualType := jsonType(expected), jsonType(actual); expectedType != actualType {
+		return fmt.Sprintf(
+			"%s: type mismatch, expected %s, got %s",
+			path, describeJSON(expectedType, expectedJSON), describeJSON(actualType, actualJSON),
+		), nil
+	}
+
+	return fmt.Sprintf("%s: expected %s, got %s", path, expectedJSON, actualJSON), nil
+}
+
+// scalarEqual reports whether the expected value is a string, number, boolean
+// or nil that is equal to the actual value that has been decoded from JSON. It
+// reports false for all other values, e.g. named types, which might implement
+// json.Marshaler.
+// Integers beyond the precision of float64 are left to the JSON comparison,
+// which compares them exactly.
+func scalarEqual(expected, actual interface{}) bool {
+	switch e := expected.(type) {
+	case nil:
+		return actual == nil
+	case string, bool, float64:
+		return actual == e
+	case int:
+		return exactInt(int64(e)) && actual == float64(e)
+	case int64:
+		return exactInt(e) && actual == float64(e)
+	case int32:
+		return actual == float64(e)
+	case uint:
+		return e < maxExactInt && actual == float64(e)
+	case uint64:
+		return e < maxExactInt && actual == float64(e)
+	case uint32:
+		return actual == float64(e)
+	default:
+		return false
+	}
+}
+
+// maxExactInt bounds the integers that are represented exactly by a float64.
+const maxExactInt = 1 << 53
+
+func exactInt(i int64) bool {
+	return i > -maxExactInt && i < maxExactInt
+}
+
+// decodeExact decodes the given JSON like json.Unmarshal does, except for
+// integers that cannot be represented exactly by a float64, e.g. large IDs.
+// They are retained as json.Number, so that they are neither rounded nor
+// considered equal to neighbouring integers.
+func decodeExact(encoded []byte) (interface{}, error) {
+	var decoded interface{}
+
+	if !hasLongInteger(encoded) {
+		err := json.Unmarshal(encoded, &decoded)
+		return decoded, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	return exactNumbers(decoded), nil
+}
+
+// hasLongInteger reports whether the given JSON might contain an integer that
+// cannot be represented exactly by a float64, i.e. whether it contains 16 or
+// more consecutive digits.
+func hasLongInteger(encoded []byte) bool {
+	digits := 0
+	for _, b := range encoded {
+		if b < '0' || b > '9' {
+			digits = 0
+			continue
+		}
+
+		if digits++; digits >= 16 {
+			return true
+		}
+	}
+	return false
+}
+
+// exactNumbers converts the json.Numbers of a value decoded using UseNumber to
+// float64, except for integers that a float64 cannot represent exactly.
+func exactNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil || math.Abs(f) >= maxExactInt && !strings.ContainsAny(string(v), ".eE") {
+			return v
+		}
+		return f
+	case map[string]interface{}:
+		for key, nested := range v {
+			v[key] = exactNumbers(nested)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = exactNumbers(nested)
+		}
+	}
+	return value
+}
+
+// mapMismatch compares an expected map with an actual object. Unless partial
+// is set, the actual object must not contain any additional keys.
+func mapMismatch(path string, expected reflect.Value, actual interface{}, partial bool) (string, error) {
+	actualMap, ok := actual.(map[string]interface{})
+	if !ok {
+		return fmt.Sprintf("%s: expected an object, got %s", path, jsonType(actual)), nil
+	}
+
+	keys := make([]string, 0, expected.Len())
+	for _, key := range expected.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		actualVal, found := actualMap[key]
+		if !found {
+			return fmt.Sprintf("%s.%s: missing", path, key), nil
+		}
+
+		expectedVal := expected.MapIndex(reflect.ValueOf(key).Convert(expected.Type().Key())).Interface()
+
+		mismatch, err := deepMismatch(path+"."+key, expectedVal, actualVal)
+		if err != nil || mismatch != "" {
+			return mismatch, err
+		}
+	}
+
+	if partial {
+		return "", nil
+	}
+
+	for _, key := range sortedKeys(actualMap) {
+		if !expected.MapIndex(reflect.ValueOf(key).Convert(expected.Type().Key())).IsValid() {
+			return fmt.Sprintf("%s.%s: unexpected", path, key), nil
+		}
+	}
+
+	return "", nil
+}
+
+func sliceMismatch(path string, expected reflect.Value, actual interface{}) (string, error) {
+	actualSlice, ok := actual.([]interface{})
+	if !ok {
+		return fmt.Sprintf("%s: expected an array, got %s", path, jsonType(actual)), nil
+	}
+
+	if expected.Len() != len(actualSlice) {
+		return fmt.Sprintf("%s: expected %d elements, got %d", path, expected.Len(), len(actualSlice)), nil
+	}
+
+	for i := range actualSlice {
+		elemPath := fmt.Sprintf("%s[%d]", path, i)
+
+		mismatch, err := deepMismatch(elemPath, expected.Index(i).Interface(), actualSlice[i])
+		if err != nil || mismatch != "" {
+			return mismatch, err
+		}
+	}
+
+	return "", nil
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64, json.Number:
+		return "a number"
+	case string:
+		return "a string"
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func describeJSON(jsonType string, encoded []byte) string {
+	if jsonType == "null" {
+		return jsonType
+	}
+	return fmt.Sprintf("%s %s", jsonType, encoded)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// dataPairs converts a map with string keys or a struct, which has been passed
+// to Data as single argument, into an alternating sequence of keys and values.
+// Structs are encoded as JSON, i.e. their json tags are honored.
+func dataPairs(value interface{}) ([]interface{}, bool) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		pairs := make([]interface{}, 0, 2*v.Len())
+		for _, key := range v.MapKeys() {
+			pairs = append(pairs, key.String(), v.MapIndex(key).Interface())
+		}
+		return pairs, true
+	case v.Kind() == reflect.Struct:
+		decoded, err := canonical(v.Interface())
+		if err != nil {
+			panic(err)
+		}
+
+		fields, ok := decoded.(map[string]interface{})
+		if !ok {
+			panic(fmt.Errorf("Invalid type for data. Want a struct that is encoded as JSON object. Got %T.", value))
+		}
+		return dataPairs(fields)
+	default:
+		return nil, false
+	}
+}
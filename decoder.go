@@ -0,0 +1,64 @@
+package glager
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// EntryDecoder decodes a single raw line of a log written in some other
+// format, e.g. a proprietary one, into a log entry. Use it to extend glager to
+// formats that are not easily converted line by line into lager's JSON
+// format, see Format.
+type EntryDecoder interface {
+	// Decode returns the entry encoded by the given line, which does not
+	// include the trailing newline. Blank lines are never passed. It returns
+	// ErrSkipLine if the line does not encode an entry and is to be skipped.
+	Decode(line []byte) (LogEntry, error)
+}
+
+// EntryDecoderFunc is an adapter that allows the use of ordinary functions as
+// EntryDecoder.
+type EntryDecoderFunc func(line []byte) (LogEntry, error)
+
+// Decode calls f(line).
+func (f EntryDecoderFunc) Decode(line []byte) (LogEntry, error) {
+	return f(line)
+}
+
+// ErrSkipLine is returned by an EntryDecoder to skip a line that does not
+// encode an entry.
+var ErrSkipLine = errors.New("Skip line.")
+
+// DecoderFormat returns a Format that decodes lines using the given decoder.
+// Use it to pass the decoder wherever a Format is expected.
+//
+// Example:
+//
+//	glager.WithFormat(glager.DecoderFormat(myDecoder), buffer)
+func DecoderFormat(decoder EntryDecoder) Format {
+	return func(line []byte) ([]byte, error) {
+		entry, err := decoder.Decode(line)
+		if err == ErrSkipLine {
+			return nil, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(entry)
+	}
+}
+
+// WithDecoder decodes a log line by line using the given decoder, so that it
+// can be passed to any of the matchers. It is a shorthand for
+// WithFormat(DecoderFormat(decoder), log).
+//
+// Example:
+//
+//	Expect(WithDecoder(myDecoder, buffer)).To(ContainSequence(
+//	  Info(Message("server started")),
+//	))
+func WithDecoder(decoder EntryDecoder, log interface{}) formattedLog {
+	return WithFormat(DecoderFormat(decoder), log)
+}
@@ -0,0 +1,72 @@
+package glager_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("EntryDecoder", func() {
+	// pipeDecoder decodes lines like "INFO|api|server started|port=8080".
+	pipeDecoder := EntryDecoderFunc(func(line []byte) (LogEntry, error) {
+		if bytes.HasPrefix(line, []byte("#")) {
+			return LogEntry{}, ErrSkipLine
+		}
+
+		fields := strings.Split(string(line), "|")
+		if len(fields) != 4 {
+			return LogEntry{}, errors.New("invalid line")
+		}
+
+		level, err := ParseLevel(fields[0])
+		if err != nil {
+			return LogEntry{}, err
+		}
+
+		entry := LogEntry{
+			LogLevel: level.LogLevel(),
+			Source:   fields[1],
+			Message:  fields[2],
+			Data:     lager.Data{},
+		}
+
+		if kv := strings.SplitN(fields[3], "=", 2); len(kv) == 2 {
+			entry.Data[kv[0]] = kv[1]
+		}
+
+		return entry, nil
+	})
+
+	const log = `# started by test
+INFO|api|server started|port=8080
+
+ERROR|api|request failed|error=boom
+`
+
+	Describe(".WithDecoder", func() {
+		It("decodes the log using the decoder", func() {
+			Expect(WithDecoder(pipeDecoder, strings.NewReader(log))).To(ContainExactSequence(
+				Info(Source("api"), Message("server started"), Data("port", "8080")),
+				Error(errors.New("boom"), Source("api"), Message("request failed")),
+			))
+		})
+
+		It("returns the errors of the decoder", func() {
+			_, err := ContainSequence(Info()).Match(WithDecoder(pipeDecoder, strings.NewReader("INFO|api\n")))
+			Expect(err).To(MatchError(ContainSubstring("invalid line")))
+		})
+	})
+
+	Describe(".DecoderFormat", func() {
+		It("can be used as format", func() {
+			Expect(WithFormat(DecoderFormat(pipeDecoder), strings.NewReader(log))).To(ContainSequence(Error(AnyErr, Message("request failed"))))
+		})
+	})
+})
@@ -0,0 +1,53 @@
+package glager
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/onsi/gomega/types"
+)
+
+// Differ renders the difference between the expected and the actual data of a
+// log entry. It is used to describe data mismatches in failure messages.
+type Differ interface {
+	// Diff returns a description of the difference between the expected and
+	// the actual data. The expected data is passed as specified, i.e. values
+	// can be Gomega matchers. The actual data has been decoded from JSON and
+	// contains all keys of the actual entry, not only the expected ones.
+	Diff(expected, actual lager.Data) string
+}
+
+// DifferFunc is an adapter that allows the use of ordinary functions as
+// Differ.
+type DifferFunc func(expected, actual lager.Data) string
+
+// Diff calls f(expected, actual).
+func (f DifferFunc) Diff(expected, actual lager.Data) string {
+	return f(expected, actual)
+}
+
+// WithDiffer returns a copy of the given ContainSequence or HaveLogged matcher
+// that renders data mismatches in its failure messages using the given differ.
+// By default, every mismatching key is reported with the path and the values
+// of its first difference, e.g. `data.count: expected 1, got 2`. Plug in your
+// own Differ to use your preferred diff tooling. It panics for any other
+// matcher.
+//
+// Example:
+//
+//	differ := glager.DifferFunc(func(expected, actual lager.Data) string {
+//	  return cmp.Diff(expected, actual)
+//	})
+//	Expect(log).To(glager.WithDiffer(differ, ContainSequence(
+//	  Info(Data("count", 1)),
+//	)))
+func WithDiffer(differ Differ, matcher types.GomegaMatcher) types.GomegaMatcher {
+	lm, ok := matcher.(*logMatcher)
+	if !ok {
+		panic(fmt.Errorf("WithDiffer must be passed a ContainSequence or HaveLogged matcher. Got %T.", matcher))
+	}
+
+	configured := *lm
+	configured.differ = differ
+	return &configured
+}
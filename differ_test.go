@@ -0,0 +1,63 @@
+package glager_test
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".WithDiffer", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		logger.Info("start", lager.Data{"count": 2, "more": "stuff"})
+	})
+
+	failureMessage := func(differ Differ) string {
+		matcher := ContainSequence(Info(Data("count", 1)))
+		if differ != nil {
+			matcher = WithDiffer(differ, matcher)
+		}
+		Expect(matcher.Match(logger)).To(BeFalse())
+		return matcher.FailureMessage(logger)
+	}
+
+	It("reports the first difference by default", func() {
+		Expect(failureMessage(nil)).To(ContainSubstring("entry 0: data.count: expected 1, got 2"))
+	})
+
+	It("renders mismatches using the given differ", func() {
+		message := failureMessage(DifferFunc(func(expected, actual lager.Data) string {
+			return fmt.Sprintf("custom diff of %d and %d keys", len(expected), len(actual))
+		}))
+		Expect(message).To(ContainSubstring("entry 0: custom diff of 1 and 2 keys"))
+		Expect(message).ToNot(ContainSubstring("data.count"))
+	})
+
+	It("is only used for entries with mismatching data", func() {
+		differ := DifferFunc(func(expected, actual lager.Data) string {
+			Fail("differ must not be called")
+			return ""
+		})
+
+		Expect(logger).To(WithDiffer(differ, ContainSequence(Info(Data("count", 2)))))
+	})
+
+	It("does not affect other matchers", func() {
+		WithDiffer(DifferFunc(func(expected, actual lager.Data) string {
+			return "custom diff"
+		}), ContainSequence(Info()))
+
+		Expect(failureMessage(nil)).ToNot(ContainSubstring("custom diff"))
+	})
+
+	It("panics for other matchers", func() {
+		Expect(func() { WithDiffer(nil, HaveNoErrors()) }).To(Panic())
+	})
+})
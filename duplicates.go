@@ -0,0 +1,130 @@
+package glager
+
+import (
+	"fmt"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// DuplicateOption configures what HaveNoDuplicateEntries considers a duplicate.
+type DuplicateOption func(*duplicateMatcher)
+
+// IgnoreTimestamp makes HaveNoDuplicateEntries consider entries duplicates
+// regardless of their timestamps, e.g. to catch handlers that are retried and
+// log the same entry again later.
+func IgnoreTimestamp() DuplicateOption {
+	return func(dm *duplicateMatcher) {
+		dm.ignoreTimestamp = true
+	}
+}
+
+// IgnoreDataKeys makes HaveNoDuplicateEntries disregard the given data keys
+// when comparing entries, e.g. a "session" or "attempt" key that differs
+// between otherwise identical entries.
+func IgnoreDataKeys(keys ...string) DuplicateOption {
+	return func(dm *duplicateMatcher) {
+		for _, key := range keys {
+			dm.ignoredKeys[key] = true
+		}
+	}
+}
+
+type duplicateMatcher struct {
+	ignoreTimestamp bool
+	ignoredKeys     map[string]bool
+	entry           *LogEntry
+	first           int
+	second          int
+}
+
+// HaveNoDuplicateEntries checks that no entry occurs more than once in the
+// log, i.e. that no two entries share the same timestamp, level, source,
+// message, and data. Use it to catch entries that are logged twice, e.g.
+// because a sink has been registered twice. Pass IgnoreTimestamp or
+// IgnoreDataKeys to relax what is considered a duplicate.
+//
+// Example:
+//
+//	Expect(logger).To(HaveNoDuplicateEntries())
+//	Expect(logger).To(HaveNoDuplicateEntries(IgnoreTimestamp(), IgnoreDataKeys("session")))
+func HaveNoDuplicateEntries(options ...DuplicateOption) types.GomegaMatcher {
+	matcher := &duplicateMatcher{
+		ignoredKeys: map[string]bool{},
+	}
+
+	for _, option := range options {
+		option(matcher)
+	}
+
+	return matcher
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (dm *duplicateMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("HaveNoDuplicateEntries", actual)
+	if err != nil {
+		return false, err
+	}
+
+	dm.entry = nil
+
+	seen := map[string]int{}
+	for i, entry := range entries {
+		key, err := dm.key(entry)
+		if err != nil {
+			return false, err
+		}
+
+		if first, found := seen[key]; found {
+			dm.entry, dm.first, dm.second = &entries[i], first, i
+			return false, nil
+		}
+		seen[key] = i
+	}
+
+	return true, nil
+}
+
+// key returns the canonical representation of the given entry that is equal
+// for duplicates.
+func (dm *duplicateMatcher) key(entry LogEntry) (string, error) {
+	if dm.ignoreTimestamp {
+		entry.Timestamp = ""
+	}
+
+	data := map[string]interface{}{}
+	for key, value := range entry.Data {
+		if !dm.ignoredKeys[key] {
+			data[key] = value
+		}
+	}
+
+	encoded, err := canonicalJSON([]interface{}{
+		entry.Timestamp, entry.LogLevel, entry.Source, entry.Message, data, entry.Fields,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (dm *duplicateMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected log entries to be unique, entries %d and %d are duplicates of\n%s",
+		dm.first,
+		dm.second,
+		format.IndentString(dm.entry.String(), 1),
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (dm *duplicateMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return "Expected log to contain duplicate entries"
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (dm *duplicateMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
@@ -0,0 +1,65 @@
+package glager_test
+
+import (
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".HaveNoDuplicateEntries", func() {
+	var (
+		buffer *gbytes.Buffer
+		logger lager.Logger
+	)
+
+	BeforeEach(func() {
+		buffer = gbytes.NewBuffer()
+		logger = lager.NewLogger("test")
+		logger.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+	})
+
+	It("matches a log without duplicate entries", func() {
+		logger.Info("start")
+		logger.Info("done")
+		Expect(buffer).To(HaveNoDuplicateEntries())
+	})
+
+	It("does not match a log with entries written twice", func() {
+		logger.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+		logger.Info("start", lager.Data{"port": 8080})
+
+		matcher := HaveNoDuplicateEntries()
+		Expect(matcher.Match(buffer)).To(BeFalse())
+
+		message := matcher.FailureMessage(buffer)
+		Expect(message).To(ContainSubstring("entries 0 and 1 are duplicates of"))
+		Expect(message).To(ContainSubstring("test.start"))
+	})
+
+	Context("when entries are logged again later", func() {
+		const log = `{"timestamp":"1.0","source":"test","message":"test.handle","log_level":1,"data":{"attempt":1}}
+{"timestamp":"2.0","source":"test","message":"test.handle","log_level":1,"data":{"attempt":2}}
+`
+
+		It("considers the timestamp and all data keys by default", func() {
+			Expect(strings.NewReader(log)).To(HaveNoDuplicateEntries())
+			Expect(strings.NewReader(log)).To(HaveNoDuplicateEntries(IgnoreTimestamp()))
+			Expect(strings.NewReader(log)).To(HaveNoDuplicateEntries(IgnoreDataKeys("attempt")))
+		})
+
+		It("ignores the timestamp and the given data keys", func() {
+			Expect(strings.NewReader(log)).ToNot(HaveNoDuplicateEntries(IgnoreTimestamp(), IgnoreDataKeys("attempt")))
+		})
+	})
+
+	It("returns an error for invalid actuals", func() {
+		_, err := HaveNoDuplicateEntries().Match(42)
+		Expect(err).To(MatchError(ContainSubstring("HaveNoDuplicateEntries must be passed")))
+	})
+})
@@ -0,0 +1,98 @@
+package glager
+
+import (
+	"encoding/json"
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+type snapshot []byte
+
+// Contents implements ContentsProvider.
+func (s snapshot) Contents() []byte {
+	return s
+}
+
+// LoggedDuring runs the given block and returns the part of the log of the
+// given BufferProvider, e.g. a TestLogger, that has been written while the
+// block was running. The result can be passed to any of the matchers.
+//
+// Example:
+//
+//	log := LoggedDuring(logger, func() {
+//	  myFunc(logger)
+//	})
+//	Expect(log).To(HaveLogged(Info(Action("test.myFunc"))))
+func LoggedDuring(provider gbytes.BufferProvider, block func()) ContentsProvider {
+	start := len(provider.Buffer().Contents())
+	block()
+
+	contents := provider.Buffer().Contents()[start:]
+	return snapshot(append([]byte{}, contents...))
+}
+
+// AssertNoLogsDuring runs the given block and fails the current test, using
+// Gomega's fail handler, if any entry matching the given entry has been logged
+// to the given BufferProvider while the block was running. Entries logged
+// before or after the block are ignored.
+//
+// Example:
+//
+//	AssertNoLogsDuring(logger, Error(AnyErr), func() {
+//	  myFunc(logger)
+//	})
+func AssertNoLogsDuring(provider gbytes.BufferProvider, entry ExpectedEntry, block func()) {
+	gomega.ExpectWithOffset(1, LoggedDuring(provider, block)).ToNot(HaveLogged(entry))
+}
+
+// captureSink records entries while it is active.
+type captureSink struct {
+	lock    sync.Mutex
+	active  bool
+	entries []LogEntry
+}
+
+// Log implements lager.Sink.
+func (s *captureSink) Log(log lager.LogFormat) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.active {
+		return
+	}
+
+	var entry LogEntry
+	if err := json.Unmarshal(log.ToJSON(), &entry); err == nil {
+		s.entries = append(s.entries, entry)
+	}
+}
+
+// Capture registers a recording sink with the given logger, runs the given
+// block, and returns exactly the entries logged during the block. Entries
+// logged by other tests sharing the logger before or after the block are not
+// returned. Since lager does not allow to remove sinks, the sink stays
+// registered but stops recording once the block returns. Just like any other
+// sink, it does not receive the entries of sessions that have been created
+// before calling Capture.
+//
+// Example:
+//
+//	entries := Capture(logger, func() {
+//	  myFunc(logger)
+//	})
+//	Expect(entries).To(ContainSequence(Info(Action("test.myFunc"))))
+func Capture(logger lager.Logger, block func()) []LogEntry {
+	sink := &captureSink{active: true}
+	logger.RegisterSink(sink)
+
+	block()
+
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+
+	sink.active = false
+	return sink.entries
+}
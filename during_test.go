@@ -0,0 +1,82 @@
+package glager_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("logs during a block", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		logger.Error("before", errors.New("boom"))
+	})
+
+	Describe(".LoggedDuring", func() {
+		It("returns the entries logged while running the block", func() {
+			log := LoggedDuring(logger, func() {
+				logger.Info("during")
+			})
+			logger.Info("after")
+
+			Expect(log).To(HaveLogged(Info(Action("test.during"))))
+			Expect(log).ToNot(HaveLogged(Error(AnyErr)))
+			Expect(log).ToNot(HaveLogged(Info(Action("test.after"))))
+		})
+	})
+
+	Describe(".AssertNoLogsDuring", func() {
+		It("passes if no matching entry is logged while running the block", func() {
+			ran := false
+			AssertNoLogsDuring(logger, Error(AnyErr), func() {
+				ran = true
+				logger.Info("during")
+			})
+			logger.Error("after", errors.New("boom"))
+
+			Expect(ran).To(BeTrue())
+		})
+
+		It("fails if a matching entry is logged while running the block", func() {
+			failures := InterceptGomegaFailures(func() {
+				AssertNoLogsDuring(logger, Error(AnyErr), func() {
+					logger.Error("during", errors.New("boom"))
+				})
+			})
+
+			Expect(failures).To(HaveLen(1))
+			Expect(failures[0]).To(ContainSubstring("test.during"))
+			Expect(failures[0]).ToNot(ContainSubstring("test.before"))
+		})
+	})
+
+	Describe(".Capture", func() {
+		It("returns exactly the entries logged during the block", func() {
+			shared := lager.NewLogger("shared")
+			shared.Info("before")
+
+			entries := Capture(shared, func() {
+				shared.Info("during")
+				shared.Session("session").Debug("nested")
+			})
+			shared.Info("after")
+
+			Expect(entries).To(HaveLen(2))
+			Expect(entries).To(ContainSequence(
+				Info(Action("shared.during")),
+				Debug(Action("shared.session.nested")),
+			))
+		})
+
+		It("returns no entries if nothing is logged", func() {
+			Expect(Capture(lager.NewLogger("shared"), func() {})).To(BeEmpty())
+		})
+	})
+})
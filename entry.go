@@ -0,0 +1,434 @@
+package glager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// LogEntry is a single entry that has been parsed from a lager log.
+type LogEntry struct {
+	Timestamp string         `json:"timestamp"`
+	Source    string         `json:"source"`
+	Message   string         `json:"message"`
+	LogLevel  lager.LogLevel `json:"log_level"`
+	Data      lager.Data     `json:"data"`
+
+	// Fields holds the top-level keys of the entry that are not part of
+	// lager's format, e.g. "trace_id" or "hostname" added by other log
+	// schemas. See the TopLevelField option.
+	Fields map[string]interface{} `json:"-"`
+
+	// err is the error value that has been logged, if it is known, i.e. for
+	// entries logged to a MockLogger.
+	err error
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Besides the numeric "log_level"
+// of the default lager format, the level name written to "level" by the pretty
+// format of lager, which is the default of lager v3, is understood as well.
+// Any other top-level keys are retained in Fields.
+func (e *LogEntry) UnmarshalJSON(encoded []byte) error {
+	type plain LogEntry
+
+	var entry struct {
+		plain
+		Level *string `json:"level"`
+	}
+	if err := json.Unmarshal(encoded, &entry); err != nil {
+		return err
+	}
+
+	*e = LogEntry(entry.plain)
+
+	if entry.Level != nil {
+		level, err := ParseLevel(*entry.Level)
+		if err != nil {
+			return err
+		}
+		e.LogLevel = level.LogLevel()
+	}
+
+	if hasLongInteger(encoded) {
+		// integers beyond the precision of float64, e.g. IDs, are retained
+		// exactly instead of being rounded
+		var exact struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(encoded, &exact); err != nil {
+			return err
+		}
+
+		if len(exact.Data) > 0 {
+			data, err := decodeExact(exact.Data)
+			if err != nil {
+				return err
+			}
+			if data, ok := data.(map[string]interface{}); ok {
+				e.Data = data
+			}
+		}
+	}
+
+	// decoding the keys a second time is only necessary for entries that
+	// have keys other than lager's, which is rarely the case
+	if onlyLagerKeys(encoded) {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return err
+	}
+
+	for key, value := range fields {
+		if lagerKeys[key] {
+			continue
+		}
+
+		decoded, err := decodeExact(value)
+		if err != nil {
+			return err
+		}
+
+		if e.Fields == nil {
+			e.Fields = map[string]interface{}{}
+		}
+		e.Fields[key] = decoded
+	}
+
+	return nil
+}
+
+// lagerKeys are the top-level keys of lager's formats.
+var lagerKeys = map[string]bool{
+	"timestamp": true,
+	"source":    true,
+	"message":   true,
+	"log_level": true,
+	"level":     true,
+	"data":      true,
+}
+
+// onlyLagerKeys reports whether the given JSON object, which must be valid,
+// has lager keys only. It scans the top-level keys without decoding any of
+// the values. Keys containing escape sequences are reported as non-lager keys.
+func onlyLagerKeys(encoded []byte) bool {
+	i := skipSpace(encoded, 0)
+	if i >= len(encoded) || encoded[i] != '{' {
+		return false
+	}
+
+	for {
+		i = skipSpace(encoded, i+1)
+		if i >= len(encoded) || encoded[i] != '"' {
+			return i < len(encoded) && encoded[i] == '}'
+		}
+
+		end := closingQuote(encoded, i)
+		if end >= len(encoded) || bytes.IndexByte(encoded[i:end], '\\') >= 0 || !lagerKeys[string(encoded[i+1:end])] {
+			return false
+		}
+
+		i = skipSpace(encoded, end+1)
+		if i >= len(encoded) || encoded[i] != ':' {
+			return false
+		}
+
+		i = skipSpace(encoded, skipValue(encoded, skipSpace(encoded, i+1)))
+		if i >= len(encoded) || encoded[i] != ',' {
+			return i < len(encoded) && encoded[i] == '}'
+		}
+	}
+}
+
+// skipValue returns the index right after the JSON value starting at the
+// given index.
+func skipValue(encoded []byte, i int) int {
+	depth := 0
+	for ; i < len(encoded); i++ {
+		switch encoded[i] {
+		case '"':
+			i = closingQuote(encoded, i)
+			if depth == 0 {
+				return i + 1
+			}
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth == 0 {
+				return i
+			}
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case ',', ' ', '\t', '\r', '\n':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return i
+}
+
+// closingQuote returns the index of the quote that terminates the JSON string
+// starting at the given index.
+func closingQuote(encoded []byte, i int) int {
+	for i++; i < len(encoded); i++ {
+		switch encoded[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return i
+}
+
+func skipSpace(encoded []byte, i int) int {
+	for i < len(encoded) && (encoded[i] == ' ' || encoded[i] == '\t' || encoded[i] == '\r' || encoded[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+// MarshalJSON implements json.Marshaler. The fields of the entry are written as
+// top-level keys.
+func (e LogEntry) MarshalJSON() ([]byte, error) {
+	type plain LogEntry
+
+	if len(e.Fields) == 0 {
+		return json.Marshal(plain(e))
+	}
+
+	encoded := map[string]interface{}{}
+	for key, value := range e.Fields {
+		encoded[key] = value
+	}
+
+	encoded["timestamp"] = e.Timestamp
+	encoded["source"] = e.Source
+	encoded["message"] = e.Message
+	encoded["log_level"] = e.LogLevel
+	encoded["data"] = e.Data
+
+	return json.Marshal(encoded)
+}
+
+// maxValueLength is the number of bytes of an encoded data value that is
+// included when formatting a log entry.
+const maxValueLength = 64
+
+// String returns a human-readable representation of the log entry with one
+// aligned field per line. Data is listed with one key per line, sorted by key.
+// Long data values are elided.
+func (e LogEntry) String() string {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "timestamp: %s\n", e.Timestamp)
+	fmt.Fprintf(b, "source:    %s\n", e.Source)
+	fmt.Fprintf(b, "message:   %s\n", e.Message)
+	fmt.Fprintf(b, "level:     %s\n", levelName(e.LogLevel))
+
+	if len(e.Fields) > 0 {
+		b.WriteString("fields:")
+		writeValues(b, e.Fields)
+		b.WriteString("\n")
+	}
+
+	if len(e.Data) == 0 {
+		b.WriteString("data:      {}")
+		return b.String()
+	}
+
+	b.WriteString("data:")
+	writeValues(b, e.Data)
+
+	return b.String()
+}
+
+// writeValues writes the given values with one aligned key per line, sorted
+// by key.
+func writeValues(b *strings.Builder, values map[string]interface{}) {
+	width := 0
+	for key := range values {
+		if len(key) > width {
+			width = len(key)
+		}
+	}
+
+	for _, key := range sortedKeys(values) {
+		fmt.Fprintf(b, "\n  %-*s %s", width+1, key+":", elideValue(values[key]))
+	}
+}
+
+// GoString returns a compact, single-line representation of the log entry.
+// Long data values are elided.
+func (e LogEntry) GoString() string {
+	data := make([]string, 0, len(e.Data))
+	for _, key := range sortedKeys(e.Data) {
+		data = append(data, fmt.Sprintf("%q: %s", key, elideValue(e.Data[key])))
+	}
+
+	var fields string
+	if len(e.Fields) > 0 {
+		rendered := make([]string, 0, len(e.Fields))
+		for _, key := range sortedKeys(e.Fields) {
+			rendered = append(rendered, fmt.Sprintf("%q: %s", key, elideValue(e.Fields[key])))
+		}
+		fields = fmt.Sprintf("Fields: {%s}, ", strings.Join(rendered, ", "))
+	}
+
+	return fmt.Sprintf(
+		"glager.LogEntry{Timestamp: %q, Source: %q, Message: %q, LogLevel: %s, %sData: {%s}}",
+		e.Timestamp,
+		e.Source,
+		e.Message,
+		levelName(e.LogLevel),
+		fields,
+		strings.Join(data, ", "),
+	)
+}
+
+// GomegaString implements format.GomegaStringer. Entries are rendered using
+// their compact representation in Gomega failure messages, unless it exceeds
+// maxCompactLength. Longer entries are rendered on multiple lines, with their
+// data as indented JSON, and truncated to format.MaxLength. If
+// format.UseStringerRepresentation is set, the compact representation is used
+// regardless of its length.
+func (e LogEntry) GomegaString() string {
+	compact := e.GoString()
+	if format.UseStringerRepresentation || len(compact) <= maxCompactLength {
+		return compact
+	}
+
+	return truncate(e.render(nil))
+}
+
+// describeValue renders an expected data value, which can be a matcher.
+func describeValue(value interface{}) string {
+	if _, ok := value.(types.GomegaMatcher); ok {
+		return fmt.Sprintf("<%T>", value)
+	}
+	if sub, ok := value.(subMap); ok {
+		return sub.GomegaString()
+	}
+	return elideValue(value)
+}
+
+// elideValue renders a data value using its canonical JSON representation,
+// i.e. with sorted keys, eliding it if it is too long.
+func elideValue(value interface{}) string {
+	encoded, err := canonicalJSON(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	if len(encoded) <= maxValueLength {
+		return string(encoded)
+	}
+
+	n := maxValueLength
+	for n > 0 && !utf8.Valid(encoded[:n]) {
+		n--
+	}
+
+	return fmt.Sprintf("%s... (%d bytes)", encoded[:n], len(encoded))
+}
+
+// Time parses and returns the timestamp of the log entry. Timestamps are
+// expected to be in lager's format, i.e. seconds since the Unix epoch with
+// fractional nanoseconds, or in RFC3339 format.
+func (e LogEntry) Time() (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, e.Timestamp); err == nil {
+		return t, nil
+	}
+
+	parts := strings.SplitN(e.Timestamp, ".", 2)
+
+	sec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid timestamp %q.", e.Timestamp)
+	}
+
+	var nsec int64
+	if len(parts) == 2 {
+		fraction := (parts[1] + "000000000")[:9]
+		nsec, err = strconv.ParseInt(fraction, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("Invalid timestamp %q.", e.Timestamp)
+		}
+	}
+
+	return time.Unix(sec, nsec), nil
+}
+
+// Entries parses and returns the log entries contained in the given actual.
+// The actual can be anything that is accepted by the ContainSequence matcher.
+func Entries(actual interface{}) ([]LogEntry, error) {
+	return parseEntries("Entries", actual)
+}
+
+// ParseEntries parses the log entries read from the given reader until it
+// returns io.EOF. Unlike Entries, the reader is consumed, i.e. it is not
+// replayed when parsed again. Use it together with the fields and methods of
+// LogEntry to build custom assertions that are not expressible as a matcher.
+//
+// Example:
+//
+//	entries, err := ParseEntries(file)
+//	Expect(err).ToNot(HaveOccurred())
+//
+//	for _, entry := range entries {
+//	  fmt.Println(entry.Level(), entry.Source, entry.Message, entry.Data)
+//	}
+func ParseEntries(reader io.Reader) ([]LogEntry, error) {
+	entries, _, err := decodeEntries(reader)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Level returns the log level of the entry.
+func (e LogEntry) Level() Level {
+	return Level(e.LogLevel)
+}
+
+// GetData returns the value of the given data key of a log entry converted to
+// type T. Since the entry has been decoded from JSON, the conversion is done by
+// re-encoding the value and decoding it into T. This takes care of coercing
+// JSON numbers into integer types as well as decoding objects into structs.
+//
+// Example:
+//
+//	count, err := GetData[int](entry, "count")
+func GetData[T any](entry LogEntry, key string) (T, error) {
+	var result T
+
+	value, found := entry.Data[key]
+	if !found {
+		return result, fmt.Errorf("Log entry does not contain data key %q.", key)
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return result, err
+	}
+
+	if err := json.Unmarshal(encoded, &result); err != nil {
+		return result, fmt.Errorf("Invalid type for data key %q. Want %T. Got %T:%v.", key, result, value, value)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,311 @@
+package glager_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("LogEntry", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+	})
+
+	Describe(".Entries", func() {
+		It("returns the parsed entries", func() {
+			logger.Info("first", lager.Data{"foo": "bar"})
+			logger.Debug("second")
+
+			entries, err := Entries(logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+
+			Expect(entries[0].Source).To(Equal("test"))
+			Expect(entries[0].Message).To(Equal("test.first"))
+			Expect(entries[0].LogLevel).To(Equal(lager.INFO))
+			Expect(entries[0].Data).To(HaveKeyWithValue("foo", "bar"))
+			Expect(entries[0].Timestamp).ToNot(BeEmpty())
+
+			Expect(entries[1].LogLevel).To(Equal(lager.DEBUG))
+		})
+
+		It("returns an error for invalid actuals", func() {
+			_, err := Entries(42)
+			Expect(err).To(MatchError(ContainSubstring("Entries must be passed")))
+		})
+	})
+
+	Describe(".ParseEntries", func() {
+		It("parses the entries read from the reader", func() {
+			logger.Info("first", lager.Data{"foo": "bar"})
+			logger.Error("second", errors.New("boom"))
+
+			entries, err := ParseEntries(bytes.NewReader(logger.Buffer().Contents()))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+
+			Expect(entries[0].Level()).To(Equal(LevelInfo))
+			Expect(entries[0].Message).To(Equal("test.first"))
+			Expect(entries[1].Level().String()).To(Equal("error"))
+			Expect(entries[1].Data).To(HaveKeyWithValue("error", "boom"))
+		})
+
+		It("returns an error for malformed entries", func() {
+			_, err := ParseEntries(strings.NewReader("not json\n"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe(".GetData", func() {
+		type request struct {
+			Method string `json:"method"`
+		}
+
+		var entry LogEntry
+
+		BeforeEach(func() {
+			logger.Info("request", lager.Data{
+				"count":   17,
+				"ratio":   0.5,
+				"name":    "foo",
+				"request": request{"GET"},
+				"tags":    []string{"a", "b"},
+			})
+
+			entries, err := Entries(logger)
+			Expect(err).ToNot(HaveOccurred())
+			entry = entries[0]
+		})
+
+		It("coerces JSON numbers into integers", func() {
+			Expect(GetData[int](entry, "count")).To(Equal(17))
+			Expect(GetData[int64](entry, "count")).To(Equal(int64(17)))
+		})
+
+		It("returns floats", func() {
+			Expect(GetData[float64](entry, "ratio")).To(Equal(0.5))
+		})
+
+		It("returns strings", func() {
+			Expect(GetData[string](entry, "name")).To(Equal("foo"))
+		})
+
+		It("decodes objects into structs", func() {
+			Expect(GetData[request](entry, "request")).To(Equal(request{"GET"}))
+		})
+
+		It("decodes arrays into slices", func() {
+			Expect(GetData[[]string](entry, "tags")).To(Equal([]string{"a", "b"}))
+		})
+
+		It("returns an error for a missing key", func() {
+			_, err := GetData[int](entry, "missing")
+			Expect(err).To(MatchError(ContainSubstring(`does not contain data key "missing"`)))
+		})
+
+		It("returns an error for an incompatible type", func() {
+			_, err := GetData[int](entry, "name")
+			Expect(err).To(MatchError(ContainSubstring(`Invalid type for data key "name"`)))
+
+			_, err = GetData[int](entry, "ratio")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Time", func() {
+		It("parses lager timestamps", func() {
+			entry := LogEntry{Timestamp: "1500000000.123456789"}
+			Expect(entry.Time()).To(Equal(time.Unix(1500000000, 123456789)))
+		})
+
+		It("parses lager timestamps with fewer fractional digits", func() {
+			entry := LogEntry{Timestamp: "1500000000.5"}
+			Expect(entry.Time()).To(Equal(time.Unix(1500000000, 500000000)))
+		})
+
+		It("parses RFC3339 timestamps", func() {
+			entry := LogEntry{Timestamp: "2017-07-14T02:40:00.123456789Z"}
+			Expect(entry.Time()).To(BeTemporally("==", time.Unix(1500000000, 123456789)))
+		})
+
+		It("parses the timestamps written by lager", func() {
+			before := time.Now()
+			logger.Info("now")
+
+			entries, err := Entries(logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries[0].Time()).To(BeTemporally("~", before, time.Second))
+		})
+
+		It("returns an error for invalid timestamps", func() {
+			_, err := LogEntry{Timestamp: "yesterday"}.Time()
+			Expect(err).To(MatchError(`Invalid timestamp "yesterday".`))
+		})
+	})
+
+	Describe("String", func() {
+		It("aligns the fields", func() {
+			entry := LogEntry{
+				Timestamp: "1500000000.5",
+				Source:    "test",
+				Message:   "test.start",
+				LogLevel:  lager.INFO,
+				Data:      lager.Data{"session": "1", "count": 2},
+			}
+
+			Expect(entry.String()).To(Equal(`timestamp: 1500000000.5
+source:    test
+message:   test.start
+level:     info
+data:
+  count:   2
+  session: "1"`))
+		})
+
+		It("renders additional fields", func() {
+			entry := LogEntry{LogLevel: lager.INFO, Fields: map[string]interface{}{"trace_id": "abc", "host": "vm-1"}}
+			Expect(entry.String()).To(HaveSuffix(`level:     info
+fields:
+  host:     "vm-1"
+  trace_id: "abc"
+data:      {}`))
+		})
+
+		It("renders empty data", func() {
+			Expect(LogEntry{LogLevel: lager.DEBUG}.String()).To(HaveSuffix("level:     debug\ndata:      {}"))
+		})
+
+		It("elides long values", func() {
+			entry := LogEntry{Data: lager.Data{"long": strings.Repeat("a", 100)}}
+			Expect(entry.String()).To(HaveSuffix(`long: "` + strings.Repeat("a", 63) + `... (102 bytes)`))
+		})
+	})
+
+	Describe("GoString", func() {
+		It("renders the entry on a single line", func() {
+			entry := LogEntry{
+				Timestamp: "1500000000.5",
+				Source:    "test",
+				Message:   "test.failed",
+				LogLevel:  lager.ERROR,
+				Data:      lager.Data{"error": "boom", "nested": map[string]interface{}{"a": 1}},
+			}
+
+			Expect(fmt.Sprintf("%#v", entry)).To(Equal(
+				`glager.LogEntry{Timestamp: "1500000000.5", Source: "test", Message: "test.failed", LogLevel: error, Data: {"error": "boom", "nested": {"a":1}}}`,
+			))
+		})
+	})
+
+	Describe("Gomega formatting", func() {
+		It("renders log entries compactly", func() {
+			entry := LogEntry{Source: "test", Message: "test.start", LogLevel: lager.INFO}
+			Expect(format.Object([]LogEntry{entry}, 0)).To(ContainSubstring(
+				`glager.LogEntry{Timestamp: "", Source: "test", Message: "test.start", LogLevel: info, Data: {}}`,
+			))
+		})
+
+		It("renders expected entries the way they have been constructed", func() {
+			entry := Error(errors.New("boom"), Source("test"), Data("id", BeAUUID()), SessionUnder("3"))
+			Expect(format.Object(entry, 0)).To(HaveSuffix(
+				`Error(AnyErr, Source("test"), Data("error", "boom"), Data("id", <*glager.formatMatcher>), <session under "3">)`,
+			))
+			Expect(format.Object(Entry(lager.LogLevel(7)), 0)).To(HaveSuffix(": Entry(7)"))
+		})
+
+		It("renders expected entries in failure messages", func() {
+			matcher := ContainSequence(Info(Message("test.missing")))
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring(`Info(Message("test.missing"))`))
+		})
+	})
+
+	Describe("pretty format", func() {
+		It("parses the entries written by the pretty sink of lager", func() {
+			buffer := gbytes.NewBuffer()
+			logger := lager.NewLogger("test")
+			logger.RegisterSink(lager.NewPrettySink(buffer, lager.DEBUG))
+
+			logger.Info("start", lager.Data{"user": "admin"})
+			logger.Error("failed", errors.New("boom"))
+
+			Expect(buffer).To(ContainSequence(
+				Info(Action("test.start"), Data("user", "admin")),
+				Error(errors.New("boom"), Action("test.failed")),
+			))
+
+			entries, err := Entries(buffer)
+			Expect(err).ToNot(HaveOccurred())
+
+			t, err := entries[0].Time()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(t).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+
+		It("parses logs mixing the pretty and the default format", func() {
+			buffer := gbytes.NewBuffer()
+			logger := lager.NewLogger("test")
+			logger.RegisterSink(lager.NewPrettySink(buffer, lager.DEBUG))
+			logger.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+
+			start := time.Now()
+			logger.Debug("start")
+
+			Expect(buffer).To(ContainSequence(
+				Debug(Action("test.start"), LoggedWithin(start, time.Minute)),
+				Debug(Action("test.start"), LoggedWithin(start, time.Minute)),
+			))
+		})
+
+		It("returns an error for unknown level names", func() {
+			_, err := Entries(strings.NewReader(`{"timestamp":"2024-01-01T00:00:00Z","level":"loud","source":"test","message":"test.start"}` + "\n"))
+			Expect(err).To(MatchError(ContainSubstring(`Invalid log level "loud".`)))
+		})
+	})
+
+	Describe("additional keys", func() {
+		parse := func(line string) LogEntry {
+			entries, err := Entries(strings.NewReader(line + "\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			return entries[0]
+		}
+
+		It("does not retain any fields for entries with lager keys only", func() {
+			entry := parse(`{"timestamp":"1","source":"test","message":"test.start","log_level":1,"data":{"trace_id":"abc","nested":{"host":"vm-1"}}}`)
+			Expect(entry.Fields).To(BeNil())
+			Expect(entry.Data).To(HaveKeyWithValue("trace_id", "abc"))
+		})
+
+		It("retains keys following values that contain delimiters", func() {
+			entry := parse(`{ "message" : "a \"quoted\", {braced} [value]" , "data" : {"list":[1,{"a":"}"}]} , "trace_id" : "abc" }`)
+			Expect(entry.Message).To(Equal(`a "quoted", {braced} [value]`))
+			Expect(entry.Data).To(HaveKey("list"))
+			Expect(entry.Fields).To(Equal(map[string]interface{}{"trace_id": "abc"}))
+		})
+
+		It("retains keys containing escape sequences", func() {
+			entry := parse(`{"message":"test.start","log_level":1,"trace\u005fid":"abc"}`)
+			Expect(entry.Fields).To(Equal(map[string]interface{}{"trace_id": "abc"}))
+		})
+
+		It("retains keys matching lager keys case-insensitively", func() {
+			entry := parse(`{"message":"test.start","log_level":1,"Host":"vm-1","Message":"other"}`)
+			Expect(entry.Fields).To(HaveKeyWithValue("Host", "vm-1"))
+			Expect(entry.Fields).To(HaveKeyWithValue("Message", "other"))
+		})
+	})
+})
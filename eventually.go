@@ -0,0 +1,80 @@
+package glager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// SequencePollingInterval is the interval at which EventuallySequence re-reads
+// the log. Use EventuallySequenceEvery for a different interval.
+const SequencePollingInterval = 10 * time.Millisecond
+
+// EventuallySequence re-reads the log returned by the given function until it
+// contains the expected sequence, like ContainSequence, or until the given
+// context is done. Use it to wait for the logs of external processes outside
+// of Gomega assertions, e.g. in test helpers. The function is called for every
+// attempt, readers that implement io.Closer are closed after reading them.
+// The returned error describes the entries observed by the last attempt, and
+// the outcome of every attempt.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//
+//	err := EventuallySequence(ctx, func() (io.Reader, error) {
+//	  return os.Open("/tmp/app.log")
+//	}, Info(Action("app.ready")))
+func EventuallySequence(ctx context.Context, newReader func() (io.Reader, error), expected ...SequenceEntry) error {
+	return EventuallySequenceEvery(ctx, SequencePollingInterval, newReader, expected...)
+}
+
+// EventuallySequenceEvery is like EventuallySequence, but re-reads the log at
+// the given interval.
+//
+// Example:
+//
+//	err := EventuallySequenceEvery(ctx, time.Second, func() (io.Reader, error) {
+//	  return os.Open("/tmp/app.log")
+//	}, Info(Action("app.ready")))
+func EventuallySequenceEvery(ctx context.Context, interval time.Duration, newReader func() (io.Reader, error), expected ...SequenceEntry) error {
+	matcher := ContainSequence(expected...)
+
+	for {
+		contents, err := readAll(newReader)
+
+		var matched bool
+		if err == nil {
+			matched, err = matcher.Match(contents)
+		}
+
+		if matched {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("Sequence has not been logged: %s. The last attempt failed: %s", ctx.Err(), err)
+			}
+			return fmt.Errorf("Sequence has not been logged: %s.\n%s", ctx.Err(), matcher.FailureMessage(contents))
+		case <-time.After(interval):
+		}
+	}
+}
+
+// readAll reads the whole log returned by the given function.
+func readAll(newReader func() (io.Reader, error)) ([]byte, error) {
+	reader, err := newReader()
+	if err != nil {
+		return nil, err
+	}
+
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	return io.ReadAll(reader)
+}
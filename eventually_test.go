@@ -0,0 +1,115 @@
+package glager_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".EventuallySequence", func() {
+	var (
+		dir    string
+		path   string
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+
+	openLog := func() (io.Reader, error) {
+		return os.Open(path)
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "glager")
+		Expect(err).ToNot(HaveOccurred())
+
+		path = filepath.Join(dir, "app.log")
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	})
+
+	AfterEach(func() {
+		cancel()
+		os.RemoveAll(dir)
+	})
+
+	It("returns as soon as the sequence has been logged", func() {
+		go func() {
+			defer GinkgoRecover()
+
+			file, err := os.Create(path)
+			Expect(err).ToNot(HaveOccurred())
+			defer file.Close()
+
+			logger := lager.NewLogger("app")
+			logger.RegisterSink(lager.NewWriterSink(file, lager.DEBUG))
+			logger.Info("starting")
+			time.Sleep(50 * time.Millisecond)
+			logger.Info("ready")
+		}()
+
+		err := EventuallySequence(ctx, openLog, Info(Action("app.starting")), Info(Action("app.ready")))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("describes the observed entries once the context is done", func() {
+		Expect(os.WriteFile(path, []byte(`{"timestamp":"1","source":"app","message":"app.starting","log_level":1,"data":{}}`+"\n"), 0644)).To(Succeed())
+		ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+
+		err := EventuallySequence(ctx, openLog, Info(Action("app.starting")), Info(Action("app.ready")))
+		Expect(err).To(MatchError(HavePrefix("Sequence has not been logged: context deadline exceeded.\n")))
+		Expect(err.Error()).To(ContainSubstring("app.starting"))
+		Expect(err.Error()).To(ContainSubstring(`Info(Message("app.ready"))`))
+	})
+
+	It("reports the error of the last attempt", func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+
+		err := EventuallySequence(ctx, func() (io.Reader, error) {
+			return nil, errors.New("not yet")
+		}, Info())
+		Expect(err).To(MatchError("Sequence has not been logged: context deadline exceeded. The last attempt failed: not yet"))
+	})
+
+	It("closes the readers", func() {
+		reader := &closingReader{Reader: strings.NewReader("")}
+		ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+
+		Expect(EventuallySequence(ctx, func() (io.Reader, error) { return reader, nil }, Info())).ToNot(Succeed())
+		Expect(reader.closed).To(BeTrue())
+	})
+
+	Describe(".EventuallySequenceEvery", func() {
+		It("re-reads the log at the given interval", func() {
+			attempts := 0
+			ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+
+			err := EventuallySequenceEvery(ctx, 40*time.Millisecond, func() (io.Reader, error) {
+				attempts++
+				return strings.NewReader(""), nil
+			}, Info())
+			Expect(err).To(HaveOccurred())
+			Expect(attempts).To(BeNumerically("<=", 3))
+		})
+	})
+})
+
+type closingReader struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closingReader) Close() error {
+	r.closed = true
+	return nil
+}
@@ -0,0 +1,112 @@
+package glager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// Select parses the given actual and returns the entries matching any of the
+// given entries, or all entries if none are given. The actual can be anything
+// that is accepted by the ContainSequence matcher.
+//
+// Example:
+//
+//	errors, err := Select(logger, Error(AnyErr))
+func Select(actual interface{}, entries ...logEntry) ([]LogEntry, error) {
+
+	parsed, err := parseEntries("Select", actual)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.filter(entries)
+}
+
+// BySource parses the given actual and splits its entries by their source,
+// e.g. to match the entries of components sharing a sink independently. The
+// entries of every source can be passed to any of the matchers. The actual can
+// be anything that is accepted by the ContainSequence matcher. The entries are
+// a snapshot, i.e. entries logged afterwards are not included.
+//
+// Example:
+//
+//	sources, err := BySource(buffer)
+//	Expect(err).ToNot(HaveOccurred())
+//	Expect(sources["worker"]).To(ContainSequence(Info(Action("worker.started"))))
+func BySource(actual interface{}) (map[string][]LogEntry, error) {
+	entries, err := parseEntries("BySource", actual)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := map[string][]LogEntry{}
+	for _, entry := range entries {
+		sources[entry.Source] = append(sources[entry.Source], entry)
+	}
+	return sources, nil
+}
+
+// FindSequence parses the given actual and returns the entries that match the
+// given sequence, i.e. the entries that make the ContainSequence matcher
+// succeed. It returns an error if the log does not contain the sequence.
+func FindSequence(actual interface{}, expectedSequence ...SequenceEntry) ([]LogEntry, error) {
+	entries, err := parseEntries("FindSequence", actual)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := compileSequence(sequenceOf(expectedSequence))
+
+	matched, err := entries.matchSequence(expected)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matched) < len(expected) {
+		return nil, fmt.Errorf("Log does not contain entry %d of the sequence.", len(matched))
+	}
+
+	result := make([]LogEntry, len(matched))
+	for n, i := range matched {
+		result[n] = entries[i]
+	}
+	return result, nil
+}
+
+// EncodeNDJSON encodes the given entries as newline-delimited JSON, i.e. in the
+// format written by lager. Use it to feed entries into components that consume
+// lager logs.
+func EncodeNDJSON(entries []LogEntry) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// ToLagerData converts the given entries into lager.Data, one per entry, using
+// the keys of lager's JSON format, i.e. "timestamp", "source", "message",
+// "log_level", and "data".
+func ToLagerData(entries []LogEntry) ([]lager.Data, error) {
+	result := make([]lager.Data, 0, len(entries))
+	for _, entry := range entries {
+		encoded, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		data := lager.Data{}
+		if err := json.Unmarshal(encoded, &data); err != nil {
+			return nil, err
+		}
+
+		result = append(result, data)
+	}
+	return result, nil
+}
@@ -0,0 +1,145 @@
+package glager_test
+
+import (
+	"bytes"
+	"errors"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("exporting entries", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		logger.Info("start", lager.Data{"event": "start"})
+		logger.Error("failed", errors.New("boom"))
+		logger.Debug("retry")
+		logger.Info("done", lager.Data{"event": "done"})
+	})
+
+	Describe(".Select", func() {
+		It("returns the entries matching any of the given entries", func() {
+			entries, err := Select(logger, Error(AnyErr), Debug())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].Message).To(Equal("test.failed"))
+			Expect(entries[1].Message).To(Equal("test.retry"))
+		})
+
+		It("selects entries by source", func() {
+			buffer := gbytes.NewBuffer()
+			api := lager.NewLogger("api")
+			api.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+			worker := lager.NewLogger("worker")
+			worker.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+
+			api.Error("failed", errors.New("timeout"))
+			worker.Error("crashed", errors.New("oom"))
+			worker.Info("restarted")
+
+			entries, err := Select(buffer, Error(nil, Source("worker")))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].Message).To(Equal("worker.crashed"))
+			Expect(entries[0].Data).To(HaveKeyWithValue("error", "oom"))
+		})
+
+		It("returns all entries if no entries are given", func() {
+			entries, err := Select(logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(4))
+		})
+
+		It("returns an error for invalid actuals", func() {
+			_, err := Select(42)
+			Expect(err).To(MatchError(ContainSubstring("Select must be passed")))
+		})
+	})
+
+	Describe(".BySource", func() {
+		It("splits the entries by their source", func() {
+			buffer := gbytes.NewBuffer()
+			api := lager.NewLogger("api")
+			api.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+			worker := lager.NewLogger("worker")
+			worker.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+
+			api.Info("request")
+			worker.Info("started")
+			api.Info("response")
+			worker.Info("done")
+
+			sources, err := BySource(buffer)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sources).To(HaveLen(2))
+
+			Expect(sources["api"]).To(ContainExactSequence(Info(Action("api.request")), Info(Action("api.response"))))
+			Expect(sources["worker"]).To(ContainExactSequence(Info(Action("worker.started")), Info(Action("worker.done"))))
+		})
+
+		It("returns an error for invalid actuals", func() {
+			_, err := BySource(42)
+			Expect(err).To(MatchError(ContainSubstring("BySource must be passed")))
+		})
+	})
+
+	Describe(".FindSequence", func() {
+		It("returns the entries matching the sequence", func() {
+			entries, err := FindSequence(logger, Info(), Info())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].Message).To(Equal("test.start"))
+			Expect(entries[1].Message).To(Equal("test.done"))
+		})
+
+		It("returns an error if the sequence is not found", func() {
+			_, err := FindSequence(logger, Info(), Debug(), Error(AnyErr))
+			Expect(err).To(MatchError("Log does not contain entry 2 of the sequence."))
+		})
+	})
+
+	Describe(".EncodeNDJSON", func() {
+		It("encodes entries in lager's format", func() {
+			entries, err := Select(logger, Info())
+			Expect(err).ToNot(HaveOccurred())
+
+			encoded, err := EncodeNDJSON(entries)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bytes.NewReader(encoded)).To(BeNDJSON())
+			Expect(encoded).To(ContainSubstring(`"message":"test.start"`))
+			Expect(encoded).ToNot(ContainSubstring("test.failed"))
+		})
+	})
+
+	Describe(".ToLagerData", func() {
+		It("converts entries into lager.Data", func() {
+			entries, err := Select(logger, Info(Data("event", "done")))
+			Expect(err).ToNot(HaveOccurred())
+
+			data, err := ToLagerData(entries)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(ConsistOf(SatisfyAll(
+				HaveKeyWithValue("source", "test"),
+				HaveKeyWithValue("message", "test.done"),
+				HaveKeyWithValue("log_level", BeNumerically("==", lager.INFO)),
+				HaveKeyWithValue("data", HaveKeyWithValue("event", "done")),
+			)))
+		})
+	})
+
+	It("accepts selected entries as actual", func() {
+		entries, err := Select(logger, Info())
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(entries).To(ContainSequence(Info(Data("event", "start")), Info(Data("event", "done"))))
+		Expect(entries).ToNot(ContainSequence(Error(AnyErr)))
+		Expect(entries).To(HaveNoLineLongerThan(1024))
+	})
+})
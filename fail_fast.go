@@ -0,0 +1,63 @@
+package glager
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// FailFastSink is a lager.Sink that fails the current test as soon as an
+// entry at or above a given level is logged. Unexpected errors are thereby
+// reported at the moment they happen, including the stack of the offending
+// call, rather than by an assertion after the fact.
+type FailFastSink struct {
+	sink  lager.Sink
+	level lager.LogLevel
+	fail  types.GomegaFailHandler
+}
+
+var _ lager.Sink = &FailFastSink{}
+
+// NewFailFastSink returns a sink that passes all entries on to the given sink
+// and calls the given fail handler, e.g. ginkgo.Fail, for every entry at or
+// above the given level. The wrapped sink may be nil. Just like with any other
+// Gomega assertion, goroutines logging to the sink have to defer
+// ginkgo.GinkgoRecover when using ginkgo.Fail.
+//
+// Example:
+//
+//	logger := lager.NewLogger("test")
+//	logger.RegisterSink(NewFailFastSink(sink, lager.ERROR, Fail))
+func NewFailFastSink(sink lager.Sink, level lager.LogLevel, fail types.GomegaFailHandler) *FailFastSink {
+	return &FailFastSink{
+		sink:  sink,
+		level: level,
+		fail:  fail,
+	}
+}
+
+// Log implements lager.Sink.
+func (s *FailFastSink) Log(log lager.LogFormat) {
+	if s.sink != nil {
+		s.sink.Log(log)
+	}
+
+	if log.LogLevel < s.level {
+		return
+	}
+
+	var entry LogEntry
+	if err := json.Unmarshal(log.ToJSON(), &entry); err != nil {
+		s.fail(fmt.Sprintf("Failed to decode log entry: %s", err), 1)
+		return
+	}
+
+	s.fail(fmt.Sprintf(
+		"Unexpected log entry at level %s or above:\n%s",
+		levelName(s.level),
+		format.IndentString(entry.String(), 1),
+	), 1)
+}
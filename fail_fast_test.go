@@ -0,0 +1,59 @@
+package glager_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("FailFastSink", func() {
+	var (
+		buffer   *gbytes.Buffer
+		logger   lager.Logger
+		failures []string
+	)
+
+	BeforeEach(func() {
+		failures = nil
+		buffer = gbytes.NewBuffer()
+
+		logger = lager.NewLogger("test")
+		logger.RegisterSink(NewFailFastSink(lager.NewWriterSink(buffer, lager.DEBUG), lager.ERROR, func(message string, callerSkip ...int) {
+			failures = append(failures, message)
+		}))
+	})
+
+	It("does not fail for entries below the level", func() {
+		logger.Debug("retry")
+		logger.Info("start")
+		Expect(failures).To(BeEmpty())
+	})
+
+	It("fails for entries at or above the level", func() {
+		logger.Error("failed", errors.New("boom"))
+		Expect(failures).To(HaveLen(1))
+		Expect(failures[0]).To(HavePrefix("Unexpected log entry at level error or above:\n"))
+		Expect(failures[0]).To(ContainSubstring("test.failed"))
+		Expect(failures[0]).To(ContainSubstring("boom"))
+	})
+
+	It("passes all entries on to the wrapped sink", func() {
+		logger.Info("start")
+		logger.Error("failed", errors.New("boom"))
+		Expect(buffer).To(ContainSequence(Info(Action("test.start")), Error(AnyErr, Action("test.failed"))))
+	})
+
+	It("does not require a wrapped sink", func() {
+		sink := NewFailFastSink(nil, lager.INFO, func(message string, callerSkip ...int) {
+			failures = append(failures, message)
+		})
+		sink.Log(lager.LogFormat{LogLevel: lager.INFO, Message: "test.start"})
+		Expect(failures).To(HaveLen(1))
+	})
+})
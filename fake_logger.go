@@ -0,0 +1,285 @@
+package glager
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// Call is a single call to a FakeLogger, or one of the calls expected by the
+// HaveRecorded matcher.
+type Call struct {
+	// Level is the log level of the call.
+	Level lager.LogLevel
+
+	// Action is the action of the call, prefixed by the component and the
+	// sessions, e.g. "test.session.start", just like the message of a lager
+	// log entry.
+	Action string
+
+	// Session is the identifier of the session that made the call, e.g. "1.2".
+	Session string
+
+	// Err is the error passed to Error or Fatal.
+	Err error
+
+	// Data is the data of the call, including the data of the logger and its
+	// sessions. Values are recorded as is, i.e. without being encoded as JSON.
+	Data lager.Data
+}
+
+// FakeLogger is a lager.Logger that records every call in memory instead of
+// writing JSON to sinks. Values retain their Go types, which makes it suitable
+// for fast and type-precise unit tests. Use the HaveRecorded matcher to verify
+// the recorded calls.
+type FakeLogger struct {
+	task      string
+	sessionID string
+	data      lager.Data
+	nextID    *uint32
+	state     *fakeState
+}
+
+var _ lager.Logger = &FakeLogger{}
+
+type fakeState struct {
+	sync.Mutex
+	calls []Call
+}
+
+// NewFakeLogger returns a new FakeLogger for the given component.
+func NewFakeLogger(component string) *FakeLogger {
+	return &FakeLogger{
+		task:   component,
+		data:   lager.Data{},
+		nextID: new(uint32),
+		state:  &fakeState{},
+	}
+}
+
+// Calls returns the calls made to the logger and all of its sessions, in order.
+func (l *FakeLogger) Calls() []Call {
+	l.state.Lock()
+	defer l.state.Unlock()
+
+	return append([]Call{}, l.state.calls...)
+}
+
+// RegisterSink implements lager.Logger. Sinks are ignored.
+func (l *FakeLogger) RegisterSink(sink lager.Sink) {}
+
+// Session implements lager.Logger.
+func (l *FakeLogger) Session(task string, data ...lager.Data) lager.Logger {
+	sid := atomic.AddUint32(l.nextID, 1)
+
+	sessionID := fmt.Sprintf("%d", sid)
+	if l.sessionID != "" {
+		sessionID = fmt.Sprintf("%s.%d", l.sessionID, sid)
+	}
+
+	return &FakeLogger{
+		task:      fmt.Sprintf("%s.%s", l.task, task),
+		sessionID: sessionID,
+		data:      l.baseData(data...),
+		nextID:    new(uint32),
+		state:     l.state,
+	}
+}
+
+// SessionName implements lager.Logger.
+func (l *FakeLogger) SessionName() string {
+	return l.task
+}
+
+// WithData implements lager.Logger.
+func (l *FakeLogger) WithData(data lager.Data) lager.Logger {
+	return &FakeLogger{
+		task:      l.task,
+		sessionID: l.sessionID,
+		data:      l.baseData(data),
+		nextID:    l.nextID,
+		state:     l.state,
+	}
+}
+
+// Debug implements lager.Logger.
+func (l *FakeLogger) Debug(action string, data ...lager.Data) {
+	l.record(lager.DEBUG, action, nil, data...)
+}
+
+// Info implements lager.Logger.
+func (l *FakeLogger) Info(action string, data ...lager.Data) {
+	l.record(lager.INFO, action, nil, data...)
+}
+
+// Error implements lager.Logger.
+func (l *FakeLogger) Error(action string, err error, data ...lager.Data) {
+	l.record(lager.ERROR, action, err, data...)
+}
+
+// Fatal implements lager.Logger. Just like lager, it panics after recording.
+func (l *FakeLogger) Fatal(action string, err error, data ...lager.Data) {
+	l.record(lager.FATAL, action, err, data...)
+	panic(err)
+}
+
+func (l *FakeLogger) record(level lager.LogLevel, action string, err error, data ...lager.Data) {
+	l.state.Lock()
+	defer l.state.Unlock()
+
+	l.state.calls = append(l.state.calls, Call{
+		Level:   level,
+		Action:  fmt.Sprintf("%s.%s", l.task, action),
+		Session: l.sessionID,
+		Err:     err,
+		Data:    l.baseData(data...),
+	})
+}
+
+func (l *FakeLogger) baseData(data ...lager.Data) lager.Data {
+	result := lager.Data{}
+
+	for k, v := range l.data {
+		result[k] = v
+	}
+
+	for _, d := range data {
+		for k, v := range d {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+// matches checks whether the call matches the expected one. Empty fields of
+// the expected call match any value. Errors are matched using errors.Is, data
+// values are compared using reflect.DeepEqual unless they are Gomega matchers.
+func (actual Call) matches(expected Call) (bool, error) {
+	if actual.Level != expected.Level {
+		return false, nil
+	}
+
+	if expected.Action != "" && actual.Action != expected.Action {
+		return false, nil
+	}
+
+	if expected.Session != "" && actual.Session != expected.Session {
+		return false, nil
+	}
+
+	if expected.Err != nil && !errors.Is(actual.Err, expected.Err) {
+		return false, nil
+	}
+
+	for key, value := range expected.Data {
+		actualValue, found := actual.Data[key]
+		if !found {
+			return false, nil
+		}
+
+		if matcher, ok := value.(types.GomegaMatcher); ok {
+			success, err := matcher.Match(actualValue)
+			if err != nil || !success {
+				return false, err
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(actualValue, value) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+type callMatcher struct {
+	expected []Call
+	matched  int
+}
+
+// HaveRecorded checks that a FakeLogger has recorded the given sequence of
+// calls. Just like with ContainSequence, the calls have to be recorded in the
+// specified order, but other calls may occur in between. Unlike the entries of
+// ContainSequence, data values are compared without being encoded as JSON,
+// i.e. their Go types have to match.
+//
+// Example:
+//
+//	logger := NewFakeLogger("test")
+//	myFunc(logger)
+//
+//	Expect(logger).To(HaveRecorded(
+//	  Call{Level: lager.INFO, Action: "test.start", Data: lager.Data{"count": 3}},
+//	  Call{Level: lager.ERROR, Err: ErrTimeout},
+//	))
+func HaveRecorded(expected ...Call) types.GomegaMatcher {
+	return &callMatcher{
+		expected: expected,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (cm *callMatcher) Match(actual interface{}) (success bool, err error) {
+	calls, err := recordedCalls(actual)
+	if err != nil {
+		return false, err
+	}
+
+	cm.matched = 0
+	for _, call := range calls {
+		if cm.matched == len(cm.expected) {
+			break
+		}
+
+		matches, err := call.matches(cm.expected[cm.matched])
+		if err != nil {
+			return false, err
+		}
+
+		if matches {
+			cm.matched++
+		}
+	}
+
+	return cm.matched == len(cm.expected), nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (cm *callMatcher) FailureMessage(actual interface{}) (message string) {
+	calls, _ := recordedCalls(actual)
+	return fmt.Sprintf(
+		"Expected\n%s\nto contain call sequence\n%s\nCall %d of the sequence has not been recorded.",
+		format.Object(calls, 1),
+		format.Object(cm.expected, 1),
+		cm.matched,
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (cm *callMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	calls, _ := recordedCalls(actual)
+	return fmt.Sprintf(
+		"Expected\n%s\nnot to contain call sequence\n%s",
+		format.Object(calls, 1),
+		format.Object(cm.expected, 1),
+	)
+}
+
+func recordedCalls(actual interface{}) ([]Call, error) {
+	switch x := actual.(type) {
+	case *FakeLogger:
+		return x.Calls(), nil
+	case []Call:
+		return x, nil
+	default:
+		return nil, fmt.Errorf("HaveRecorded must be passed a *glager.FakeLogger or []glager.Call. Got:\n%s", format.Object(actual, 1))
+	}
+}
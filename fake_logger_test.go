@@ -0,0 +1,98 @@
+package glager_test
+
+import (
+	"errors"
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("FakeLogger", func() {
+	var (
+		logger     *FakeLogger
+		errTimeout = errors.New("timeout")
+	)
+
+	BeforeEach(func() {
+		logger = NewFakeLogger("test")
+	})
+
+	It("records every call", func() {
+		session := logger.Session("session", lager.Data{"task": "t1"})
+		session.Info("start", lager.Data{"count": 3})
+		session.WithData(lager.Data{"attempt": 1}).Error("failed", errTimeout)
+		logger.Debug("done")
+
+		Expect(logger.Calls()).To(Equal([]Call{
+			{Level: lager.INFO, Action: "test.session.start", Session: "1", Data: lager.Data{"task": "t1", "count": 3}},
+			{Level: lager.ERROR, Action: "test.session.failed", Session: "1", Err: errTimeout, Data: lager.Data{"task": "t1", "attempt": 1}},
+			{Level: lager.DEBUG, Action: "test.done", Data: lager.Data{}},
+		}))
+	})
+
+	It("records fatal calls and panics", func() {
+		Expect(func() { logger.Fatal("crashed", errTimeout) }).To(Panic())
+		Expect(logger).To(HaveRecorded(Call{Level: lager.FATAL, Action: "test.crashed", Err: errTimeout}))
+	})
+
+	It("numbers nested sessions", func() {
+		logger.Session("a").Session("b").Info("start")
+		Expect(logger.Calls()[0].Session).To(Equal("1.1"))
+		Expect(logger.Calls()[0].Action).To(Equal("test.a.b.start"))
+	})
+
+	Describe(".HaveRecorded", func() {
+		BeforeEach(func() {
+			logger.Info("start", lager.Data{"count": 3, "name": "job"})
+			logger.Debug("retry")
+			logger.Error("failed", fmt.Errorf("wrapped: %w", errTimeout))
+		})
+
+		It("matches a sequence of calls", func() {
+			Expect(logger).To(HaveRecorded(
+				Call{Level: lager.INFO, Action: "test.start", Data: lager.Data{"count": 3}},
+				Call{Level: lager.ERROR, Err: errTimeout},
+			))
+		})
+
+		It("compares data values type-precisely", func() {
+			Expect(logger).ToNot(HaveRecorded(Call{Level: lager.INFO, Data: lager.Data{"count": 3.0}}))
+			Expect(logger).ToNot(HaveRecorded(Call{Level: lager.INFO, Data: lager.Data{"count": "3"}}))
+		})
+
+		It("matches data values using Gomega matchers", func() {
+			Expect(logger).To(HaveRecorded(Call{Level: lager.INFO, Data: lager.Data{"count": BeNumerically(">", 2)}}))
+		})
+
+		It("does not match calls out of order", func() {
+			Expect(logger).ToNot(HaveRecorded(
+				Call{Level: lager.ERROR},
+				Call{Level: lager.INFO},
+			))
+		})
+
+		It("does not match a different error", func() {
+			Expect(logger).ToNot(HaveRecorded(Call{Level: lager.ERROR, Err: errors.New("timeout")}))
+		})
+
+		It("matches recorded calls", func() {
+			Expect(logger.Calls()).To(HaveRecorded(Call{Level: lager.DEBUG, Action: "test.retry"}))
+		})
+
+		It("explains which call has not been recorded", func() {
+			matcher := HaveRecorded(Call{Level: lager.INFO}, Call{Level: lager.FATAL})
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring("Call 1 of the sequence has not been recorded."))
+		})
+
+		It("returns an error for invalid actuals", func() {
+			_, err := HaveRecorded().Match("foo")
+			Expect(err).To(MatchError(HavePrefix("HaveRecorded must be passed a *glager.FakeLogger or []glager.Call.")))
+		})
+	})
+})
@@ -0,0 +1,230 @@
+package glager
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// logFile is a log file that is read every time it is matched.
+type logFile string
+
+// FromFile returns a log file, e.g. one captured from an external process,
+// that can be passed to any of the matchers. The file is read every time it is
+// matched, which makes it suitable for Eventually. An entry that is still being
+// written to the file is ignored until it is complete. Files compressed using
+// gzip are decompressed transparently. Uncompressed files are matched line by
+// line, without loading them into memory, like seekable readers.
+//
+// Example:
+//
+//	Eventually(FromFile("/var/log/app.log")).Should(ContainSequence(
+//	  Info(Action("app.start")),
+//	))
+func FromFile(path string) logFile {
+	return logFile(path)
+}
+
+// read opens the log file for reading its current contents. The returned
+// reader has to be closed using closeContents.
+func (f logFile) read() (io.Reader, error) {
+	return openLogFile(string(f))
+}
+
+// logFiles is a set of log files, given as glob pattern, that is read every
+// time it is matched.
+type logFiles string
+
+// FromFiles returns the log files matching the given glob pattern, e.g. a log
+// and its rotated predecessors, as a single log that can be passed to any of
+// the matchers. The files are concatenated in the order they have been
+// rotated in, i.e. numbered files like "app.log.2" and "app.log.1" come first,
+// highest number first, followed by all other files in lexical order. Files
+// compressed using gzip, e.g. "app.log.2.gz", are decompressed transparently.
+// The files are read every time they are matched, one after the other, without
+// loading them into memory.
+//
+// Example:
+//
+//	Expect(FromFiles("/var/log/app.log*")).To(ContainSequence(
+//	  Info(Action("app.start")),
+//	))
+func FromFiles(pattern string) logFiles {
+	return logFiles(pattern)
+}
+
+// read opens the files for reading their concatenated current contents. The
+// returned reader has to be closed using closeContents.
+func (f logFiles) read() (io.Reader, error) {
+	paths, err := filepath.Glob(string(f))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("FromFiles found no files matching %q.", string(f))
+	}
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		return rotatedBefore(paths[i], paths[j])
+	})
+
+	files := openedFiles{}
+	readers := make([]io.Reader, 0, len(paths))
+	for i, path := range paths {
+		reader, err := openLogFile(path)
+		if err != nil {
+			closeContents(files)
+			return nil, err
+		}
+
+		files.files = append(files.files, reader)
+		if i < len(paths)-1 {
+			reader = &terminatedReader{reader: reader}
+		}
+		readers = append(readers, reader)
+	}
+
+	files.Reader = io.MultiReader(readers...)
+	return files, nil
+}
+
+// openedFiles are log files that have been opened by glager, read one after
+// the other.
+type openedFiles struct {
+	io.Reader
+	files []io.Reader
+}
+
+// terminatedReader reads the contents of the given reader and appends a
+// newline, unless they are empty or end with one already.
+type terminatedReader struct {
+	reader io.Reader
+	last   byte
+	read   bool
+}
+
+// Read implements io.Reader.
+func (r *terminatedReader) Read(p []byte) (int, error) {
+	if r.reader == nil {
+		return 0, io.EOF
+	}
+
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.last, r.read = p[n-1], true
+	}
+	if err != io.EOF {
+		return n, err
+	}
+
+	if r.read && r.last != '\n' {
+		if n == len(p) {
+			// the newline is appended on the next read
+			return n, nil
+		}
+		p[n], r.last = '\n', '\n'
+		n++
+	}
+
+	r.reader = nil
+	return n, io.EOF
+}
+
+// rotatedBefore reports whether the file at the given path has been rotated
+// before the other one, i.e. whether it contains older entries.
+func rotatedBefore(path, other string) bool {
+	n, numbered := rotation(path)
+	m, otherNumbered := rotation(other)
+
+	switch {
+	case numbered && otherNumbered:
+		return n > m
+	case numbered != otherNumbered:
+		return numbered
+	default:
+		return path < other
+	}
+}
+
+// rotation returns the number of a rotated log file, e.g. 2 for "app.log.2"
+// and "app.log.2.gz".
+func rotation(path string) (int, bool) {
+	ext := filepath.Ext(strings.TrimSuffix(path, ".gz"))
+	n, err := strconv.Atoi(strings.TrimPrefix(ext, "."))
+	return n, err == nil && ext != ""
+}
+
+// openedFile is a log file that has been opened by glager, as opposed to files
+// passed as io.Reader, and has to be closed once it has been read.
+type openedFile struct {
+	*os.File
+}
+
+// openedGzipFile is a log file compressed using gzip that has been opened by
+// glager. Reading it decompresses its contents.
+type openedGzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+// Close closes the underlying file.
+func (f openedGzipFile) Close() error {
+	f.Reader.Close()
+	return f.file.Close()
+}
+
+// openLogFile opens the file at the given path for reading, decompressing it
+// while it is read if it has been compressed using gzip.
+func openLogFile(path string) (io.Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	magic := make([]byte, 2)
+	n, err := io.ReadFull(file, magic)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		file.Close()
+		return nil, err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if !bytes.Equal(magic[:n], []byte{0x1f, 0x8b}) {
+		return openedFile{file}, nil
+	}
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return openedGzipFile{Reader: reader, file: file}, nil
+}
+
+// closeContents closes the given reader, as returned by contentsReader, if it
+// has been opened by glager, e.g. for a log file. Other readers are left
+// untouched.
+func closeContents(reader io.Reader) {
+	switch x := reader.(type) {
+	case openedFile:
+		x.Close()
+	case openedGzipFile:
+		x.Close()
+	case openedFiles:
+		for _, file := range x.files {
+			closeContents(file)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package glager_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".FromFile", func() {
+	var (
+		dir    string
+		path   string
+		file   *os.File
+		logger lager.Logger
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "glager")
+		Expect(err).ToNot(HaveOccurred())
+
+		path = filepath.Join(dir, "app.log")
+		file, err = os.Create(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		logger = lager.NewLogger("app")
+		logger.RegisterSink(lager.NewWriterSink(file, lager.DEBUG))
+	})
+
+	AfterEach(func() {
+		file.Close()
+		os.RemoveAll(dir)
+	})
+
+	It("matches the contents of the file", func() {
+		logger.Info("start")
+		logger.Info("ready")
+
+		Expect(FromFile(path)).To(ContainSequence(Info(Action("app.start")), Info(Action("app.ready"))))
+		Expect(FromFile(path)).ToNot(ContainSequence(Info(Action("app.stop"))))
+	})
+
+	It("reads the file every time it is matched", func() {
+		logger.Info("start")
+		Expect(FromFile(path)).ToNot(ContainSequence(Info(Action("app.ready"))))
+
+		go logger.Info("ready")
+		Eventually(FromFile(path)).Should(ContainSequence(Info(Action("app.ready"))))
+	})
+
+	It("ignores an entry that is still being written", func() {
+		logger.Info("start")
+		_, err := file.WriteString(`{"timestamp":"1","source":"app","mess`)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(FromFile(path)).To(ContainSequence(Info(Action("app.start"))))
+		Expect(FromFile(path)).ToNot(ContainSequence(Info(Action("app.stop"))))
+	})
+
+	It("stops reading once the sequence has been matched", func() {
+		logger.Info("start")
+		_, err := file.WriteString("not json\n")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(FromFile(path)).To(ContainSequence(Info(Action("app.start"))))
+
+		_, err = ContainSequence(Info(Action("app.stop"))).Match(FromFile(path))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error if the file cannot be read", func() {
+		_, err := ContainSequence(Info()).Match(FromFile(filepath.Join(path, "missing")))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe(".FromFiles", func() {
+	var dir string
+
+	entry := func(message string) string {
+		return fmt.Sprintf(`{"timestamp":"1","source":"app","message":"app.%s","log_level":1,"data":{}}`+"\n", message)
+	}
+
+	writeFile := func(name, contents string) {
+		Expect(os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644)).To(Succeed())
+	}
+
+	writeGzip := func(name, contents string) {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		_, err := writer.Write([]byte(contents))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+		writeFile(name, buf.String())
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "glager")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("concatenates rotated files in the order they have been rotated in", func() {
+		writeFile("app.log", entry("ready"))
+		writeFile("app.log.1", entry("configured"))
+		writeGzip("app.log.2.gz", entry("start"))
+		writeGzip("app.log.10.gz", entry("boot"))
+
+		Expect(FromFiles(filepath.Join(dir, "app.log*"))).To(ContainExactSequence(
+			Info(Action("app.boot")),
+			Info(Action("app.start")),
+			Info(Action("app.configured")),
+			Info(Action("app.ready")),
+		))
+	})
+
+	It("separates files without a trailing newline", func() {
+		writeFile("app.log.1", strings.TrimSuffix(entry("start"), "\n"))
+		writeFile("app.log", entry("ready"))
+
+		Expect(FromFiles(filepath.Join(dir, "app.log*"))).To(ContainExactSequence(
+			Info(Action("app.start")),
+			Info(Action("app.ready")),
+		))
+	})
+
+	It("returns an error if no files match", func() {
+		pattern := filepath.Join(dir, "*.log")
+		_, err := ContainSequence(Info()).Match(FromFiles(pattern))
+		Expect(err).To(MatchError(fmt.Sprintf("FromFiles found no files matching %q.", pattern)))
+	})
+})
+
+var _ = Describe("compressed log files", func() {
+	It("decompresses files compressed using gzip", func() {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		_, err := writer.Write([]byte(`{"timestamp":"1","source":"app","message":"app.start","log_level":1,"data":{}}` + "\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+
+		dir, err := os.MkdirTemp("", "glager")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "app.log.gz")
+		Expect(os.WriteFile(path, buf.Bytes(), 0644)).To(Succeed())
+
+		Expect(FromFile(path)).To(ContainSequence(Info(Action("app.start"))))
+	})
+})
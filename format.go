@@ -0,0 +1,370 @@
+package glager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// Format converts a single raw line of a log written in some other format into
+// a line in the lager format. Lines for which an empty slice is returned are
+// skipped. Formats are applied to a log using WithFormat, which makes them
+// suitable to preprocess lines in the lager format as well, e.g. to strip
+// prefixes added by some transport.
+type Format func(line []byte) ([]byte, error)
+
+// The levels of log/slog, which are spaced four apart.
+const (
+	slogDebug = -4
+	slogInfo  = 0
+	slogWarn  = 4
+	slogError = 8
+)
+
+// Slog is a Format for logs written by the JSON handler of log/slog. The
+// "time", "level", and "msg" keys are mapped to the timestamp, level, and
+// message of the entry, all other attributes become its data. Levels below
+// INFO are mapped to lager.DEBUG, levels below WARN to lager.INFO, levels
+// below ERROR to LevelWarn, and all others to lager.ERROR. The source of slog
+// entries is always empty.
+func Slog(line []byte) ([]byte, error) {
+	return convertJSON(line, slogKeys, parseSlogLevel)
+}
+
+// Slog returns the Slog format, mapping level names using the given levels
+// first, e.g. to name "TRACE".
+func (l Levels) Slog() Format {
+	return func(line []byte) ([]byte, error) {
+		return convertJSON(line, slogKeys, l.parser(parseSlogLevel))
+	}
+}
+
+// Zap is a Format for logs written by the JSON encoder of zap, e.g. using its
+// production config. The "ts", "level", "msg", and "logger" keys are mapped to
+// the timestamp, level, message, and source of the entry, all other fields,
+// including "caller", become its data. Timestamps can be epoch seconds or
+// ISO8601 strings. The levels debug, info, and error map to their lager
+// counterparts, warn maps to LevelWarn, dpanic to lager.ERROR, and panic and
+// fatal to lager.FATAL.
+func Zap(line []byte) ([]byte, error) {
+	return convertJSON(line, zapKeys, parseZapLevel)
+}
+
+// Zap returns the Zap format, mapping level names using the given levels
+// first, e.g. to alias "dpanic" onto LevelFatal.
+func (l Levels) Zap() Format {
+	return func(line []byte) ([]byte, error) {
+		return convertJSON(line, zapKeys, l.parser(parseZapLevel))
+	}
+}
+
+// Zerolog is a Format for logs written by zerolog using its default field
+// names. The "time", "level", and "message" keys are mapped to the timestamp,
+// level, and message of the entry, all other fields become its data.
+// Timestamps can be epoch seconds or RFC3339 strings. The levels trace and
+// debug map to lager.DEBUG, info to lager.INFO, warn to LevelWarn, error to
+// lager.ERROR, and fatal and panic to lager.FATAL. The source of zerolog
+// entries is always empty.
+func Zerolog(line []byte) ([]byte, error) {
+	return convertJSON(line, zerologKeys, commonLevels("zerolog"))
+}
+
+// Zerolog returns the Zerolog format, mapping level names using the given
+// levels first.
+func (l Levels) Zerolog() Format {
+	return func(line []byte) ([]byte, error) {
+		return convertJSON(line, zerologKeys, l.parser(commonLevels("zerolog")))
+	}
+}
+
+// Logrus is a Format for logs written by logrus using its JSONFormatter with
+// the default field names. The "time", "level", and "msg" keys are mapped to
+// the timestamp, level, and message of the entry, all other fields, including
+// "func" and "file" if the caller is reported, become its data. The levels
+// trace and debug map to lager.DEBUG, info to lager.INFO, warning to
+// LevelWarn, error to lager.ERROR, and fatal and panic to lager.FATAL. The
+// source of logrus entries is always empty.
+func Logrus(line []byte) ([]byte, error) {
+	return convertJSON(line, logrusKeys, commonLevels("logrus"))
+}
+
+// Logrus returns the Logrus format, mapping level names using the given levels
+// first.
+func (l Levels) Logrus() Format {
+	return func(line []byte) ([]byte, error) {
+		return convertJSON(line, logrusKeys, l.parser(commonLevels("logrus")))
+	}
+}
+
+// The keys of the fields of the bundled formats.
+var (
+	slogKeys    = fieldKeys{time: "time", level: "level", message: "msg"}
+	zapKeys     = fieldKeys{time: "ts", level: "level", message: "msg", source: "logger"}
+	zerologKeys = fieldKeys{time: "time", level: "level", message: "message"}
+	logrusKeys  = fieldKeys{time: "time", level: "level", message: "msg"}
+)
+
+// fieldKeys are the keys of the fields of a log entry that are mapped to the
+// fields of a lager entry. All other fields become the data of the entry.
+// If data is set, the data is read from the object under that key instead, all
+// other fields are ignored.
+type fieldKeys struct {
+	time    string
+	level   string
+	message string
+	source  string
+	data    string
+}
+
+// convertJSON converts a JSON log entry into a lager entry.
+func convertJSON(line []byte, keys fieldKeys, parseLevel func(string) (lager.LogLevel, error)) ([]byte, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return nil, err
+	}
+
+	return convertFields(fields, keys, parseLevel)
+}
+
+// convertFields converts the fields of a log entry into a lager entry.
+func convertFields(fields map[string]interface{}, keys fieldKeys, parseLevel func(string) (lager.LogLevel, error)) ([]byte, error) {
+	entry := LogEntry{Data: lager.Data{}}
+
+	for key, value := range fields {
+		switch {
+		case key == keys.time:
+			if seconds, ok := value.(float64); ok {
+				entry.Timestamp = strconv.FormatFloat(seconds, 'f', 9, 64)
+			} else {
+				entry.Timestamp = fmt.Sprint(value)
+			}
+		case key == keys.message:
+			entry.Message = fmt.Sprint(value)
+		case keys.source != "" && key == keys.source:
+			entry.Source = fmt.Sprint(value)
+		case key == keys.level:
+			level, err := parseLevel(fmt.Sprint(value))
+			if err != nil {
+				return nil, err
+			}
+			entry.LogLevel = level
+		case keys.data != "" && key == keys.data:
+			if data, ok := value.(map[string]interface{}); ok {
+				entry.Data = data
+			}
+		case keys.data == "":
+			entry.Data[key] = value
+		}
+	}
+
+	return json.Marshal(entry)
+}
+
+// Schema describes an arbitrary line-delimited JSON log format by the keys of
+// the fields that are mapped to the fields of a lager entry.
+type Schema struct {
+	// TimestampKey is the key of the timestamp. Timestamps can be epoch
+	// seconds or RFC3339 strings.
+	TimestampKey string
+	// LevelKey is the key of the level.
+	LevelKey string
+	// MessageKey is the key of the message.
+	MessageKey string
+	// SourceKey is the key of the source. It is optional.
+	SourceKey string
+	// DataKey is the key of the object holding the data. If it is empty, all
+	// fields that are not mapped otherwise become the data of the entry.
+	DataKey string
+	// LevelValues maps the values of the level field, rendered as strings, to
+	// levels, e.g. {"30": LevelInfo}. If it is nil, the values are parsed
+	// using ParseLevel.
+	LevelValues map[string]Level
+}
+
+// Format returns a Format converting lines of the log format described by the
+// schema.
+func (s Schema) Format() Format {
+	keys := fieldKeys{
+		time:    s.TimestampKey,
+		level:   s.LevelKey,
+		message: s.MessageKey,
+		source:  s.SourceKey,
+		data:    s.DataKey,
+	}
+
+	return func(line []byte) ([]byte, error) {
+		return convertJSON(line, keys, s.parseLevel)
+	}
+}
+
+func (s Schema) parseLevel(value string) (lager.LogLevel, error) {
+	if s.LevelValues == nil {
+		level, err := ParseLevel(value)
+		return level.LogLevel(), err
+	}
+
+	level, found := s.LevelValues[value]
+	if !found {
+		return 0, fmt.Errorf("Invalid log level %q.", value)
+	}
+	return level.LogLevel(), nil
+}
+
+// WithSchema converts a log written in the JSON log format described by the
+// given schema to the lager format, so that it can be passed to any of the
+// matchers. It is a shorthand for WithFormat(schema.Format(), log).
+//
+// Example:
+//
+//	schema := Schema{
+//	  TimestampKey: "@timestamp",
+//	  LevelKey:     "severity",
+//	  MessageKey:   "event",
+//	  LevelValues:  map[string]Level{"INFO": LevelInfo, "ERROR": LevelError},
+//	}
+//	Expect(WithSchema(schema, buffer)).To(ContainSequence(Info(Message("user.login"))))
+func WithSchema(schema Schema, log interface{}) formattedLog {
+	return WithFormat(schema.Format(), log)
+}
+
+// WithDataKey reads the data of the entries of the given log from the given key
+// instead of "data", for log schemas that store structured fields under a
+// different key, e.g. "fields" or "context". The Data option and all matchers
+// work unchanged against such logs. A "data" key of the entries is ignored,
+// other top-level keys are retained as the fields of the entries.
+//
+// Example:
+//
+//	Expect(WithDataKey("fields", buffer)).To(ContainSequence(Info(Data("user", "admin"))))
+func WithDataKey(key string, log interface{}) formattedLog {
+	return WithFormat(func(line []byte) ([]byte, error) {
+		return renameDataKey(line, key)
+	}, log)
+}
+
+// renameDataKey moves the value of the given key of a JSON log entry to "data".
+func renameDataKey(line []byte, key string) ([]byte, error) {
+	if key == "data" {
+		return line, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return nil, err
+	}
+
+	data, found := fields[key]
+	delete(fields, key)
+	delete(fields, "data")
+	if found {
+		fields["data"] = data
+	}
+
+	return json.Marshal(fields)
+}
+
+// parseZapLevel maps a level as rendered by zap, e.g. "info", to the
+// corresponding lager level.
+func parseZapLevel(name string) (lager.LogLevel, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return lager.DEBUG, nil
+	case "info":
+		return lager.INFO, nil
+	case "warn":
+		return LevelWarn.LogLevel(), nil
+	case "error", "dpanic":
+		return lager.ERROR, nil
+	case "panic", "fatal":
+		return lager.FATAL, nil
+	default:
+		return 0, unknownLevel("zap", name)
+	}
+}
+
+// parseSlogLevel parses a level as rendered by log/slog, e.g. "INFO" or
+// "ERROR+2", and maps it to the corresponding lager level.
+func parseSlogLevel(name string) (lager.LogLevel, error) {
+	base, offset := name, 0
+	if i := strings.IndexAny(name, "+-"); i > 0 {
+		n, err := strconv.Atoi(name[i:])
+		if err != nil {
+			return 0, unknownLevel("slog", name)
+		}
+		base, offset = name[:i], n
+	}
+
+	var level int
+	switch strings.ToUpper(base) {
+	case "DEBUG":
+		level = slogDebug
+	case "INFO":
+		level = slogInfo
+	case "WARN":
+		level = slogWarn
+	case "ERROR":
+		level = slogError
+	default:
+		return 0, unknownLevel("slog", name)
+	}
+	level += offset
+
+	switch {
+	case level < slogInfo:
+		return lager.DEBUG, nil
+	case level < slogWarn:
+		return lager.INFO, nil
+	case level < slogError:
+		return LevelWarn.LogLevel(), nil
+	default:
+		return lager.ERROR, nil
+	}
+}
+
+type formattedLog struct {
+	format Format
+	log    interface{}
+}
+
+// WithFormat converts a log written in the given format, e.g. Slog, to the
+// lager format, so that it can be passed to any of the matchers. The log can
+// be anything that is accepted by the ContainSequence matcher. It is only read
+// when matching.
+//
+// Example:
+//
+//	Expect(WithFormat(Slog, buffer)).To(ContainSequence(
+//	  Info(Message("server started"), Data("port", 8080)),
+//	))
+func WithFormat(format Format, log interface{}) formattedLog {
+	return formattedLog{
+		format: format,
+		log:    log,
+	}
+}
+
+// entries reads the log and converts it line by line. A trailing line that is
+// still being written is ignored if the log is live.
+func (f formattedLog) entries() (logEntries, error) {
+	entries, _, err := f.decode()
+	return entries, err
+}
+
+// decode is like entries, but also returns the byte offsets right after each
+// of the entries in the raw lines of the log.
+func (f formattedLog) decode() (logEntries, []int64, error) {
+	reader, err := contentsReader("WithFormat", f.log)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeContents(reader)
+
+	entries, offsets, err := decodeLines(reader, f.format, isLive(f.log))
+	if err != nil {
+		return nil, nil, err
+	}
+	return entries, offsets, nil
+}
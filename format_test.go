@@ -0,0 +1,267 @@
+package glager_test
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("formats", func() {
+	Describe("Slog", func() {
+		var (
+			buffer *gbytes.Buffer
+			logger *slog.Logger
+		)
+
+		BeforeEach(func() {
+			buffer = gbytes.NewBuffer()
+			logger = slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		})
+
+		It("maps slog entries onto lager entries", func() {
+			logger.Debug("connecting", "attempt", 1)
+			logger.Info("server started", "port", 8080, slog.Group("tls", "enabled", true))
+			logger.Warn("slow request")
+			logger.Error("request failed", "error", errors.New("boom"))
+
+			Expect(WithFormat(Slog, buffer)).To(ContainSequence(
+				Debug(Message("connecting"), Data("attempt", 1)),
+				Info(Message("server started"), Data("port", 8080), DataAt("tls.enabled", true)),
+				Warn(Message("slow request")),
+				Error(errors.New("boom"), Message("request failed")),
+			))
+		})
+
+		It("parses the timestamps", func() {
+			logger.Info("server started")
+
+			entries, err := Entries(WithFormat(Slog, buffer))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+
+			_, err = entries[0].Time()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		table.DescribeTable("mapping levels",
+			func(level string, expected lager.LogLevel) {
+				log := strings.NewReader(`{"time":"2024-01-01T00:00:00Z","level":"` + level + `","msg":"test"}` + "\n")
+				Expect(WithFormat(Slog, log)).To(ContainSequence(Entry(expected, Message("test"))))
+			},
+			table.Entry("debug", "DEBUG", lager.DEBUG),
+			table.Entry("below info", "INFO-2", lager.DEBUG),
+			table.Entry("info", "INFO", lager.INFO),
+			table.Entry("below warn", "WARN-1", lager.INFO),
+			table.Entry("warn", "WARN", LevelWarn.LogLevel()),
+			table.Entry("below error", "ERROR-2", LevelWarn.LogLevel()),
+			table.Entry("error", "ERROR", lager.ERROR),
+			table.Entry("above error", "ERROR+4", lager.ERROR),
+		)
+
+		It("returns an error for invalid levels", func() {
+			log := strings.NewReader(`{"level":"LOUD","msg":"test"}` + "\n")
+			_, err := ContainSequence(Info()).Match(WithFormat(Slog, log))
+			Expect(err).To(MatchError(ContainSubstring(`Invalid slog level "LOUD". Use Levels to map it onto a glager level`)))
+		})
+	})
+
+	Describe("Zap", func() {
+		const log = `{"level":"info","ts":1700000000.5,"logger":"api","caller":"server/main.go:42","msg":"server started","port":8080}
+{"level":"warn","ts":"2023-11-14T22:13:21.000Z","logger":"api","msg":"slow request","duration":1.5}
+{"level":"error","ts":1700000002,"logger":"api","caller":"server/handler.go:12","msg":"request failed","error":"boom","stacktrace":"main.handle"}
+`
+
+		It("maps zap entries onto lager entries", func() {
+			Expect(WithFormat(Zap, strings.NewReader(log))).To(ContainSequence(
+				Info(Source("api"), Message("server started"), Data("port", 8080, "caller", "server/main.go:42")),
+				Warn(Message("slow request"), Data("duration", 1.5)),
+				Error(errors.New("boom"), Source("api"), Message("request failed")),
+			))
+		})
+
+		It("parses both epoch and ISO8601 timestamps", func() {
+			entries, err := Entries(WithFormat(Zap, strings.NewReader(log)))
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, entry := range entries {
+				_, err := entry.Time()
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			t, err := entries[0].Time()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(t.UnixNano()).To(Equal(int64(1700000000500000000)))
+		})
+
+		table.DescribeTable("mapping levels",
+			func(level string, expected lager.LogLevel) {
+				log := strings.NewReader(`{"level":"` + level + `","ts":1,"msg":"test"}` + "\n")
+				Expect(WithFormat(Zap, log)).To(ContainSequence(Entry(expected, Message("test"))))
+			},
+			table.Entry("debug", "debug", lager.DEBUG),
+			table.Entry("info", "info", lager.INFO),
+			table.Entry("warn", "warn", LevelWarn.LogLevel()),
+			table.Entry("error", "error", lager.ERROR),
+			table.Entry("dpanic", "dpanic", lager.ERROR),
+			table.Entry("panic", "panic", lager.FATAL),
+			table.Entry("fatal", "fatal", lager.FATAL),
+		)
+
+		It("returns an error for invalid levels", func() {
+			_, err := ContainSequence(Info()).Match(WithFormat(Zap, strings.NewReader(`{"level":"loud","msg":"test"}`+"\n")))
+			Expect(err).To(MatchError(ContainSubstring(`Invalid zap level "loud". Use Levels to map it onto a glager level`)))
+		})
+	})
+
+	Describe("Zerolog", func() {
+		const log = `{"level":"info","service":"api","port":8080,"time":"2024-01-01T00:00:00Z","message":"server started"}
+{"level":"warn","duration":1.5,"time":1704067201,"message":"slow request"}
+{"level":"error","error":"boom","time":"2024-01-01T00:00:02Z","message":"request failed"}
+`
+
+		It("maps zerolog entries onto lager entries", func() {
+			Expect(WithFormat(Zerolog, strings.NewReader(log))).To(ContainSequence(
+				Info(Message("server started"), Data("service", "api", "port", 8080)),
+				Warn(Message("slow request"), Data("duration", 1.5)),
+				Error(errors.New("boom"), Message("request failed")),
+			))
+
+			entries, err := Entries(WithFormat(Zerolog, strings.NewReader(log)))
+			Expect(err).ToNot(HaveOccurred())
+
+			t, err := entries[1].Time()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(t.Unix()).To(Equal(int64(1704067201)))
+		})
+
+		table.DescribeTable("mapping levels",
+			func(level string, expected lager.LogLevel) {
+				log := strings.NewReader(`{"level":"` + level + `","message":"test"}` + "\n")
+				Expect(WithFormat(Zerolog, log)).To(ContainSequence(Entry(expected, Message("test"))))
+			},
+			table.Entry("trace", "trace", lager.DEBUG),
+			table.Entry("debug", "debug", lager.DEBUG),
+			table.Entry("info", "info", lager.INFO),
+			table.Entry("warn", "warn", LevelWarn.LogLevel()),
+			table.Entry("error", "error", lager.ERROR),
+			table.Entry("fatal", "fatal", lager.FATAL),
+			table.Entry("panic", "panic", lager.FATAL),
+		)
+
+		It("returns an error for invalid levels", func() {
+			_, err := ContainSequence(Info()).Match(WithFormat(Zerolog, strings.NewReader(`{"level":"loud","message":"test"}`+"\n")))
+			Expect(err).To(MatchError(ContainSubstring(`Invalid zerolog level "loud". Use Levels to map it onto a glager level`)))
+		})
+	})
+
+	Describe("Logrus", func() {
+		const log = `{"level":"info","msg":"server started","port":8080,"time":"2024-01-01T00:00:00Z"}
+{"file":"/app/handler.go:12","func":"main.handle","level":"warning","msg":"slow request","time":"2024-01-01T00:00:01Z"}
+{"error":"boom","level":"error","msg":"request failed","time":"2024-01-01T00:00:02Z"}
+`
+
+		It("maps logrus entries onto lager entries", func() {
+			Expect(WithFormat(Logrus, strings.NewReader(log))).To(ContainSequence(
+				Info(Message("server started"), Data("port", 8080)),
+				Warn(Message("slow request"), Data("func", "main.handle")),
+				Error(errors.New("boom"), Message("request failed")),
+			))
+		})
+
+		It("returns an error for invalid levels", func() {
+			_, err := ContainSequence(Info()).Match(WithFormat(Logrus, strings.NewReader(`{"level":"loud","msg":"test"}`+"\n")))
+			Expect(err).To(MatchError(ContainSubstring(`Invalid logrus level "loud". Use Levels to map it onto a glager level`)))
+		})
+	})
+
+	Describe("Schema", func() {
+		const log = `{"@timestamp":"2024-01-01T00:00:00Z","severity":"INFO","event":"user.login","service":"auth","user":"admin"}
+{"@timestamp":"2024-01-01T00:00:01Z","severity":"ERROR","event":"user.locked","service":"auth","user":"guest"}
+`
+
+		var schema Schema
+
+		BeforeEach(func() {
+			schema = Schema{
+				TimestampKey: "@timestamp",
+				LevelKey:     "severity",
+				MessageKey:   "event",
+				SourceKey:    "service",
+				LevelValues:  map[string]Level{"INFO": LevelInfo, "ERROR": LevelError},
+			}
+		})
+
+		It("maps the fields according to the schema", func() {
+			Expect(WithSchema(schema, strings.NewReader(log))).To(ContainSequence(
+				Info(Source("auth"), Message("user.login"), ExactData("user", "admin")),
+				Error(AnyErr, Source("auth"), Message("user.locked"), Data("user", "guest")),
+			))
+		})
+
+		It("parses level names if no level values are given", func() {
+			schema.LevelValues = nil
+			Expect(WithSchema(schema, strings.NewReader(log))).To(ContainSequence(Info(), Error(AnyErr)))
+		})
+
+		It("maps numeric levels", func() {
+			schema = Schema{TimestampKey: "time", LevelKey: "level", MessageKey: "msg", LevelValues: map[string]Level{"30": LevelInfo}}
+			Expect(WithSchema(schema, strings.NewReader(`{"time":1,"level":30,"msg":"started"}`+"\n"))).To(ContainSequence(Info(Message("started"))))
+		})
+
+		It("reads the data from the data key", func() {
+			schema.DataKey = "context"
+			log := `{"severity":"INFO","event":"user.login","user":"ignored","context":{"user":"admin"}}` + "\n"
+			Expect(WithSchema(schema, strings.NewReader(log))).To(ContainSequence(Info(ExactData("user", "admin"))))
+		})
+
+		It("returns an error for unknown level values", func() {
+			_, err := ContainSequence(Info()).Match(WithSchema(schema, strings.NewReader(`{"severity":"LOUD"}`+"\n")))
+			Expect(err).To(MatchError(ContainSubstring(`Invalid log level "LOUD"`)))
+		})
+	})
+
+	Describe("WithDataKey", func() {
+		const log = `{"timestamp":"1","source":"test","message":"test.start","log_level":1,"data":{"ignored":true},"fields":{"user":"admin"},"host":"vm-1"}` + "\n"
+
+		It("reads the data from the given key", func() {
+			Expect(WithDataKey("fields", strings.NewReader(log))).To(ContainSequence(Info(Data("user", "admin"))))
+			Expect(WithDataKey("fields", strings.NewReader(log))).ToNot(ContainSequence(Info(Data("ignored", true))))
+		})
+
+		It("retains other top-level keys", func() {
+			Expect(WithDataKey("fields", strings.NewReader(log))).To(ContainSequence(Info(TopLevelField("host", "vm-1"))))
+		})
+
+		It("only affects the given log", func() {
+			Expect(strings.NewReader(log)).To(ContainSequence(Info(Data("ignored", true))))
+
+			entries, err := Entries(strings.NewReader(log))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries[0].Fields).To(HaveKeyWithValue("fields", map[string]interface{}{"user": "admin"}))
+		})
+	})
+
+	Describe("WithFormat", func() {
+		It("ignores a trailing line that is still being written to a live log", func() {
+			buffer := gbytes.NewBuffer()
+			buffer.Write([]byte(`{"level":"INFO","msg":"first"}` + "\n" + `{"level":"INFO","ms`))
+
+			Expect(WithFormat(Slog, buffer)).To(ContainSequence(Info(Message("first"))))
+		})
+
+		It("can be passed to other matchers", func() {
+			log := strings.NewReader(`{"level":"INFO","msg":"first"}` + "\n")
+			Expect(WithFormat(Slog, log)).To(HaveNoEntriesBelow(lager.INFO))
+			Expect(WithFormat(Slog, strings.NewReader(`{"level":"INFO","msg":"first"}`+"\n"))).To(BeNDJSON())
+		})
+	})
+})
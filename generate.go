@@ -0,0 +1,163 @@
+package glager
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// Generator produces randomized lager log entries and streams. Use it to
+// property test code that processes lager logs, or to fuzz parsers, including
+// glager's own. Generators with the same seed produce the same entries.
+type Generator struct {
+	rand *rand.Rand
+	time time.Time
+}
+
+// NewGenerator returns a generator seeded with the given seed.
+func NewGenerator(seed int64) *Generator {
+	return &Generator{
+		rand: rand.New(rand.NewSource(seed)),
+		time: time.Unix(1500000000, 0),
+	}
+}
+
+var (
+	generatedSources = []string{"app", "api", "worker", "db"}
+	generatedActions = []string{"start", "request", "retry", "done", "failed"}
+	generatedKeys    = []string{"id", "count", "url", "ok", "tags", "payload", "msg"}
+	generatedRunes   = []rune("abcXYZ019 _-.:/\"\\\n\té✓")
+	generatedLevels  = []lager.LogLevel{lager.DEBUG, lager.INFO, lager.ERROR, lager.FATAL}
+)
+
+// Entry returns a valid random entry. Timestamps of subsequent entries are
+// strictly increasing.
+func (g *Generator) Entry() LogEntry {
+	g.time = g.time.Add(time.Duration(1+g.rand.Intn(1000)) * time.Millisecond)
+
+	source := g.pick(generatedSources)
+	entry := LogEntry{
+		Timestamp: fmt.Sprintf("%d.%09d", g.time.Unix(), g.time.Nanosecond()),
+		Source:    source,
+		Message:   source + "." + g.pick(generatedActions),
+		LogLevel:  generatedLevels[g.rand.Intn(len(generatedLevels))],
+		Data:      lager.Data{},
+	}
+
+	for i := g.rand.Intn(4); i > 0; i-- {
+		entry.Data[g.pick(generatedKeys)] = g.value(2)
+	}
+
+	if entry.LogLevel >= lager.ERROR {
+		entry.Data["error"] = g.string()
+	}
+
+	return entry
+}
+
+// Entries returns n valid random entries.
+func (g *Generator) Entries(n int) []LogEntry {
+	entries := make([]LogEntry, n)
+	for i := range entries {
+		entries[i] = g.Entry()
+	}
+	return entries
+}
+
+// Stream returns n valid random entries encoded as newline-delimited JSON.
+func (g *Generator) Stream(n int) []byte {
+	stream, err := EncodeNDJSON(g.Entries(n))
+	if err != nil {
+		panic(err)
+	}
+	return stream
+}
+
+// MalformedLine returns a random line that cannot be decoded as a lager entry,
+// e.g. a truncated entry, plain text, or an entry with fields of the wrong
+// type. The line is terminated by a newline.
+func (g *Generator) MalformedLine() []byte {
+	valid := bytes.TrimSuffix(g.Stream(1), []byte("\n"))
+
+	var line []byte
+	switch g.rand.Intn(4) {
+	case 0:
+		line = valid[:1+g.rand.Intn(len(valid)-1)]
+	case 1:
+		line = []byte("panic: " + g.string())
+	case 2:
+		line = []byte(`{"timestamp":"1.0","source":"app","message":"app.start","log_level":"info","data":{}}`)
+	default:
+		line = []byte(`[` + strconv.Quote(g.string()) + `]`)
+	}
+
+	return append(line, '\n')
+}
+
+// MalformedStream returns a stream of n valid random entries, one of which
+// has been replaced by a malformed line. Decoding the stream always fails.
+func (g *Generator) MalformedStream(n int) []byte {
+	if n < 1 {
+		n = 1
+	}
+
+	broken := g.rand.Intn(n)
+
+	buf := &bytes.Buffer{}
+	for i := 0; i < n; i++ {
+		if i == broken {
+			buf.Write(g.MalformedLine())
+			continue
+		}
+		buf.Write(g.Stream(1))
+	}
+	return buf.Bytes()
+}
+
+func (g *Generator) pick(values []string) string {
+	return values[g.rand.Intn(len(values))]
+}
+
+func (g *Generator) string() string {
+	runes := make([]rune, g.rand.Intn(12))
+	for i := range runes {
+		runes[i] = generatedRunes[g.rand.Intn(len(generatedRunes))]
+	}
+	return string(runes)
+}
+
+// value returns a random JSON value, nesting arrays and objects up to the
+// given depth.
+func (g *Generator) value(depth int) interface{} {
+	kinds := 4
+	if depth > 0 {
+		kinds = 6
+	}
+
+	switch g.rand.Intn(kinds) {
+	case 0:
+		return g.string()
+	case 1:
+		return float64(g.rand.Intn(100000))
+	case 2:
+		return g.rand.Intn(2) == 0
+	case 3:
+		return nil
+	case 4:
+		values := make([]interface{}, g.rand.Intn(3))
+		for i := range values {
+			values[i] = g.value(depth - 1)
+		}
+		return values
+	default:
+		values := map[string]interface{}{}
+		for i := g.rand.Intn(3); i > 0; i-- {
+			values[g.pick(generatedKeys)] = g.value(depth - 1)
+		}
+		return values
+	}
+}
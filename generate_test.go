@@ -0,0 +1,75 @@
+package glager_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("Generator", func() {
+	It("generates the same entries for the same seed", func() {
+		Expect(NewGenerator(42).Entries(20)).To(Equal(NewGenerator(42).Entries(20)))
+		Expect(NewGenerator(42).Entries(20)).ToNot(Equal(NewGenerator(43).Entries(20)))
+	})
+
+	It("generates entries with increasing timestamps", func() {
+		entries := NewGenerator(1).Entries(50)
+		for i := 1; i < len(entries); i++ {
+			previous, err := entries[i-1].Time()
+			Expect(err).ToNot(HaveOccurred())
+
+			current, err := entries[i].Time()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(current).To(BeTemporally(">", previous))
+		}
+	})
+
+	It("generates valid streams", func() {
+		for seed := int64(0); seed < 50; seed++ {
+			generator := NewGenerator(seed)
+			stream := generator.Stream(20)
+
+			Expect(bytes.NewReader(stream)).To(BeNDJSON())
+
+			entries, err := Entries(bytes.NewReader(stream))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(Equal(NewGenerator(seed).Entries(20)))
+		}
+	})
+
+	It("generates malformed streams", func() {
+		for seed := int64(0); seed < 50; seed++ {
+			_, err := Entries(bytes.NewReader(NewGenerator(seed).MalformedStream(10)))
+			Expect(err).To(HaveOccurred(), "seed %d", seed)
+		}
+	})
+})
+
+func FuzzEntries(f *testing.F) {
+	generator := NewGenerator(0)
+	for i := 0; i < 10; i++ {
+		f.Add(generator.Stream(5))
+		f.Add(generator.MalformedStream(5))
+	}
+
+	f.Fuzz(func(t *testing.T, stream []byte) {
+		entries, err := Entries(bytes.NewReader(stream))
+		if err != nil {
+			return
+		}
+
+		encoded, err := EncodeNDJSON(entries)
+		if err != nil {
+			t.Fatalf("failed to encode decoded entries: %s", err)
+		}
+
+		if _, err := Entries(bytes.NewReader(encoded)); err != nil {
+			t.Fatalf("failed to decode encoded entries: %s", err)
+		}
+	})
+}
@@ -9,6 +9,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/url"
+	"regexp"
+	"strings"
 
 	"code.cloudfoundry.org/lager"
 	"github.com/onsi/gomega/format"
@@ -16,13 +19,80 @@ import (
 	"github.com/onsi/gomega/types"
 )
 
-type logEntry lager.LogFormat
+type logEntry struct {
+	lager.LogFormat
+	checks   []entryCheck
+	captures []func(actual LogEntry)
+	patterns []*regexp.Regexp
+	repeated repetition
 
-type logEntries []logEntry
+	negated      bool
+	absentBefore []logEntry
+	absentAfter  []logEntry
+
+	alternatives []logEntry
+	immediate    bool
+	atLeast      bool
+	ignoredData  map[string]bool
+}
+
+// ExpectedEntry is a log entry specification as returned by Info, Debug,
+// Error, Fatal, and Entry. Use it to build your own matchers on top of glager's
+// matching logic, see LogEntry.Matches and ScanSequence.
+type ExpectedEntry = logEntry
+
+// SequenceEntry is an expected entry of a sequence, as accepted by
+// ContainSequence, HaveLogged, BeginWith, EndWith, and the functions scanning
+// for sequences. Every ExpectedEntry is a SequenceEntry, and so are the entries
+// returned by Repeated, Immediately, and Without. The latter only make sense as
+// part of a sequence, i.e. they cannot be passed to functions and matchers that
+// match single entries.
+type SequenceEntry interface {
+	sequenceEntry() logEntry
+}
+
+// RequiredEntry is a SequenceEntry that has to appear in the log, i.e. any
+// entry but the ones returned by Without. ContainExactSequence and MockLogger
+// accept RequiredEntries.
+type RequiredEntry interface {
+	SequenceEntry
+	requiredEntry()
+}
+
+// UnorderedEntry is a RequiredEntry whose position in the log does not matter,
+// i.e. any entry but the ones returned by Without and Immediately. ContainAll
+// and ContainOnly accept UnorderedEntries.
+type UnorderedEntry interface {
+	RequiredEntry
+	unorderedEntry()
+}
+
+func (entry logEntry) sequenceEntry() logEntry { return entry }
+func (entry logEntry) requiredEntry()          {}
+func (entry logEntry) unorderedEntry()         {}
+
+// sequenceOf returns the expected entries of the given sequence.
+func sequenceOf[T SequenceEntry](sequence []T) []logEntry {
+	entries := make([]logEntry, len(sequence))
+	for i, entry := range sequence {
+		entries[i] = entry.sequenceEntry()
+	}
+	return entries
+}
+
+type entryCheck struct {
+	description string
+	match       func(actual LogEntry) (bool, error)
+}
+
+type logEntries []LogEntry
 
 type logEntryData lager.Data
 
-type option func(*logEntry)
+// Option specifies an expected property of a log entry. Options are passed to
+// Info, Debug, Error, Fatal, and Entry. Write your own options by composing the
+// built-in ones or by using Check.
+type Option func(*logEntry)
 
 // TestLogger embedds an actual lager logger and implements gbytes.BufferProvider.
 // This comes in handy when used with the HaveLogged matcher.
@@ -34,9 +104,15 @@ type TestLogger struct {
 // NewLogger returns a new TestLogger that can be used with the HaveLogged matcher.
 // The returned logger uses log level lager.DEBUG.
 func NewLogger(component string) *TestLogger {
+	return NewLoggerWithLevel(component, lager.DEBUG)
+}
+
+// NewLoggerWithLevel returns a new TestLogger that can be used with the
+// HaveLogged matcher. The returned logger uses the specified minimum log level.
+func NewLoggerWithLevel(component string, minLevel lager.LogLevel) *TestLogger {
 	buf := gbytes.NewBuffer()
 	log := lager.NewLogger(component)
-	log.RegisterSink(lager.NewWriterSink(buf, lager.DEBUG))
+	log.RegisterSink(lager.NewWriterSink(buf, minLevel))
 	return &TestLogger{log, buf}
 }
 
@@ -46,8 +122,17 @@ func (l *TestLogger) Buffer() *gbytes.Buffer {
 }
 
 type logMatcher struct {
-	actual   logEntries
-	expected logEntries
+	actual     logEntries
+	expected   []logEntry
+	mismatches []string
+	matched    []int
+	verbosity  *Verbosity
+	differ     Differ
+	stream     *streamedLog
+	polls      []poll
+	contiguous bool
+	anchor     anchor
+	closest    string
 }
 
 // HaveLogged is an alias for ContainSequence. It checks if the specified entries
@@ -82,7 +167,7 @@ type logMatcher struct {
 // 		   Data("event", "done"),
 // 	   ),
 //   ))
-func HaveLogged(expectedSequence ...logEntry) types.GomegaMatcher {
+func HaveLogged(expectedSequence ...SequenceEntry) types.GomegaMatcher {
 	return ContainSequence(expectedSequence...)
 }
 
@@ -116,21 +201,46 @@ func HaveLogged(expectedSequence ...logEntry) types.GomegaMatcher {
 // 		   Data("event", "done"),
 // 	   ),
 //   ))
-func ContainSequence(expectedSequence ...logEntry) types.GomegaMatcher {
+func ContainSequence(expectedSequence ...SequenceEntry) types.GomegaMatcher {
+	return &logMatcher{
+		expected: compileSequence(sequenceOf(expectedSequence)),
+	}
+}
+
+// ContainExactSequence checks if the specified entries appear inside the log
+// back-to-back, i.e. in the right order and without any other entries in
+// between. Use it to verify that nothing has been logged between two entries.
+// The sequence may start at any position in the log.
+//
+// Example:
+//
+//	Expect(logger).To(ContainExactSequence(
+//	  Info(Action("test.lock.acquired")),
+//	  Info(Action("test.lock.released")),
+//	))
+func ContainExactSequence(expectedSequence ...RequiredEntry) types.GomegaMatcher {
 	return &logMatcher{
-		expected: expectedSequence,
+		expected:   expandRepetitions(sequenceOf(expectedSequence)),
+		contiguous: true,
 	}
 }
 
 // Info returns a log entry of type lager.INFO that can be used with the
 // HaveLogged and ContainSequence matchers.
-func Info(options ...option) logEntry {
+func Info(options ...Option) logEntry {
 	return Entry(lager.INFO, options...)
 }
 
+// Warn returns a log entry of type LevelWarn that can be used with the
+// HaveLogged and ContainSequence matchers. lager itself does not log warnings,
+// use it for logs written in other formats, e.g. WithFormat(Zap, buffer).
+func Warn(options ...Option) logEntry {
+	return Entry(LevelWarn.LogLevel(), options...)
+}
+
 // Debug returns a log entry of type lager.DEBUG that can be used with the
 // HaveLogged and ContainSequence matchers.
-func Debug(options ...option) logEntry {
+func Debug(options ...Option) logEntry {
 	return Entry(lager.DEBUG, options...)
 }
 
@@ -139,7 +249,7 @@ var AnyErr error = nil
 
 // Error returns a log entry of type lager.ERROR that can be used with the
 // HaveLogged and ContainSequence matchers.
-func Error(err error, options ...option) logEntry {
+func Error(err error, options ...Option) logEntry {
 	if err != nil {
 		options = append(options, Data("error", err.Error()))
 	}
@@ -149,7 +259,7 @@ func Error(err error, options ...option) logEntry {
 
 // Fatal returns a log entry of type lager.FATAL that can be used with the
 // HaveLogged and ContainSequence matchers.
-func Fatal(err error, options ...option) logEntry {
+func Fatal(err error, options ...Option) logEntry {
 	if err != nil {
 		options = append(options, Data("error", err.Error()))
 	}
@@ -158,22 +268,25 @@ func Fatal(err error, options ...option) logEntry {
 }
 
 // Entry returns a log entry for the specified log level that can be used with
-// the HaveLogged and ContainSequence matchers.
-func Entry(logLevel lager.LogLevel, options ...option) logEntry {
-	entry := logEntry(lager.LogFormat{
-		LogLevel: logLevel,
-		Data:     lager.Data{},
-	})
+// the HaveLogged and ContainSequence matchers. Use it for custom levels, see
+// Levels.
+func Entry(logLevel lager.LogLevel, options ...Option) logEntry {
+	entry := logEntry{
+		LogFormat: lager.LogFormat{
+			LogLevel: logLevel,
+			Data:     lager.Data{},
+		},
+	}
 
-	for _, option := range options {
-		option(&entry)
+	for _, apply := range options {
+		apply(&entry)
 	}
 
 	return entry
 }
 
 // Message specifies a string that represent the message of a given log entry.
-func Message(msg string) option {
+func Message(msg string) Option {
 	return func(e *logEntry) {
 		e.Message = msg
 	}
@@ -181,14 +294,14 @@ func Message(msg string) option {
 
 // Action is an alias for Message, lager uses the term action is used
 // alternatively to message.
-func Action(action string) option {
+func Action(action string) Option {
 	return Message(action)
 }
 
 // Source specifies a string that indicates the log source. The source of a
 // lager logger is usually specified at instantiation time. Source is sometimes
 // also called component.
-func Source(src string) option {
+func Source(src string) Option {
 	return func(e *logEntry) {
 		e.Source = src
 	}
@@ -196,7 +309,22 @@ func Source(src string) option {
 
 // Data specifies the data logged by a given log entry. Arguments are specified
 // as an alternating sequence of keys (string) and values (interface{}).
-func Data(kv ...interface{}) option {
+// Alternatively, a single map with string keys, e.g. lager.Data, or a struct can
+// be passed. All of its keys, or all fields as they are encoded to JSON,
+// respectively, are then expected to be logged. Just like with key value
+// pairs, the entry may contain further keys.
+//
+// Example:
+//
+//	Info(Data(lager.Data{"user": "admin", "attempt": 2}))
+//	Info(Data(request{Method: "GET", Path: "/"}))
+func Data(kv ...interface{}) Option {
+	if len(kv) == 1 {
+		if pairs, ok := dataPairs(kv[0]); ok {
+			kv = pairs
+		}
+	}
+
 	if len(kv)%2 == 1 {
 		kv = append(kv, "")
 	}
@@ -215,52 +343,73 @@ func Data(kv ...interface{}) option {
 
 // ContentsProvider implements Contents function.
 type ContentsProvider interface {
-	// Contents returns a slice of bytes.
+	// Contents returns a slice of bytes. Implementations that are written to
+	// while being matched, e.g. using Eventually, must synchronize Contents
+	// with their writers, like gbytes.Buffer and ConcurrentBuffer do. The
+	// returned slice is copied before it is parsed.
 	Contents() []byte
 }
 
 // Match is doing the actual matching for a given log assertion.
 func (lm *logMatcher) Match(actual interface{}) (success bool, err error) {
-	var reader io.Reader
+	lm.mismatches = nil
+	lm.closest = ""
+	lm.stream = nil
 
-	switch x := actual.(type) {
-	case gbytes.BufferProvider:
-		reader = bytes.NewReader(x.Buffer().Contents())
-	case ContentsProvider:
-		reader = bytes.NewReader(x.Contents())
-	case io.Reader:
-		reader = x
-	default:
-		return false, fmt.Errorf("ContainSequence must be passed an io.Reader, glager.ContentsProvider, or gbytes.BufferProvider. Got:\n%s", format.Object(actual, 1))
+	streamed, err := lm.matchSeekable(actual)
+	if err != nil {
+		return false, err
 	}
 
-	decoder := json.NewDecoder(reader)
-
-	lm.actual = logEntries{}
+	if !streamed {
+		lm.actual, err = parseEntries(lm.name(), actual)
+		if err != nil {
+			return false, err
+		}
 
-	for {
-		var entry logEntry
-		if err := decoder.Decode(&entry); err == io.EOF {
-			break
-		} else if err != nil {
+		switch {
+		case lm.contiguous:
+			lm.matched, err = lm.actual.matchContiguousSequence(lm.expected)
+		case lm.anchor != anchorNone:
+			lm.matched, err = lm.actual.matchAnchoredSequence(lm.expected, lm.anchor)
+		default:
+			lm.matched, err = lm.actual.matchSequence(lm.expected)
+		}
+		if err != nil {
 			return false, err
 		}
-		lm.actual = append(lm.actual, entry)
 	}
 
-	actualEntries := lm.actual
-
-	for _, expected := range lm.expected {
-		i, found, err := actualEntries.indexOf(expected)
+	if !streamed && len(lm.matched) < len(lm.expected) {
+		candidates, offset := lm.candidates()
+		lm.mismatches, err = candidates.dataMismatches(lm.expected[len(lm.matched)], offset, lm.differ)
 		if err != nil {
 			return false, err
 		}
 
-		if !found {
-			return false, nil
+		if len(lm.mismatches) == 0 {
+			lm.closest, err = candidates.closest(lm.expected[len(lm.matched)], offset)
 		}
 
-		actualEntries = actualEntries[i+1:]
+		if err == nil && len(lm.mismatches) == 0 && lm.closest == "" {
+			lm.closest, err = lm.forbidden()
+		}
+	}
+
+	lm.recordPoll()
+
+	if err != nil || len(lm.matched) < len(lm.expected) {
+		return false, err
+	}
+
+	for n := range lm.matched {
+		lm.expected[n].capture(lm.matchedEntry(n))
+	}
+
+	if cursor, ok := actual.(*Cursor); ok && len(lm.matched) > 0 {
+		if err := cursor.skip(lm.divergence()); err != nil {
+			return false, err
+		}
 	}
 
 	return true, nil
@@ -268,67 +417,486 @@ func (lm *logMatcher) Match(actual interface{}) (success bool, err error) {
 
 // FailureMessage constructs a message for failed assertions.
 func (lm *logMatcher) FailureMessage(actual interface{}) (message string) {
-	return fmt.Sprintf(
-		"Expected\n\t%s\nto contain log sequence \n\t%s",
-		format.Object(lm.actual, 0),
-		format.Object(lm.expected, 0),
-	)
+	verbosity := lm.currentVerbosity()
+	if len(lm.matched) >= len(lm.expected) {
+		verbosity = VerbosityFull
+	}
+
+	switch verbosity {
+	case VerbositySummary:
+		message = fmt.Sprintf(
+			"Expected log to contain entry %d of %s\n\t%s\nafter log entry %d",
+			len(lm.matched),
+			lm.sequenceName(),
+			lm.expected[len(lm.matched)].GomegaString(),
+			lm.divergence(),
+		)
+	case VerbosityWindow:
+		message = fmt.Sprintf(
+			"Expected\n\t%s\nto contain %s\n\t%s",
+			lm.renderWindow(),
+			lm.sequenceName(),
+			renderSequence(lm.expected),
+		)
+	default:
+		message = fmt.Sprintf(
+			"Expected\n\t%s\nto contain %s\n\t%s",
+			lm.renderActual(),
+			lm.sequenceName(),
+			renderSequence(lm.expected),
+		)
+	}
+
+	if len(lm.mismatches) > 0 {
+		message += fmt.Sprintf("\nData mismatches:\n\t%s", strings.Join(lm.mismatches, "\n\t"))
+	}
+
+	message += lm.closest
+
+	if len(lm.matched) < len(lm.expected) {
+		message += lm.summary()
+	}
+
+	if lm.polled() {
+		message += lm.timeline()
+	}
+
+	return message
 }
 
 // NegatedFailureMessage constructs a message for failed negative assertions.
 func (lm *logMatcher) NegatedFailureMessage(actual interface{}) (message string) {
-	return fmt.Sprintf(
-		"Expected\n\t%s\nnot to contain log sequence \n\t%s",
-		format.Object(lm.actual, 0),
-		format.Object(lm.expected, 0),
+	message = fmt.Sprintf(
+		"Expected\n\t%s\nnot to contain %s\n\t%s",
+		lm.renderActual(),
+		lm.sequenceName(),
+		renderSequence(lm.expected),
 	)
+
+	if len(lm.matched) > 0 {
+		matches := make([]string, len(lm.matched))
+		for n, i := range lm.matched {
+			matches[n] = fmt.Sprintf("entry %d: %s", i, lm.matchedEntry(n).GoString())
+		}
+		message += fmt.Sprintf("\nMatching entries:\n\t%s", strings.Join(matches, "\n\t"))
+	}
+
+	return message
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (lm *logMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+func (lm *logMatcher) name() string {
+	switch {
+	case lm.contiguous:
+		return "ContainExactSequence"
+	case lm.anchor == anchorStart:
+		return "BeginWith"
+	case lm.anchor == anchorEnd:
+		return "EndWith"
+	default:
+		return "ContainSequence"
+	}
+}
+
+func (lm *logMatcher) sequenceName() string {
+	switch {
+	case lm.contiguous:
+		return "contiguous log sequence"
+	case lm.anchor == anchorStart:
+		return "log sequence at its start"
+	case lm.anchor == anchorEnd:
+		return "log sequence at its end"
+	default:
+		return "log sequence"
+	}
+}
+
+// renderActual renders the actual log for failure messages. Logs that have
+// been streamed are rendered around the point of divergence, as the other
+// entries have not been retained.
+func (lm *logMatcher) renderActual() string {
+	if lm.stream != nil {
+		return lm.renderWindow()
+	}
+	return format.Object(lm.actual, 0)
+}
+
+// entries returns the number of actual entries.
+func (lm *logMatcher) entries() int {
+	if lm.stream != nil {
+		return lm.stream.entries
+	}
+	return len(lm.actual)
+}
+
+// matchedEntry returns the actual entry that matched the nth expected entry.
+func (lm *logMatcher) matchedEntry(n int) LogEntry {
+	if lm.stream != nil {
+		return lm.stream.matched[n]
+	}
+	return lm.actual[lm.matched[n]]
+}
+
+// candidates returns the actual entries the first expected entry that has not
+// been matched could have matched, along with the index of the first of them.
+func (lm *logMatcher) candidates() (logEntries, int) {
+	offset := lm.divergence()
+	candidates := lm.actual[offset:]
+
+	switch {
+	case len(candidates) == 0:
+	case lm.contiguous && len(lm.matched) > 0:
+		// the next expected entry has to be the very next actual entry
+		return candidates[:1], offset
+	case lm.anchor == anchorStart && len(lm.matched) == 0:
+		return candidates[:1], offset
+	case lm.anchor == anchorEnd && len(lm.matched) == len(lm.expected)-1:
+		return candidates[len(candidates)-1:], len(lm.actual) - 1
+	}
+
+	return candidates, offset
+}
+
+// renderSequence renders the expected entries one per line. The rendering is
+// deterministic, i.e. data keys are sorted and values are normalized, so that
+// failure messages are identical across runs.
+func renderSequence(expected []logEntry) string {
+	if len(expected) == 0 {
+		return "<empty sequence>"
+	}
+
+	lines := make([]string, len(expected))
+	for i, entry := range expected {
+		lines[i] = fmt.Sprintf("%d: %s", i, entry.GomegaString())
+	}
+	return strings.Join(lines, "\n\t")
+}
+
+func parseEntries(matcher string, actual interface{}) (logEntries, error) {
+	entries, _, err := parseOffsets(matcher, actual)
+	return entries, err
+}
+
+// parseOffsets is like parseEntries, but also returns the byte offsets right
+// after each of the entries in the raw log. The offsets are nil for logs that
+// have no raw contents of their own, e.g. slices of entries or merged logs.
+func parseOffsets(matcher string, actual interface{}) (logEntries, []int64, error) {
+	switch x := actual.(type) {
+	case logEntries:
+		return x, nil, nil
+	case []LogEntry:
+		return x, nil, nil
+	case mergedLog:
+		entries, err := x.entries()
+		return entries, nil, err
+	case *Stream:
+		entries, err := x.parse()
+		return entries, nil, err
+	case formattedLog:
+		return x.decode()
+	case otlpLog:
+		entries, err := x.entries()
+		return entries, nil, err
+	}
+
+	reader, err := contentsReader(matcher, actual)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeContents(reader)
+
+	entries, offsets, err := decodeEntries(reader)
+	if err == io.ErrUnexpectedEOF && isLive(actual) {
+		// the last entry is still being written, ignore it until it is complete
+		return entries, offsets, nil
+	}
+
+	return entries, offsets, err
+}
+
+// isLive reports whether the given actual is a buffer that might still be
+// written to while being matched, e.g. when using Eventually.
+func isLive(actual interface{}) bool {
+	switch actual.(type) {
+	case gbytes.BufferProvider, ContentsProvider, RemoteLog, *url.URL, logFile, logFiles:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeEntries decodes all entries of the given reader. Along with the entries
+// it returns the byte offsets right after each of them. In case of an error,
+// the entries that have been decoded so far are returned.
+func decodeEntries(reader io.Reader) (logEntries, []int64, error) {
+	// the contents are retained to report lines that cannot be parsed, unless
+	// they can be read again, e.g. from a file
+	readerAt, base, rereadable := rereadableReader(reader)
+
+	var raw *bytes.Buffer
+	input := reader
+	if !rereadable {
+		raw = &bytes.Buffer{}
+		if sized, ok := reader.(interface{ Len() int }); ok {
+			// spare growing the buffer step by step, e.g. for a *bytes.Reader
+			raw.Grow(sized.Len())
+		}
+		input = io.TeeReader(reader, raw)
+	}
+	decoder := json.NewDecoder(input)
+
+	entries := logEntries{}
+	offsets := []int64{}
+
+	for {
+		var entry LogEntry
+		if err := decoder.Decode(&entry); err == io.EOF {
+			break
+		} else if err == io.ErrUnexpectedEOF {
+			return entries, offsets, err
+		} else if err != nil {
+			var start int64
+			if len(offsets) > 0 {
+				start = offsets[len(offsets)-1]
+			}
+			if !rereadable {
+				return entries, offsets, unparseableLine(raw.Bytes(), start, err)
+			}
+			return entries, offsets, unparseableLineAt(readerAt, base, start, err)
+		}
+		entries = append(entries, entry)
+		offsets = append(offsets, decoder.InputOffset())
+	}
+
+	return entries, offsets, nil
+}
+
+// rereadableReader returns the given reader as io.ReaderAt, along with its
+// current offset, if it can be read again without retaining its contents.
+func rereadableReader(reader io.Reader) (io.ReaderAt, int64, bool) {
+	readerAt, ok := reader.(io.ReaderAt)
+	seeker, isSeeker := reader.(io.Seeker)
+	if !ok || !isSeeker {
+		return nil, 0, false
+	}
+
+	base, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, 0, false
+	}
+	return readerAt, base, true
+}
+
+func contentsReader(matcher string, actual interface{}) (io.Reader, error) {
+	switch x := actual.(type) {
+	case mergedLog:
+		entries, err := x.entries()
+		if err != nil {
+			return nil, err
+		}
+		return contentsReader(matcher, entries)
+	case formattedLog:
+		entries, err := x.entries()
+		if err != nil {
+			return nil, err
+		}
+		return contentsReader(matcher, entries)
+	case otlpLog:
+		entries, err := x.entries()
+		if err != nil {
+			return nil, err
+		}
+		return contentsReader(matcher, entries)
+	case logEntries:
+		return contentsReader(matcher, []LogEntry(x))
+	case []LogEntry:
+		encoded, err := EncodeNDJSON(x)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(encoded), nil
+	case gbytes.BufferProvider:
+		return bytes.NewReader(x.Buffer().Contents()), nil
+	case ContentsProvider:
+		// take a snapshot, providers might reuse the returned slice
+		return bytes.NewReader(append([]byte{}, x.Contents()...)), nil
+	case RemoteLog:
+		return x.fetch()
+	case *url.URL:
+		return RemoteLog{URL: x.String()}.fetch()
+	case logFile:
+		return x.read()
+	case logFiles:
+		return x.read()
+	case string:
+		return strings.NewReader(x), nil
+	case []byte:
+		return bytes.NewReader(x), nil
+	case *ReplayableReader:
+		return x.replay()
+	case io.Reader:
+		return replayReader(x)
+	default:
+		if encoded, ok := encodeFormats(actual); ok {
+			return bytes.NewReader(encoded), nil
+		}
+		return nil, fmt.Errorf("%s must be passed an io.Reader, a string, []byte, glager.ContentsProvider, gbytes.BufferProvider, glager.FromFile, glager.FromFiles, glager.RemoteLog, *url.URL, lager.LogFormat, or []glager.LogEntry. Got:\n%s", matcher, format.Object(actual, 1))
+	}
+}
+
+// GomegaString implements format.GomegaStringer. Expected entries are rendered
+// the way they have been constructed, e.g. Info(Message("test.start")).
+func (entry logEntry) GomegaString() string {
+	var name string
+	var args []string
+
+	switch {
+	case entry.atLeast:
+		name, args = "AtLeast", []string{fmt.Sprintf("%d", entry.LogLevel)}
+	case entry.LogLevel == lager.DEBUG:
+		name = "Debug"
+	case entry.LogLevel == lager.INFO:
+		name = "Info"
+	case entry.LogLevel == LevelWarn.LogLevel():
+		name = "Warn"
+	case entry.LogLevel == lager.ERROR:
+		name, args = "Error", []string{"AnyErr"}
+	case entry.LogLevel == lager.FATAL:
+		name, args = "Fatal", []string{"AnyErr"}
+	default:
+		name, args = "Entry", []string{fmt.Sprintf("%d", entry.LogLevel)}
+	}
+
+	if entry.Source != "" {
+		args = append(args, fmt.Sprintf("Source(%q)", entry.Source))
+	}
+
+	if entry.Message != "" {
+		args = append(args, fmt.Sprintf("Message(%q)", entry.Message))
+	}
+
+	for _, key := range sortedKeys(entry.Data) {
+		args = append(args, fmt.Sprintf("Data(%q, %s)", key, describeValue(entry.Data[key])))
+	}
+
+	if len(entry.ignoredData) > 0 {
+		keys := make([]string, 0, len(entry.ignoredData))
+		for _, key := range sortedKeys(entry.ignoredData) {
+			keys = append(keys, fmt.Sprintf("%q", key))
+		}
+		args = append(args, fmt.Sprintf("IgnoringData(%s)", strings.Join(keys, ", ")))
+	}
+
+	for _, check := range entry.checks {
+		args = append(args, fmt.Sprintf("<%s>", check.description))
+	}
+
+	rendered := fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+	if len(entry.alternatives) > 0 {
+		rendered = renderAlternatives(entry.alternatives)
+	}
+
+	if entry.repeated.times > 0 {
+		rendered = fmt.Sprintf("Repeated(%d, %s)", entry.repeated.times, rendered)
+		if entry.repeated.n > 0 {
+			rendered += fmt.Sprintf(" (%d of %d)", entry.repeated.n, entry.repeated.times)
+		}
+	}
+
+	if entry.immediate {
+		rendered = fmt.Sprintf("Immediately(%s)", rendered)
+	}
+
+	if entry.negated {
+		rendered = fmt.Sprintf("Without(%s)", rendered)
+	}
+
+	if len(entry.absentBefore) > 0 || len(entry.absentAfter) > 0 {
+		parts := append(renderAbsent(entry.absentBefore), rendered)
+		rendered = strings.Join(append(parts, renderAbsent(entry.absentAfter)...), ", ")
+	}
+
+	return rendered
 }
 
 func (entry logEntry) logData() logEntryData {
 	return logEntryData(entry.Data)
 }
 
-func (actual logEntry) contains(expected logEntry) (bool, error) {
-	if expected.Source != "" && actual.Source != expected.Source {
+func (entry LogEntry) logData() logEntryData {
+	return logEntryData(entry.Data)
+}
+
+// Matches reports whether the log entry matches the given expected entry, just
+// like the entries matched by ContainSequence.
+func (actual LogEntry) Matches(expected ExpectedEntry) (bool, error) {
+	return actual.contains(expected)
+}
+
+func (actual LogEntry) contains(expected logEntry) (bool, error) {
+	if len(expected.alternatives) > 0 {
+		return actual.containsAny(expected)
+	}
+
+	if !expected.matchesLevel(actual.LogLevel) {
 		return false, nil
 	}
 
-	if expected.Message != "" && actual.Message != expected.Message {
+	if expected.Source != "" && actual.Source != expected.Source {
 		return false, nil
 	}
 
-	if actual.LogLevel != expected.LogLevel {
+	if expected.Message != "" && actual.Message != expected.Message {
 		return false, nil
 	}
 
+	expected, actual = ignoreData(expected, actual)
+
 	containsData, err := actual.logData().contains(expected.logData())
-	if err != nil {
+	if err != nil || !containsData {
 		return false, err
 	}
 
-	return containsData, nil
+	for _, check := range expected.checks {
+		matches, err := check.match(actual)
+		if err != nil || !matches {
+			return false, err
+		}
+	}
+
+	return true, nil
 }
 
 func (actual logEntryData) contains(expected logEntryData) (bool, error) {
-	for expectedKey, expectedVal := range expected {
-		actualVal, found := actual[expectedKey]
-		if !found {
-			return false, nil
-		}
+	mismatch, err := actual.mismatch(expected)
+	return mismatch == "", err
+}
 
-		// this has been marshalled and unmarshalled before, no need to check err
-		actualJSON, _ := json.Marshal(actualVal)
+// canonicalJSON encodes the given value after round-tripping it through JSON.
+// This way structs honor their json tags and are encoded with sorted keys,
+// just like the maps that have been decoded from the log.
+func canonicalJSON(value interface{}) ([]byte, error) {
+	decoded, err := canonical(value)
+	if err != nil {
+		return nil, err
+	}
 
-		expectedJSON, err := json.Marshal(expectedVal)
-		if err != nil {
-			return false, err
-		}
+	return json.Marshal(decoded)
+}
 
-		if string(actualJSON) != string(expectedJSON) {
-			return false, nil
-		}
+// canonical round-trips the given value through JSON, the way the data of log
+// entries is decoded.
+func canonical(value interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
 	}
-	return true, nil
+
+	return decodeExact(encoded)
 }
 
 func (entries logEntries) indexOf(entry logEntry) (int, bool, error) {
@@ -344,3 +912,214 @@ func (entries logEntries) indexOf(entry logEntry) (int, bool, error) {
 	}
 	return 0, false, nil
 }
+
+// ScanSequence scans the given entries for the expected sequence, just like
+// ContainSequence does. It returns the indices of the entries matching the
+// expected entries. If the sequence is not contained in the entries, fewer
+// indices than expected entries are returned, i.e. the indices of the entries
+// matched before scanning diverged.
+//
+// Example:
+//
+//	entries, err := Entries(log)
+//	Expect(err).ToNot(HaveOccurred())
+//
+//	matched, err := ScanSequence(entries, Info(Action("audit.login")), Info(Action("audit.logout")))
+//	Expect(err).ToNot(HaveOccurred())
+//	Expect(matched).To(HaveLen(2))
+func ScanSequence(entries []LogEntry, expectedSequence ...SequenceEntry) ([]int, error) {
+	return logEntries(entries).matchSequence(compileSequence(sequenceOf(expectedSequence)))
+}
+
+// matchSequence returns the indices of the entries matching the expected
+// sequence. If the sequence is not contained in the entries, fewer indices
+// than expected entries are returned, i.e. the indices matched before the
+// furthest divergence. If a repeated entry appears more often than expected,
+// the indices up to its last copy are returned. If an entry passed to Without
+// appears between two matched entries, the search is restarted at the first of
+// them, past the forbidden entry.
+func (entries logEntries) matchSequence(expectedSequence []logEntry) ([]int, error) {
+	matched := []int{}
+	furthest := []int{}
+	offset := 0
+
+	// diverged returns the indices matched before the furthest divergence
+	diverged := func(matched []int) []int {
+		if len(furthest) > len(matched) {
+			return furthest
+		}
+		return matched
+	}
+
+	// restart continues the search at expectedSequence[k], past the given
+	// forbidden entry, remembering the indices matched before diverging
+	restart := func(k, violation int, progress []int) int {
+		if len(progress) > len(furthest) {
+			furthest = append([]int{}, progress...)
+		}
+		matched, offset = matched[:k], violation+1
+		return k - 1
+	}
+
+	for n := 0; n <= len(expectedSequence); n++ {
+		if n == len(expectedSequence) {
+			if n == 0 {
+				break
+			}
+			last := n - 1
+
+			if expectedSequence[last].lastRepetition() {
+				repeatedAgain, err := entries[offset:].contain(expectedSequence[last])
+				if err != nil || repeatedAgain {
+					return diverged(matched[:last]), err
+				}
+			}
+
+			violation, err := entries.violation(matched, last, expectedSequence[last].absentAfter, len(entries))
+			if err != nil {
+				return nil, err
+			}
+
+			if violation < 0 {
+				break
+			}
+
+			if !movable(expectedSequence, last) {
+				return diverged(matched[:last]), nil
+			}
+			n = restart(last, violation, matched[:last])
+			continue
+		}
+
+		expected := expectedSequence[n]
+
+		if n > 0 && expected.immediate {
+			var found bool
+			var err error
+			matched, found, err = entries.matchImmediate(expectedSequence, matched, n)
+			if err != nil || !found {
+				return diverged(matched), err
+			}
+			offset = matched[n] + 1
+			continue
+		}
+
+		i, found, err := entries[offset:].indexOf(expected)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			return diverged(matched), nil
+		}
+
+		if n > 0 && expectedSequence[n-1].lastRepetition() {
+			repeatedAgain, err := entries[offset : offset+i].contain(expectedSequence[n-1])
+			if err != nil || repeatedAgain {
+				return diverged(matched[:len(matched)-1]), err
+			}
+		}
+
+		violation, err := entries.violation(matched, n-1, expected.absentBefore, offset+i)
+		if err != nil {
+			return nil, err
+		}
+
+		if violation >= 0 {
+			if !movable(expectedSequence, n-1) {
+				return diverged(matched), nil
+			}
+			n = restart(n-1, violation, matched)
+			continue
+		}
+
+		matched = append(matched, offset+i)
+		offset += i + 1
+	}
+
+	return matched, nil
+}
+
+// matchContiguousSequence returns the indices of the longest run of entries
+// that match the start of the expected sequence without any other entries in
+// between. The first such run is returned if there are several.
+func (entries logEntries) matchContiguousSequence(expectedSequence []logEntry) ([]int, error) {
+	best := []int{}
+
+	for start := range entries {
+		if start > 0 && len(expectedSequence) > 0 && expectedSequence[0].firstRepetition() {
+			// the repeated entry would appear more often than expected
+			repeatedBefore, err := entries[start-1].contains(expectedSequence[0])
+			if err != nil {
+				return nil, err
+			}
+			if repeatedBefore {
+				continue
+			}
+		}
+
+		matched := []int{}
+		for n, expected := range expectedSequence {
+			i := start + n
+			if i >= len(entries) {
+				break
+			}
+
+			containsEntry, err := entries[i].contains(expected)
+			if err != nil {
+				return nil, err
+			}
+
+			if !containsEntry {
+				break
+			}
+
+			matched = append(matched, i)
+		}
+
+		if len(matched) == len(expectedSequence) && len(matched) > 0 && expectedSequence[len(matched)-1].lastRepetition() {
+			next := start + len(matched)
+			if next < len(entries) {
+				repeatedAfter, err := entries[next].contains(expectedSequence[len(matched)-1])
+				if err != nil {
+					return nil, err
+				}
+				if repeatedAfter {
+					matched = matched[:len(matched)-1]
+				}
+			}
+		}
+
+		if len(matched) > len(best) {
+			best = matched
+		}
+
+		if len(best) == len(expectedSequence) {
+			break
+		}
+	}
+
+	return best, nil
+}
+
+func (entries logEntries) filter(specs []logEntry) (logEntries, error) {
+	if len(specs) == 0 {
+		return entries, nil
+	}
+
+	result := logEntries{}
+	for _, actual := range entries {
+		for _, spec := range specs {
+			containsEntry, err := actual.contains(spec)
+			if err != nil {
+				return nil, err
+			}
+
+			if containsEntry {
+				result = append(result, actual)
+				break
+			}
+		}
+	}
+	return result, nil
+}
@@ -12,6 +12,7 @@ import (
 	"code.cloudfoundry.org/lager/lagertest"
 
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/types"
@@ -223,6 +224,22 @@ var _ = Describe(".ContainSequence", func() {
 					))
 				})
 
+				expectTypeMismatch := func(key string, expected interface{}, message string) {
+					matcher := ContainSequence(Info(Data(key, expected)))
+					Expect(matcher.Match(logger)).To(BeFalse())
+					Expect(matcher.FailureMessage(logger)).To(ContainSubstring(message))
+				}
+
+				It("does not coerce JSON types and explains the type mismatch", func() {
+					logger.Info("strings", lager.Data{"count": "17", "flag": "true"})
+
+					expectTypeMismatch("int", "17", `data.int: type mismatch, expected a string "17", got a number 17`)
+					expectTypeMismatch("count", 17, `data.count: type mismatch, expected a number 17, got a string "17"`)
+					expectTypeMismatch("flag", true, `data.flag: type mismatch, expected a boolean true, got a string "true"`)
+					expectTypeMismatch("bool", "true", `data.bool: type mismatch, expected a string "true", got a boolean true`)
+					expectTypeMismatch("null", "", `data.null: type mismatch, expected a string "", got null`)
+				})
+
 				It("matches a correct null value", func() {
 					Expect(logger).To(ContainSequence(
 						Info(Data("null", nil)),
@@ -251,6 +268,27 @@ var _ = Describe(".ContainSequence", func() {
 					))
 				})
 
+				It("matches a struct value using its json tags regardless of field order", func() {
+					type request struct {
+						Path    string            `json:"path"`
+						Method  string            `json:"method"`
+						Headers map[string]string `json:"headers,omitempty"`
+						Ignored string            `json:"-"`
+					}
+
+					logger.Info("request", lager.Data{
+						"request": map[string]interface{}{"method": "GET", "path": "/v1"},
+					})
+
+					Expect(logger).To(ContainSequence(
+						Info(Data("request", request{Path: "/v1", Method: "GET", Ignored: "foo"})),
+					))
+
+					Expect(logger).ToNot(ContainSequence(
+						Info(Data("request", request{Path: "/v2", Method: "GET"})),
+					))
+				})
+
 				It("does not match an incorrect object value", func() {
 					Expect(logger).ToNot(ContainSequence(
 						Info(Data("object", foo{"boooh"})),
@@ -605,7 +643,7 @@ var _ = Describe(".ContainSequence", func() {
 
 			Context("when actual is an invalid type", func() {
 				BeforeEach(func() {
-					actual = "invalid"
+					actual = 42
 				})
 
 				It("returns failure", func() {
@@ -676,6 +714,56 @@ var _ = Describe(".ContainSequence", func() {
 				})
 			})
 
+			Context("when actual is a Replayable io.Reader", func() {
+				BeforeEach(func() {
+					actual = Replayable(bufio.NewReader(buffer))
+				})
+
+				It("returns success", func() {
+					Expect(success).To(BeTrue())
+				})
+
+				It("matches on subsequent calls", func() {
+					Expect(actual).To(matcher)
+				})
+			})
+
+			Context("when actual is a string", func() {
+				BeforeEach(func() {
+					actual = `{"timestamp":"1","source":"logger","message":"logger.some-info","log_level":1,"data":{}}` + "\n"
+				})
+
+				It("returns success", func() {
+					Expect(success).To(BeTrue())
+				})
+
+				It("does not return an error", func() {
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("does match on subsequent calls", func() {
+					Expect(actual).To(matcher)
+				})
+			})
+
+			Context("when actual is a []byte", func() {
+				BeforeEach(func() {
+					actual = []byte(`{"timestamp":"1","source":"logger","message":"logger.some-info","log_level":1,"data":{}}`)
+				})
+
+				It("returns success", func() {
+					Expect(success).To(BeTrue())
+				})
+
+				It("does not return an error", func() {
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("does match on subsequent calls", func() {
+					Expect(actual).To(matcher)
+				})
+			})
+
 			Context("when actual contains invalid entries", func() {
 				BeforeEach(func() {
 					actual = strings.NewReader("invalid")
@@ -685,9 +773,16 @@ var _ = Describe(".ContainSequence", func() {
 					Expect(success).To(BeFalse())
 				})
 
-				It("returns a json.SyntaxError", func() {
+				It("returns a ParseError wrapping a json.SyntaxError", func() {
 					Expect(err).To(HaveOccurred())
-					Expect(err).To(BeAssignableToTypeOf(&json.SyntaxError{}))
+
+					var parseErr *ParseError
+					Expect(errors.As(err, &parseErr)).To(BeTrue())
+					Expect(parseErr.Line).To(Equal(1))
+					Expect(parseErr.Content).To(Equal("invalid"))
+
+					var syntaxErr *json.SyntaxError
+					Expect(errors.As(err, &syntaxErr)).To(BeTrue())
 				})
 			})
 
@@ -714,6 +809,32 @@ var _ = Describe(".ContainSequence", func() {
 			})
 		})
 
+		Describe("rendering of the expected sequence", func() {
+			type request struct {
+				Path   string `json:"path"`
+				Method string `json:"method"`
+			}
+
+			It("is deterministic", func() {
+				matcher = ContainSequence(
+					Info(Data("b", 1.0, "a", map[string]interface{}{"z": 1, "y": 2}, "c", BeAUUID())),
+					Error(AnyErr, Message("logger.failed"), Data("request", request{"/", "GET"})),
+				)
+				Expect(matcher.Match(buffer)).To(BeFalse())
+
+				message := matcher.FailureMessage(buffer)
+				Expect(message).To(ContainSubstring(`to contain log sequence
+	0: Info(Data("a", {"y":2,"z":1}), Data("b", 1), Data("c", <*glager.formatMatcher>))
+	1: Error(AnyErr, Message("logger.failed"), Data("request", {"method":"GET","path":"/"}))`))
+
+				for i := 0; i < 10; i++ {
+					Expect(matcher.Match(buffer)).To(BeFalse())
+					// repeated matching adds a timeline of the polls
+					Expect(strings.SplitN(matcher.FailureMessage(buffer), "\nPolls:", 2)[0]).To(Equal(message))
+				}
+			})
+		})
+
 		Describe("NegatedFailureMessage", func() {
 			It("returns the right message", func() {
 				matcher.Match(buffer)
@@ -721,10 +842,185 @@ var _ = Describe(".ContainSequence", func() {
 					"not to contain log sequence",
 				))
 			})
+
+			It("shows where the sequence matched", func() {
+				logger.Info("first")
+				logger.Debug("second")
+				logger.Info("third")
+
+				matcher = ContainSequence(Info(), Info(Message("logger.third")))
+				Expect(matcher.Match(buffer)).To(BeTrue())
+
+				message := matcher.NegatedFailureMessage(buffer)
+				Expect(message).To(ContainSubstring("Matching entries:"))
+				Expect(message).To(MatchRegexp(`entry 1: glager.LogEntry{.*Message: "logger.first"`))
+				Expect(message).To(MatchRegexp(`entry 3: glager.LogEntry{.*Message: "logger.third"`))
+				Expect(message).ToNot(ContainSubstring("entry 0:"))
+				Expect(message).ToNot(ContainSubstring("entry 2:"))
+			})
 		})
 	})
 
+	Describe("nested data", func() {
+		BeforeEach(func() {
+			logger.Info("request", lager.Data{
+				"request": map[string]interface{}{
+					"method": "GET",
+					"headers": []map[string]string{
+						{"name": "Accept", "value": "*/*"},
+						{"name": "Host", "value": "example.com"},
+						{"name": "User-Agent", "value": "curl"},
+					},
+				},
+			})
+		})
+
+		It("matches deeply equal maps and slices", func() {
+			Expect(logger).To(ContainSequence(Info(Data("request", map[string]interface{}{
+				"method": "GET",
+				"headers": []interface{}{
+					map[string]string{"name": "Accept", "value": "*/*"},
+					map[string]interface{}{"name": "Host", "value": "example.com"},
+					map[string]interface{}{"name": "User-Agent", "value": HavePrefix("cu")},
+				},
+			}))))
+		})
+
+		It("does not match nested maps with missing or additional keys", func() {
+			Expect(logger).ToNot(ContainSequence(Info(Data("request", map[string]interface{}{
+				"method": "GET",
+			}))))
+
+			Expect(logger).ToNot(ContainSequence(Info(Data("request", map[string]interface{}{
+				"method":  "GET",
+				"headers": []interface{}{},
+				"body":    "",
+			}))))
+		})
+
+		table.DescribeTable("reporting the path of the first difference",
+			func(expected interface{}, path string) {
+				matcher := ContainSequence(Info(Data("request", expected)))
+				Expect(matcher.Match(logger)).To(BeFalse())
+				Expect(matcher.FailureMessage(logger)).To(ContainSubstring(path))
+			},
+			table.Entry("a different nested value", map[string]interface{}{
+				"method": "GET",
+				"headers": []map[string]string{
+					{"name": "Accept", "value": "*/*"},
+					{"name": "Host", "value": "example.com"},
+					{"name": "Agent", "value": "curl"},
+				},
+			}, `entry 0: data.request.headers[2].name: expected "Agent", got "User-Agent"`),
+			table.Entry("a missing nested key", map[string]interface{}{
+				"method":  "GET",
+				"headers": []interface{}{},
+				"body":    "",
+			}, "data.request.body: missing"),
+			table.Entry("an unexpected nested key", map[string]interface{}{
+				"headers": []map[string]string{
+					{"name": "Accept", "value": "*/*"},
+					{"name": "Host", "value": "example.com"},
+					{"name": "User-Agent", "value": "curl"},
+				},
+			}, "data.request.method: unexpected"),
+			table.Entry("a different number of elements", map[string]interface{}{
+				"method":  "GET",
+				"headers": []interface{}{},
+			}, "data.request.headers: expected 0 elements, got 3"),
+			table.Entry("an array instead of a string", map[string]interface{}{
+				"method": []string{"GET"},
+			}, "data.request.method: expected an array, got a string"),
+			table.Entry("an object instead of a string", map[string]interface{}{
+				"method": map[string]string{},
+			}, "data.request.method: expected an object, got a string"),
+		)
+	})
+
 	Describe(".Data", func() {
+		Context("when a matcher is passed as value", func() {
+			BeforeEach(func() {
+				logger.Info("request", lager.Data{"duration": 150, "request_id": "9f1c-42ab"})
+			})
+
+			It("delegates to the matcher", func() {
+				Expect(logger).To(ContainSequence(Info(Data(
+					"duration", BeNumerically(">", 100),
+					"request_id", MatchRegexp("^[0-9a-f-]+$"),
+				))))
+
+				Expect(logger).ToNot(ContainSequence(Info(Data("duration", BeNumerically("<", 100)))))
+			})
+		})
+
+		Context("when a number is passed as value", func() {
+			BeforeEach(func() {
+				logger.Info("listening", lager.Data{"port": 8080, "load": 0.5, "id": int64(9007199254740993)})
+			})
+
+			table.DescribeTable("compares it by value, regardless of its type",
+				func(key string, value interface{}) {
+					Expect(logger).To(ContainSequence(Info(Data(key, value))))
+				},
+				table.Entry("int", "port", 8080),
+				table.Entry("uint16", "port", uint16(8080)),
+				table.Entry("int64", "port", int64(8080)),
+				table.Entry("float64", "port", 8080.0),
+				table.Entry("json.Number", "port", json.Number("8080")),
+				table.Entry("float32", "load", float32(0.5)),
+				table.Entry("int64 beyond float64 precision", "id", int64(9007199254740993)),
+				table.Entry("uint64 beyond float64 precision", "id", uint64(9007199254740993)),
+				table.Entry("json.Number beyond float64 precision", "id", json.Number("9007199254740993")),
+			)
+
+			table.DescribeTable("does not match other numbers or strings",
+				func(key string, value interface{}) {
+					Expect(logger).ToNot(ContainSequence(Info(Data(key, value))))
+				},
+				table.Entry("int", "port", 8081),
+				table.Entry("string", "port", "8080"),
+				table.Entry("int64 beyond float64 precision", "id", int64(9007199254740992)),
+				table.Entry("uint64 beyond float64 precision", "id", uint64(9007199254740994)),
+				table.Entry("float64 beyond float64 precision", "id", float64(9007199254740992)),
+			)
+
+			It("decodes integers beyond float64 precision exactly", func() {
+				entries, err := Entries(logger)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(entries[0].Data["id"]).To(Equal(json.Number("9007199254740993")))
+				Expect(entries[0].Data["port"]).To(Equal(8080.0))
+			})
+		})
+
+		Context("when a map is passed", func() {
+			BeforeEach(func() {
+				logger.Info("request", lager.Data{"method": "GET", "path": "/", "status": 200})
+			})
+
+			It("matches all of its keys", func() {
+				Expect(logger).To(ContainSequence(Info(Data(lager.Data{"method": "GET", "status": 200}))))
+				Expect(logger).To(ContainSequence(Info(Data(map[string]interface{}{"status": BeNumerically(">=", 200)}))))
+				Expect(logger).ToNot(ContainSequence(Info(Data(map[string]string{"method": "GET", "path": "/index"}))))
+			})
+		})
+
+		Context("when a struct is passed", func() {
+			type request struct {
+				Method string `json:"method"`
+				Path   string `json:"path,omitempty"`
+			}
+
+			BeforeEach(func() {
+				logger.Info("request", lager.Data{"method": "GET", "path": "/", "status": 200})
+			})
+
+			It("matches all of its fields as they are encoded to JSON", func() {
+				Expect(logger).To(ContainSequence(Info(Data(request{Method: "GET"}))))
+				Expect(logger).To(ContainSequence(Info(Data(&request{Method: "GET", Path: "/"}))))
+				Expect(logger).ToNot(ContainSequence(Info(Data(request{Method: "POST"}))))
+			})
+		})
+
 		Context("when a non-string key is passed", func() {
 			It("panics", func() {
 				Expect(func() {
@@ -734,3 +1030,51 @@ var _ = Describe(".ContainSequence", func() {
 		})
 	})
 })
+
+var _ = Describe(".ContainExactSequence", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		logger.Info("start")
+		logger.Info("lock.acquired")
+		logger.Debug("work")
+		logger.Info("lock.released")
+		logger.Info("stop")
+	})
+
+	It("matches entries that have been logged back-to-back", func() {
+		Expect(logger).To(ContainExactSequence(
+			Info(Action("test.lock.acquired")),
+			Debug(Action("test.work")),
+			Info(Action("test.lock.released")),
+		))
+	})
+
+	It("does not match entries with other entries in between", func() {
+		Expect(logger).To(ContainSequence(Info(Action("test.lock.acquired")), Info(Action("test.lock.released"))))
+		Expect(logger).ToNot(ContainExactSequence(Info(Action("test.lock.acquired")), Info(Action("test.lock.released"))))
+	})
+
+	It("finds a run that starts after a partial one", func() {
+		logger.Info("lock.acquired")
+		logger.Info("lock.released")
+
+		Expect(logger).To(ContainExactSequence(Info(Action("test.lock.acquired")), Info(Action("test.lock.released"))))
+	})
+
+	It("reports the entry that interrupted the sequence", func() {
+		matcher := ContainExactSequence(Info(Action("test.lock.acquired")), Info(Action("test.lock.released")))
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		message := matcher.FailureMessage(logger)
+		Expect(message).To(ContainSubstring("to contain contiguous log sequence"))
+		Expect(message).To(ContainSubstring("0: matched entry 1"))
+		Expect(message).To(ContainSubstring("1: not found"))
+	})
+
+	It("returns an error for invalid actuals", func() {
+		_, err := ContainExactSequence(Info()).Match(42)
+		Expect(err).To(MatchError(HavePrefix("ContainExactSequence must be passed")))
+	})
+})
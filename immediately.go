@@ -0,0 +1,96 @@
+package glager
+
+// Immediately specifies that the given log entry has to be the very next entry
+// after the entry matched for the previous entry of a sequence. Entries before
+// the previous entry and after the given one are not constrained, unlike with
+// ContainExactSequence. If the previous entry is not immediately followed by a
+// matching entry, later occurrences of it are tried. Immediately can not be
+// applied to the first entry of a sequence, and cannot be passed to ContainAll
+// or ContainOnly.
+//
+// Example:
+//
+//	Expect(logger).To(ContainSequence(
+//	  Info(Action("test.lock.acquired")),
+//	  Immediately(Info(Action("test.lock.released"))),
+//	))
+func Immediately(entry ExpectedEntry) immediateEntry {
+	entry.immediate = true
+	return immediateEntry(entry)
+}
+
+// immediateEntry is an entry passed to Immediately.
+type immediateEntry logEntry
+
+func (entry immediateEntry) sequenceEntry() logEntry { return logEntry(entry) }
+func (entry immediateEntry) requiredEntry()          {}
+
+// matchImmediate matches expectedSequence[n], which has to immediately follow
+// the entry matched for expectedSequence[n-1]. If it does not, the chain of
+// entries leading up to it, i.e. the preceding entries passed to Immediately
+// and the entry they follow, is moved to a later occurrence, if that is
+// possible without violating any other constraint. It returns the matched
+// indices and whether the entry has been matched.
+func (entries logEntries) matchImmediate(expectedSequence []logEntry, matched []int, n int) ([]int, bool, error) {
+	head := n - 1
+	for head > 0 && expectedSequence[head].immediate {
+		head--
+	}
+
+	start := matched[head]
+	for {
+		chained, err := entries.matchChain(expectedSequence[head:n+1], start)
+		if err != nil {
+			return matched, false, err
+		}
+
+		if chained {
+			result := append([]int{}, matched[:head]...)
+			for i := start; i <= start+n-head; i++ {
+				result = append(result, i)
+			}
+			return result, true, nil
+		}
+
+		// the head of the chain can only be moved if that does not change the
+		// number of repetitions of a repeated entry
+		if expectedSequence[head].repeated.times > 0 || (head > 0 && expectedSequence[head-1].lastRepetition()) {
+			return matched, false, nil
+		}
+
+		i, found, err := entries[start+1:].indexOf(expectedSequence[head])
+		if err != nil || !found {
+			return matched, false, err
+		}
+		start += 1 + i
+
+		previous := 0
+		if head > 0 {
+			previous = matched[head-1] + 1
+		}
+
+		// moving the head extends the range its own negated entries apply to
+		violation, err := entries.lastOccurrence(expectedSequence[head].absentBefore, previous, start)
+		if err != nil || violation >= 0 {
+			return matched, false, err
+		}
+	}
+}
+
+// matchChain reports whether the entries following the given start match the
+// entries of the given chain but the first one, without any other entries in
+// between.
+func (entries logEntries) matchChain(chain []logEntry, start int) (bool, error) {
+	for n, expected := range chain[1:] {
+		i := start + 1 + n
+		if i >= len(entries) {
+			return false, nil
+		}
+
+		containsEntry, err := entries[i].contains(expected)
+		if err != nil || !containsEntry {
+			return false, err
+		}
+	}
+	return true, nil
+}
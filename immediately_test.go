@@ -0,0 +1,96 @@
+package glager_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".Immediately", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		logger.Info("lock.acquired")
+		logger.Info("work")
+		logger.Info("lock.released")
+		logger.Info("lock.acquired")
+		logger.Info("lock.released")
+	})
+
+	It("matches an entry that directly follows the previous one", func() {
+		Expect(logger).To(ContainSequence(
+			Info(Action("test.lock.acquired")),
+			Immediately(Info(Action("test.work"))),
+		))
+	})
+
+	It("tries later occurrences of the previous entry", func() {
+		Expect(logger).To(ContainSequence(
+			Info(Action("test.lock.acquired")),
+			Immediately(Info(Action("test.lock.released"))),
+		))
+	})
+
+	It("moves chains of entries passed to Immediately", func() {
+		logger.Info("work")
+
+		Expect(logger).To(ContainSequence(
+			Info(Action("test.lock.acquired")),
+			Immediately(Info(Action("test.lock.released"))),
+			Immediately(Info(Action("test.work"))),
+		))
+	})
+
+	It("does not match if there are other entries in between", func() {
+		Expect(logger).ToNot(ContainSequence(
+			Info(Action("test.work")),
+			Immediately(Info(Action("test.lock.acquired"))),
+		))
+	})
+
+	It("does not move a previous entry past a negated entry", func() {
+		Expect(logger).ToNot(ContainSequence(
+			Info(Action("test.work")),
+			Without(Info(Action("test.lock.released"))),
+			Info(Action("test.lock.acquired")),
+			Immediately(Info(Action("test.lock.released"))),
+		))
+	})
+
+	It("does not move a repeated entry", func() {
+		Expect(logger).ToNot(ContainSequence(
+			Repeated(1, Info(Action("test.lock.acquired"))),
+			Immediately(Info(Action("test.lock.released"))),
+		))
+	})
+
+	It("is rendered the way it has been constructed", func() {
+		matcher := ContainSequence(
+			Info(Action("test.work")),
+			Immediately(Info(Action("test.lock.acquired"))),
+		)
+		Expect(matcher.Match(logger)).To(BeFalse())
+		Expect(matcher.FailureMessage(logger)).To(ContainSubstring(`1: Immediately(Info(Message("test.lock.acquired")))`))
+	})
+
+	It("panics if it is applied to the first entry of a sequence", func() {
+		Expect(func() {
+			ContainSequence(Immediately(Info(Action("test.work"))))
+		}).To(PanicWith(MatchError(`Immediately can not be applied to the first entry of a sequence. Got Immediately(Info(Message("test.work"))).`)))
+	})
+
+	It("can only be passed to matchers that keep the order of entries", func() {
+		var entry interface{} = Immediately(Info())
+
+		_, required := entry.(RequiredEntry)
+		Expect(required).To(BeTrue())
+
+		_, unordered := entry.(UnorderedEntry)
+		Expect(unordered).To(BeFalse())
+
+		_, single := entry.(ExpectedEntry)
+		Expect(single).To(BeFalse())
+	})
+})
@@ -0,0 +1,161 @@
+package glager
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// Latencies parses the given actual and returns the time gaps between the
+// entries matching from and the entries matching to. Every entry matching
+// from is paired with the first subsequent entry matching to that has not
+// been paired yet, i.e. occurrences are paired in order. Entries matching from
+// without a subsequent entry matching to are ignored. The actual can be
+// anything that is accepted by the ContainSequence matcher.
+//
+// Example:
+//
+//	latencies, err := Latencies(logger, Info(Action("api.request")), Info(Action("api.response")))
+//	Expect(err).ToNot(HaveOccurred())
+//	Expect(latencies).To(HaveP95Under(200 * time.Millisecond))
+func Latencies(actual interface{}, from, to ExpectedEntry) ([]time.Duration, error) {
+
+	entries, err := parseEntries("Latencies", actual)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []time.Time{}
+	latencies := []time.Duration{}
+
+	for _, entry := range entries {
+		isTo, err := entry.contains(to)
+		if err != nil {
+			return nil, err
+		}
+
+		if isTo && len(pending) > 0 {
+			t, err := entry.Time()
+			if err != nil {
+				return nil, err
+			}
+
+			latencies = append(latencies, t.Sub(pending[0]))
+			pending = pending[1:]
+			continue
+		}
+
+		isFrom, err := entry.contains(from)
+		if err != nil {
+			return nil, err
+		}
+
+		if isFrom {
+			t, err := entry.Time()
+			if err != nil {
+				return nil, err
+			}
+
+			pending = append(pending, t)
+		}
+	}
+
+	return latencies, nil
+}
+
+// Percentile returns the p-th percentile of the given durations using the
+// nearest-rank method, e.g. Percentile(latencies, 95). It returns 0 if there
+// are no durations.
+func Percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(a, b int) bool {
+		return sorted[a] < sorted[b]
+	})
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+
+	return sorted[rank-1]
+}
+
+type percentileMatcher struct {
+	percentile float64
+	limit      time.Duration
+	actual     time.Duration
+}
+
+// HavePercentileUnder checks that the p-th percentile of the actual durations,
+// e.g. as returned by Latencies, is less than the given limit. It fails if
+// there are no durations.
+func HavePercentileUnder(p float64, limit time.Duration) types.GomegaMatcher {
+	if p < 0 || p > 100 {
+		panic(fmt.Errorf("HavePercentileUnder must be passed a percentile between 0 and 100. Got %v.", p))
+	}
+
+	return &percentileMatcher{
+		percentile: p,
+		limit:      limit,
+	}
+}
+
+// HaveP50Under checks that the median of the actual durations is less than
+// the given limit.
+func HaveP50Under(limit time.Duration) types.GomegaMatcher {
+	return HavePercentileUnder(50, limit)
+}
+
+// HaveP95Under checks that the 95th percentile of the actual durations is less
+// than the given limit.
+func HaveP95Under(limit time.Duration) types.GomegaMatcher {
+	return HavePercentileUnder(95, limit)
+}
+
+// HaveP99Under checks that the 99th percentile of the actual durations is less
+// than the given limit.
+func HaveP99Under(limit time.Duration) types.GomegaMatcher {
+	return HavePercentileUnder(99, limit)
+}
+
+// Match is doing the actual matching for a given set of durations.
+func (pm *percentileMatcher) Match(actual interface{}) (success bool, err error) {
+	durations, ok := actual.([]time.Duration)
+	if !ok {
+		return false, fmt.Errorf("HavePercentileUnder must be passed a []time.Duration. Got:\n%s", format.Object(actual, 1))
+	}
+
+	if len(durations) == 0 {
+		return false, fmt.Errorf("HavePercentileUnder must be passed at least one duration.")
+	}
+
+	pm.actual = Percentile(durations, pm.percentile)
+	return pm.actual < pm.limit, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (pm *percentileMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected p%v of durations to be under %s, got %s", pm.percentile, pm.limit, pm.actual)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (pm *percentileMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected p%v of durations not to be under %s, got %s", pm.percentile, pm.limit, pm.actual)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (pm *percentileMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
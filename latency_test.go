@@ -0,0 +1,101 @@
+package glager_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("latency statistics", func() {
+	entry := func(ms int, message string) LogEntry {
+		return LogEntry{
+			Timestamp: fmt.Sprintf("100.%03d000000", ms),
+			Source:    "api",
+			Message:   message,
+			LogLevel:  1,
+		}
+	}
+
+	Describe(".Latencies", func() {
+		It("returns the gaps between matching entries in order", func() {
+			log := []LogEntry{
+				entry(0, "api.request"),
+				entry(10, "api.request"),
+				entry(20, "api.other"),
+				entry(50, "api.response"),
+				entry(70, "api.response"),
+				entry(80, "api.response"),
+				entry(90, "api.request"),
+			}
+
+			Expect(Latencies(log, Info(Action("api.request")), Info(Action("api.response")))).To(Equal([]time.Duration{
+				50 * time.Millisecond,
+				60 * time.Millisecond,
+			}))
+		})
+
+		It("returns an error for invalid timestamps", func() {
+			log := []LogEntry{{Timestamp: "now", Message: "api.request", LogLevel: 1}}
+			_, err := Latencies(log, Info(Action("api.request")), Info(Action("api.response")))
+			Expect(err).To(MatchError(`Invalid timestamp "now".`))
+		})
+	})
+
+	Describe(".Percentile", func() {
+		durations := []time.Duration{}
+		for i := 100; i > 0; i-- {
+			durations = append(durations, time.Duration(i)*time.Millisecond)
+		}
+
+		It("returns the nearest-rank percentile", func() {
+			Expect(Percentile(durations, 50)).To(Equal(50 * time.Millisecond))
+			Expect(Percentile(durations, 95)).To(Equal(95 * time.Millisecond))
+			Expect(Percentile(durations, 100)).To(Equal(100 * time.Millisecond))
+			Expect(Percentile(durations, 0)).To(Equal(1 * time.Millisecond))
+			Expect(Percentile(nil, 95)).To(BeZero())
+		})
+
+		It("does not modify the given durations", func() {
+			Percentile(durations, 50)
+			Expect(durations[0]).To(Equal(100 * time.Millisecond))
+		})
+	})
+
+	Describe(".HaveP95Under", func() {
+		durations := []time.Duration{}
+		for i := 1; i <= 100; i++ {
+			durations = append(durations, time.Duration(i)*time.Millisecond)
+		}
+
+		It("matches if the percentile is under the limit", func() {
+			Expect(durations).To(HaveP95Under(96 * time.Millisecond))
+			Expect(durations).ToNot(HaveP95Under(95 * time.Millisecond))
+			Expect(durations).To(HaveP50Under(51 * time.Millisecond))
+			Expect(durations).To(HaveP99Under(100 * time.Millisecond))
+		})
+
+		It("explains failures", func() {
+			matcher := HaveP95Under(10 * time.Millisecond)
+			Expect(matcher.Match(durations)).To(BeFalse())
+			Expect(matcher.FailureMessage(durations)).To(Equal("Expected p95 of durations to be under 10ms, got 95ms"))
+		})
+
+		It("returns an error for empty durations", func() {
+			_, err := HaveP95Under(time.Second).Match([]time.Duration{})
+			Expect(err).To(MatchError("HavePercentileUnder must be passed at least one duration."))
+		})
+
+		It("returns an error for invalid actuals", func() {
+			_, err := HaveP95Under(time.Second).Match([]int{1})
+			Expect(err).To(MatchError(HavePrefix("HavePercentileUnder must be passed a []time.Duration.")))
+		})
+
+		It("panics for invalid percentiles", func() {
+			Expect(func() { HavePercentileUnder(101, time.Second) }).To(Panic())
+		})
+	})
+})
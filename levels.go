@@ -0,0 +1,328 @@
+package glager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// Level is a lager log level. It can be parsed from and rendered as the level
+// names used by lager, i.e. "debug", "info", "error", and "fatal", as well as
+// "warn" for LevelWarn. Custom levels are rendered as numbers.
+type Level lager.LogLevel
+
+// The log levels supported by lager.
+const (
+	LevelDebug = Level(lager.DEBUG)
+	LevelInfo  = Level(lager.INFO)
+	LevelError = Level(lager.ERROR)
+	LevelFatal = Level(lager.FATAL)
+)
+
+// LevelWarn is the level of warnings written in other log formats, e.g. by
+// slog, zap, or logrus. lager has no warn level, LevelWarn ranks between
+// LevelInfo and LevelError, e.g. AtLeast(LevelWarn.LogLevel()) matches
+// warnings, errors, and fatal entries.
+const LevelWarn = Level(lager.FATAL + 1)
+
+// Levels maps level names onto glager levels, e.g. to name the TRACE level of
+// some other log format, or to alias a name onto an existing level. Names are
+// matched case insensitively and take precedence over the level mapping of the
+// format, e.g. with Levels{"dpanic": LevelFatal}, Zap maps DPanic entries to
+// lager.FATAL instead of lager.ERROR, and with Levels{"warn": LevelInfo},
+// warnings are collapsed onto lager.INFO. The mapping is applied to a single
+// log by using the bundled formats returned by its methods, e.g.
+// Levels{"verbose": Level(-1)}.Zerolog(), instead of the plain formats. Use
+// Entry to specify entries at custom levels.
+//
+// Example:
+//
+//	levels := Levels{"dpanic": LevelFatal}
+//
+//	Expect(WithFormat(levels.Zap(), buffer)).To(ContainSequence(Fatal(AnyErr, Message("invariant violated"))))
+type Levels map[string]Level
+
+// lookup returns the level mapped to the given name.
+func (l Levels) lookup(name string) (Level, bool) {
+	name = strings.TrimSpace(name)
+	for candidate, level := range l {
+		if strings.EqualFold(strings.TrimSpace(candidate), name) {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+// parser returns a function that maps level names using the mapping, and
+// parses all other names using the given function.
+func (l Levels) parser(parse func(string) (lager.LogLevel, error)) func(string) (lager.LogLevel, error) {
+	return func(name string) (lager.LogLevel, error) {
+		if level, found := l.lookup(name); found {
+			return level.LogLevel(), nil
+		}
+		return parse(name)
+	}
+}
+
+// builtinLevel returns the lager level with the given lower case name.
+func builtinLevel(name string) (Level, bool) {
+	switch name {
+	case "debug":
+		return LevelDebug, true
+	case "info":
+		return LevelInfo, true
+	case "error":
+		return LevelError, true
+	case "fatal":
+		return LevelFatal, true
+	default:
+		return 0, false
+	}
+}
+
+// ParseLevel parses the given level name, e.g. "info". Names are matched case
+// insensitively. Numeric levels, e.g. "1", and "warn" or "warning" are
+// accepted as well.
+func ParseLevel(name string) (Level, error) {
+	if level, found := builtinLevel(strings.ToLower(strings.TrimSpace(name))); found {
+		return level, nil
+	}
+
+	if isWarning(name) {
+		return LevelWarn, nil
+	}
+
+	level, err := strconv.Atoi(name)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid log level %q.", name)
+	}
+
+	return Level(level), nil
+}
+
+// isWarning reports whether the given name is a common name of the warn level,
+// i.e. "warn" or "warning".
+func isWarning(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "warn", "warning":
+		return true
+	default:
+		return false
+	}
+}
+
+// unknownLevel returns the error for a level name of the given format that
+// is not known to the format.
+func unknownLevel(format, name string) error {
+	return fmt.Errorf("Invalid %s level %q. Use Levels to map it onto a glager level.", format, name)
+}
+
+// severity ranks the given level. LevelWarn ranks between lager.INFO and
+// lager.ERROR, all other levels rank by their value.
+func severity(level lager.LogLevel) float64 {
+	if Level(level) == LevelWarn {
+		return float64(lager.INFO) + 0.5
+	}
+	return float64(level)
+}
+
+// String returns the name of the level, e.g. "info".
+func (l Level) String() string {
+	return levelName(lager.LogLevel(l))
+}
+
+// LogLevel returns the level as lager.LogLevel, e.g. to be passed to Entry.
+func (l Level) LogLevel() lager.LogLevel {
+	return lager.LogLevel(l)
+}
+
+type levelMatcher struct {
+	name        string
+	description string
+	allowed     func(entry LogEntry) (bool, error)
+	offending   logEntries
+}
+
+// AtLeast returns a log entry that matches entries of the given level or any
+// level above it, e.g. AtLeast(lager.ERROR) matches both error and fatal
+// entries. Use it to check that a failure has been surfaced without pinning the
+// exact level the implementation chose.
+//
+// Example:
+//
+//	Expect(logger).To(HaveLogged(AtLeast(lager.ERROR, Source("db"))))
+func AtLeast(level lager.LogLevel, options ...Option) logEntry {
+	entry := Entry(level, options...)
+	entry.atLeast = true
+	return entry
+}
+
+// matchesLevel reports whether the given level is the one of the expected
+// entry, or above it if the entry has been specified using AtLeast.
+func (expected logEntry) matchesLevel(level lager.LogLevel) bool {
+	if expected.atLeast {
+		return severity(level) >= severity(expected.LogLevel)
+	}
+	return level == expected.LogLevel
+}
+
+// HaveNoEntriesBelow checks that the log does not contain any entries with a
+// level lower than the given one. Use it to verify that a sink registered at a
+// given minimum level is honored, i.e. that no code bypasses the logger and no
+// sink misapplies its minimum level.
+//
+// Example:
+//
+//	logger := NewLoggerWithLevel("test", lager.INFO)
+//	myFunc(logger)
+//	Expect(logger).To(HaveNoEntriesBelow(lager.INFO))
+func HaveNoEntriesBelow(level lager.LogLevel) types.GomegaMatcher {
+	return &levelMatcher{
+		name:        "HaveNoEntriesBelow",
+		description: fmt.Sprintf("below level %s", levelName(level)),
+		allowed: func(entry LogEntry) (bool, error) {
+			return severity(entry.LogLevel) >= severity(level), nil
+		},
+	}
+}
+
+// HaveNoEntriesAbove checks that the log does not contain any entries with a
+// level higher than the given one. The failure message lists the offending
+// entries.
+//
+// Example:
+//
+//	Expect(logger).To(HaveNoEntriesAbove(lager.INFO))
+func HaveNoEntriesAbove(level lager.LogLevel) types.GomegaMatcher {
+	return &levelMatcher{
+		name:        "HaveNoEntriesAbove",
+		description: fmt.Sprintf("above level %s", levelName(level)),
+		allowed: func(entry LogEntry) (bool, error) {
+			return severity(entry.LogLevel) <= severity(level), nil
+		},
+	}
+}
+
+// HaveNoErrors checks that the log does not contain any error or fatal
+// entries. It is a shorthand for HaveNoEntriesAbove(lager.INFO) that comes in
+// handy as a teardown assertion.
+//
+// Example:
+//
+//	AfterEach(func() {
+//	  Expect(logger).To(HaveNoErrors())
+//	})
+func HaveNoErrors() types.GomegaMatcher {
+	return &levelMatcher{
+		name:        "HaveNoErrors",
+		description: "at level error or fatal",
+		allowed: func(entry LogEntry) (bool, error) {
+			return severity(entry.LogLevel) < severity(lager.ERROR), nil
+		},
+	}
+}
+
+// HaveNoLogs checks that the log does not contain any entries at all. Use it
+// to verify that a component stays silent. The failure message lists the
+// unexpected entries.
+//
+// Example:
+//
+//	Expect(logger).To(HaveNoLogs())
+func HaveNoLogs() types.GomegaMatcher {
+	return &levelMatcher{
+		name:        "HaveNoLogs",
+		description: "at any level",
+		allowed: func(entry LogEntry) (bool, error) {
+			return false, nil
+		},
+	}
+}
+
+// HaveNoLogsFrom checks that the log does not contain any entries, at any
+// level, with the properties specified by the given options, e.g. a source.
+// The failure message lists the unexpected entries.
+//
+// Example:
+//
+//	Expect(logger).To(HaveNoLogsFrom(Source("noisy-component")))
+func HaveNoLogsFrom(options ...Option) types.GomegaMatcher {
+	expected := Entry(lager.INFO, options...)
+
+	rendered := expected.GomegaString()
+	rendered = strings.TrimSuffix(strings.TrimPrefix(rendered, "Info("), ")")
+
+	return &levelMatcher{
+		name:        "HaveNoLogsFrom",
+		description: fmt.Sprintf("with %s", rendered),
+		allowed: func(entry LogEntry) (bool, error) {
+			candidate := expected
+			candidate.LogLevel = entry.LogLevel
+			containsEntry, err := entry.contains(candidate)
+			return !containsEntry, err
+		},
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (lm *levelMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries(lm.name, actual)
+	if err != nil {
+		return false, err
+	}
+
+	lm.offending = logEntries{}
+	for _, entry := range entries {
+		allowed, err := lm.allowed(entry)
+		if err != nil {
+			return false, err
+		}
+
+		if !allowed {
+			lm.offending = append(lm.offending, entry)
+		}
+	}
+
+	return len(lm.offending) == 0, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (lm *levelMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected log to contain no entries %s, found\n%s",
+		lm.description,
+		format.Object(lm.offending, 1),
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (lm *levelMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected log to contain entries %s", lm.description)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (lm *levelMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+func levelName(level lager.LogLevel) string {
+	switch level {
+	case lager.DEBUG:
+		return "debug"
+	case lager.INFO:
+		return "info"
+	case lager.ERROR:
+		return "error"
+	case lager.FATAL:
+		return "fatal"
+	}
+
+	if Level(level) == LevelWarn {
+		return "warn"
+	}
+	return fmt.Sprintf("%d", level)
+}
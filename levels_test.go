@@ -0,0 +1,316 @@
+package glager_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("level matchers", func() {
+	Describe(".NewLoggerWithLevel", func() {
+		It("filters entries below the given level", func() {
+			logger := NewLoggerWithLevel("test", lager.INFO)
+			logger.Debug("debug")
+			logger.Info("info")
+
+			Expect(logger).ToNot(ContainSequence(Debug()))
+			Expect(logger).To(ContainSequence(Info()))
+		})
+	})
+
+	Describe(".AtLeast", func() {
+		var logger *TestLogger
+
+		BeforeEach(func() {
+			logger = NewLogger("db")
+			logger.Info("connect")
+		})
+
+		It("matches entries at the given level", func() {
+			logger.Error("query", errors.New("timeout"))
+			Expect(logger).To(HaveLogged(AtLeast(lager.ERROR, Source("db"))))
+		})
+
+		It("matches entries above the given level", func() {
+			func() {
+				defer func() { recover() }()
+				logger.Fatal("query", errors.New("timeout"))
+			}()
+			Expect(logger).To(HaveLogged(AtLeast(lager.ERROR, Source("db"), Data("error", "timeout"))))
+		})
+
+		It("does not match entries below the given level", func() {
+			matcher := HaveLogged(AtLeast(lager.ERROR, Message("db.connect")))
+			Expect(matcher.Match(logger)).To(BeFalse())
+
+			message := matcher.FailureMessage(logger)
+			Expect(message).To(ContainSubstring(`AtLeast(2, Message("db.connect"))`))
+			Expect(message).To(ContainSubstring("level: expected at least error, got info"))
+		})
+	})
+
+	Describe(".HaveNoEntriesBelow", func() {
+		It("matches a log honoring the sink level", func() {
+			logger := NewLoggerWithLevel("test", lager.ERROR)
+			logger.Debug("debug")
+			logger.Info("info")
+			logger.Error("error", errors.New("some-error"))
+
+			Expect(logger).To(HaveNoEntriesBelow(lager.ERROR))
+		})
+
+		It("does not match a log with entries below the level", func() {
+			log := `{"timestamp":"1.0","source":"test","message":"test.info","log_level":1,"data":{}}
+{"timestamp":"2.0","source":"test","message":"test.error","log_level":2,"data":{}}
+{"timestamp":"3.0","source":"test","message":"test.debug","log_level":0,"data":{}}
+`
+			matcher := HaveNoEntriesBelow(lager.ERROR)
+			Expect(matcher.Match(strings.NewReader(log))).To(BeFalse())
+
+			message := matcher.FailureMessage(nil)
+			Expect(message).To(ContainSubstring("no entries below level error"))
+			Expect(message).To(ContainSubstring("test.info"))
+			Expect(message).To(ContainSubstring("test.debug"))
+			Expect(message).ToNot(ContainSubstring("test.error"))
+		})
+
+		It("returns an error for invalid actuals", func() {
+			_, err := HaveNoEntriesBelow(lager.INFO).Match(42)
+			Expect(err).To(MatchError(ContainSubstring("HaveNoEntriesBelow must be passed")))
+		})
+	})
+
+	Describe(".HaveNoEntriesAbove", func() {
+		It("matches a log without entries above the level", func() {
+			logger := NewLogger("test")
+			logger.Debug("debug")
+			logger.Info("info")
+
+			Expect(logger).To(HaveNoEntriesAbove(lager.INFO))
+			Expect(logger).ToNot(HaveNoEntriesAbove(lager.DEBUG))
+		})
+
+		It("lists the offending entries", func() {
+			logger := NewLogger("test")
+			logger.Info("info")
+			logger.Error("error", errors.New("some-error"))
+
+			matcher := HaveNoEntriesAbove(lager.INFO)
+			Expect(matcher.Match(logger)).To(BeFalse())
+
+			message := matcher.FailureMessage(logger)
+			Expect(message).To(ContainSubstring("no entries above level info"))
+			Expect(message).To(ContainSubstring("test.error"))
+			Expect(message).ToNot(ContainSubstring("test.info"))
+		})
+	})
+
+	Describe(".HaveNoErrors", func() {
+		It("matches a log without error or fatal entries", func() {
+			logger := NewLogger("test")
+			logger.Debug("debug")
+			logger.Info("info")
+
+			Expect(logger).To(HaveNoErrors())
+
+			logger.Error("error", errors.New("some-error"))
+			Expect(logger).ToNot(HaveNoErrors())
+		})
+
+		It("lists the offending entries", func() {
+			logger := NewLogger("test")
+			logger.Error("error", errors.New("some-error"))
+
+			matcher := HaveNoErrors()
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring("no entries at level error or fatal"))
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring("some-error"))
+		})
+	})
+
+	Describe(".HaveNoLogs", func() {
+		It("matches an empty log", func() {
+			logger := NewLogger("test")
+			Expect(logger).To(HaveNoLogs())
+
+			logger.Debug("debug")
+			Expect(logger).ToNot(HaveNoLogs())
+		})
+
+		It("lists the unexpected entries", func() {
+			logger := NewLogger("test")
+			logger.Info("chatty")
+
+			matcher := HaveNoLogs()
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring("no entries at any level"))
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring("test.chatty"))
+		})
+	})
+
+	Describe(".HaveNoLogsFrom", func() {
+		var logger *TestLogger
+
+		BeforeEach(func() {
+			logger = NewLogger("quiet")
+		})
+
+		It("matches a log without entries with the given properties", func() {
+			logger.Info("info")
+			logger.Error("error", errors.New("some-error"))
+
+			Expect(logger).To(HaveNoLogsFrom(Source("noisy")))
+			Expect(logger).To(HaveNoLogsFrom(Source("quiet"), Data("user", "admin")))
+			Expect(logger).ToNot(HaveNoLogsFrom(Source("quiet")))
+			Expect(logger).ToNot(HaveNoLogsFrom(Action("quiet.error")))
+		})
+
+		It("lists the unexpected entries", func() {
+			logger.Debug("debug", lager.Data{"user": "admin"})
+
+			matcher := HaveNoLogsFrom(Source("quiet"), Data("user", "admin"))
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring(`no entries with Source("quiet"), Data("user", "admin"), found`))
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring("quiet.debug"))
+		})
+	})
+
+	Describe("Levels", func() {
+		const levelVerbose = Level(-1)
+
+		var levels Levels
+
+		BeforeEach(func() {
+			levels = Levels{"VERBOSE": levelVerbose}
+		})
+
+		It("maps custom level names of the bundled formats", func() {
+			Expect(WithFormat(levels.Zap(), strings.NewReader(`{"level":"verbose","ts":1,"msg":"cache miss"}`))).To(ContainSequence(Entry(-1, Message("cache miss"))))
+			Expect(WithFormat(levels.Slog(), strings.NewReader(`{"level":"Verbose","time":"1","msg":"cache miss"}`))).To(ContainSequence(Entry(-1, Message("cache miss"))))
+			Expect(WithFormat(levels.Zerolog(), strings.NewReader(`{"level":"verbose","time":1,"message":"cache miss"}`))).To(ContainSequence(Entry(-1, Message("cache miss"))))
+			Expect(WithFormat(levels.Logrus(), strings.NewReader(`{"level":"verbose","time":"1","msg":"cache miss"}`))).To(ContainSequence(Entry(-1, Message("cache miss"))))
+			Expect(WithFormat(levels.Logfmt(), strings.NewReader(`level=verbose msg="cache miss"`))).To(ContainSequence(Entry(-1, Message("cache miss"))))
+		})
+
+		It("keeps the level mapping of the format for other names", func() {
+			log := `{"level":"info","ts":1,"msg":"started"}` + "\n" + `{"level":"dpanic","ts":2,"msg":"invariant violated"}` + "\n"
+
+			Expect(WithFormat(levels.Zap(), strings.NewReader(log))).To(ContainSequence(
+				Info(Message("started")),
+				Error(AnyErr, Message("invariant violated")),
+			))
+		})
+
+		It("takes precedence over the level mapping of the format", func() {
+			levels["dpanic"] = LevelFatal
+
+			log := `{"level":"info","ts":1,"msg":"started"}` + "\n" + `{"level":"dpanic","ts":2,"msg":"invariant violated"}` + "\n"
+
+			Expect(WithFormat(levels.Zap(), strings.NewReader(log))).To(ContainSequence(
+				Info(Message("started")),
+				Fatal(AnyErr, Message("invariant violated")),
+			))
+			Expect(WithFormat(levels.Zap(), strings.NewReader(log))).ToNot(ContainSequence(Error(AnyErr, Message("invariant violated"))))
+			Expect(WithFormat(Zap, strings.NewReader(log))).To(ContainSequence(Error(AnyErr, Message("invariant violated"))))
+		})
+
+		It("aliases warnings onto other levels", func() {
+			levels["WARNING"] = LevelInfo
+
+			Expect(WithFormat(levels.Logrus(), strings.NewReader(`{"level":"warning","time":"1","msg":"slow request"}`))).To(ContainSequence(Info(Message("slow request"))))
+		})
+
+		It("only applies to the log it is used for", func() {
+			log := `{"level":"verbose","ts":1,"msg":"cache miss"}` + "\n"
+
+			Expect(WithFormat(levels.Zap(), strings.NewReader(log))).To(ContainSequence(Entry(-1, Message("cache miss"))))
+
+			_, err := ContainSequence(Entry(-1)).Match(WithFormat(Zap, strings.NewReader(log)))
+			Expect(err).To(MatchError(ContainSubstring(`Invalid zap level "verbose". Use Levels to map it onto a glager level`)))
+		})
+
+		It("renders custom levels as numbers", func() {
+			Expect(levelVerbose.String()).To(Equal("-1"))
+		})
+	})
+
+	Describe(".Warn", func() {
+		const log = `{"level":"info","ts":1,"msg":"started"}
+{"level":"warn","ts":2,"msg":"slow request"}
+{"level":"error","ts":3,"msg":"request failed"}
+`
+
+		It("matches warnings", func() {
+			Expect(WithFormat(Zap, strings.NewReader(log))).To(ContainSequence(
+				Info(Message("started")),
+				Warn(Message("slow request")),
+				Error(AnyErr, Message("request failed")),
+			))
+			Expect(WithFormat(Zap, strings.NewReader(log))).ToNot(ContainSequence(Info(Message("slow request"))))
+		})
+
+		It("ranks warnings between info and error", func() {
+			Expect(WithFormat(Zap, strings.NewReader(log))).To(HaveEntryCount(2, AtLeast(LevelWarn.LogLevel())))
+			Expect(WithFormat(Zap, strings.NewReader(log))).To(HaveEntryCount(1, AtLeast(lager.ERROR)))
+
+			warnings := `{"level":"info","ts":1,"msg":"started"}` + "\n" + `{"level":"warn","ts":2,"msg":"slow request"}` + "\n"
+			Expect(WithFormat(Zap, strings.NewReader(warnings))).To(HaveNoErrors())
+			Expect(WithFormat(Zap, strings.NewReader(warnings))).ToNot(HaveNoEntriesAbove(lager.INFO))
+			Expect(WithFormat(Zap, strings.NewReader(warnings))).To(HaveNoEntriesAbove(LevelWarn.LogLevel()))
+		})
+
+		It("is rendered in failure messages", func() {
+			failed := `{"level":"error","ts":3,"msg":"request failed"}` + "\n"
+
+			matcher := ContainSequence(Warn(Message("request failed")))
+			Expect(matcher.Match(WithFormat(Zap, strings.NewReader(failed)))).To(BeFalse())
+
+			message := matcher.FailureMessage(WithFormat(Zap, strings.NewReader(failed)))
+			Expect(message).To(ContainSubstring(`0: Warn(Message("request failed"))`))
+			Expect(message).To(ContainSubstring("level: expected warn, got error"))
+		})
+	})
+
+	Describe("Level", func() {
+		It("parses level names", func() {
+			Expect(ParseLevel("debug")).To(Equal(LevelDebug))
+			Expect(ParseLevel("INFO")).To(Equal(LevelInfo))
+			Expect(ParseLevel(" Error ")).To(Equal(LevelError))
+			Expect(ParseLevel("fatal")).To(Equal(LevelFatal))
+			Expect(ParseLevel("WARN")).To(Equal(LevelWarn))
+			Expect(ParseLevel("warning")).To(Equal(LevelWarn))
+		})
+
+		It("parses numeric levels", func() {
+			Expect(ParseLevel("2")).To(Equal(LevelError))
+		})
+
+		It("returns an error for invalid levels", func() {
+			_, err := ParseLevel("verbose")
+			Expect(err).To(MatchError(`Invalid log level "verbose".`))
+		})
+
+		It("renders level names", func() {
+			Expect(LevelInfo.String()).To(Equal("info"))
+			Expect(fmt.Sprintf("%s", LevelFatal)).To(Equal("fatal"))
+			Expect(Level(7).String()).To(Equal("7"))
+		})
+
+		It("converts into lager levels", func() {
+			level, err := ParseLevel("error")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(level.LogLevel()).To(Equal(lager.ERROR))
+
+			logger := NewLogger("test")
+			logger.Error("failed", errors.New("boom"))
+			Expect(logger).To(HaveLogged(Entry(level.LogLevel())))
+		})
+	})
+})
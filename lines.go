@@ -0,0 +1,211 @@
+package glager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/onsi/gomega/types"
+)
+
+// maxLineLength is the number of bytes of an invalid line that is included in
+// failure messages.
+const maxLineLength = 256
+
+type lineMatcher struct {
+	name        string
+	description string
+	validate    func(line []byte) string
+	terminated  bool
+	line        int
+	content     []byte
+	reason      string
+}
+
+// BeNDJSON checks that every line of the log is a complete and valid JSON
+// object and that every line, including the last one, is terminated by a
+// newline. Use it to catch writers that split entries across writes or append
+// trailing garbage, both of which break downstream log shippers.
+func BeNDJSON() types.GomegaMatcher {
+	return &lineMatcher{
+		name:        "BeNDJSON",
+		description: "to be newline delimited JSON",
+		terminated:  true,
+		validate: func(line []byte) string {
+			if !json.Valid(line) {
+				return "invalid JSON"
+			}
+
+			if trimmed := bytes.TrimSpace(line); len(trimmed) == 0 || trimmed[0] != '{' {
+				return "not a JSON object"
+			}
+
+			return ""
+		},
+	}
+}
+
+// escapedControlChar matches JSON escaped control characters other than tabs,
+// newlines, and carriage returns, e.g. the escape character starting an ANSI
+// escape sequence.
+var escapedControlChar = regexp.MustCompile(`(^|[^\\])(\\\\)*\\u00(0[0-8bBcCeEfF]|1[0-9a-fA-F]|7[fF])`)
+
+// BeCleanUTF8 checks that the log is valid UTF-8 and free of control
+// characters, both raw and JSON escaped ones, including ANSI escape sequences.
+// Tabs, as well as escaped newlines and carriage returns, e.g. inside of stack
+// traces, are permitted.
+func BeCleanUTF8() types.GomegaMatcher {
+	return &lineMatcher{
+		name:        "BeCleanUTF8",
+		description: "to be valid UTF-8 without control characters",
+		validate: func(line []byte) string {
+			if !utf8.Valid(line) {
+				return "invalid UTF-8"
+			}
+
+			for _, b := range line {
+				if b < 0x20 && b != '\t' || b == 0x7f {
+					return fmt.Sprintf("raw control character 0x%02x", b)
+				}
+			}
+
+			if escapedControlChar.Match(line) {
+				return "escaped control character"
+			}
+
+			return ""
+		},
+	}
+}
+
+// HaveNoLineLongerThan checks that no line of the log is longer than the given
+// number of bytes, not counting the terminating newline. Use it to enforce the
+// limits of transports like syslog over UDP that silently drop oversized
+// entries.
+func HaveNoLineLongerThan(n int) types.GomegaMatcher {
+	return &lineMatcher{
+		name:        "HaveNoLineLongerThan",
+		description: fmt.Sprintf("to have no line longer than %d bytes", n),
+		validate: func(line []byte) string {
+			if len(line) > n {
+				return fmt.Sprintf("%d bytes", len(line))
+			}
+			return ""
+		},
+	}
+}
+
+type sizeMatcher struct {
+	budget int
+	size   int
+}
+
+// HaveLoggedAtMostBytes checks that the total size of the log does not exceed
+// the given budget in bytes. Use it to keep performance-sensitive code paths
+// from regressing into excessive debug output.
+func HaveLoggedAtMostBytes(budget int) types.GomegaMatcher {
+	return &sizeMatcher{
+		budget: budget,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (sm *sizeMatcher) Match(actual interface{}) (success bool, err error) {
+	reader, err := contentsReader("HaveLoggedAtMostBytes", actual)
+	if err != nil {
+		return false, err
+	}
+	defer closeContents(reader)
+
+	size, err := io.Copy(io.Discard, reader)
+	if err != nil {
+		return false, err
+	}
+
+	sm.size = int(size)
+	return sm.size <= sm.budget, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (sm *sizeMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected log of %d bytes to stay within budget of %d bytes", sm.size, sm.budget)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (sm *sizeMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected log of %d bytes to exceed budget of %d bytes", sm.size, sm.budget)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (sm *sizeMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (lm *lineMatcher) Match(actual interface{}) (success bool, err error) {
+	reader, err := contentsReader(lm.name, actual)
+	if err != nil {
+		return false, err
+	}
+	defer closeContents(reader)
+
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return false, err
+	}
+
+	lm.line = 0
+	lm.content = nil
+	lm.reason = ""
+
+	lines := bytes.SplitAfter(contents, []byte("\n"))
+
+	for i, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+
+		if line[len(line)-1] == '\n' {
+			line = line[:len(line)-1]
+		} else if lm.terminated {
+			lm.line, lm.content, lm.reason = i+1, line, "missing trailing newline"
+			return false, nil
+		}
+
+		if reason := lm.validate(line); reason != "" {
+			lm.line, lm.content, lm.reason = i+1, line, reason
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (lm *lineMatcher) FailureMessage(actual interface{}) (message string) {
+	content := lm.content
+	if len(content) > maxLineLength {
+		content = append(content[:maxLineLength:maxLineLength], "..."...)
+	}
+
+	return fmt.Sprintf(
+		"Expected log %s, line %d is invalid (%s):\n\t%q",
+		lm.description,
+		lm.line,
+		lm.reason,
+		content,
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (lm *lineMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected log not %s", lm.description)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (lm *lineMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
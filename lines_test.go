@@ -0,0 +1,137 @@
+package glager_test
+
+import (
+	"errors"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("line matchers", func() {
+	Describe(".BeNDJSON", func() {
+		It("matches a log written by lager", func() {
+			logger := NewLogger("test")
+			logger.Info("first")
+			logger.Debug("second")
+
+			Expect(logger).To(BeNDJSON())
+		})
+
+		It("matches an empty log", func() {
+			Expect(strings.NewReader("")).To(BeNDJSON())
+		})
+
+		table.DescribeTable("detecting malformed lines",
+			func(log string, line int, reason string) {
+				matcher := BeNDJSON()
+				Expect(matcher.Match(strings.NewReader(log))).To(BeFalse())
+				Expect(matcher.FailureMessage(nil)).To(ContainSubstring("line %d is invalid (%s)", line, reason))
+			},
+			table.Entry("entry split across lines", "{\"a\":1}\n{\"b\":\n2}\n", 2, "invalid JSON"),
+			table.Entry("trailing garbage", "{\"a\":1} garbage\n", 1, "invalid JSON"),
+			table.Entry("empty line", "{\"a\":1}\n\n{\"b\":2}\n", 2, "invalid JSON"),
+			table.Entry("JSON array", "[1,2]\n", 1, "not a JSON object"),
+			table.Entry("missing newline", "{\"a\":1}\n{\"b\":2}", 2, "missing trailing newline"),
+		)
+
+		It("returns an error for invalid actuals", func() {
+			_, err := BeNDJSON().Match(42)
+			Expect(err).To(MatchError(ContainSubstring("BeNDJSON must be passed")))
+		})
+	})
+
+	Describe(".BeCleanUTF8", func() {
+		It("matches a log written by lager", func() {
+			logger := NewLogger("test")
+			logger.Info("first", lager.Data{"text": "grüße\tnach\nhause", "quoted": `\u001b`})
+			Expect(func() { logger.Fatal("crash", errors.New("boom")) }).To(Panic())
+
+			Expect(logger).To(BeCleanUTF8())
+		})
+
+		It("does not require a trailing newline", func() {
+			Expect(strings.NewReader(`{"a":"b"}`)).To(BeCleanUTF8())
+		})
+
+		table.DescribeTable("detecting unclean lines",
+			func(log string, line int, reason string) {
+				matcher := BeCleanUTF8()
+				Expect(matcher.Match(strings.NewReader(log))).To(BeFalse())
+				Expect(matcher.FailureMessage(nil)).To(ContainSubstring("line %d is invalid (%s)", line, reason))
+			},
+			table.Entry("invalid UTF-8", "{}\n{\"a\":\"\xff\"}\n", 2, "invalid UTF-8"),
+			table.Entry("raw ANSI escape", "\x1b[31m{}\n", 1, "raw control character 0x1b"),
+			table.Entry("raw delete", "{\"a\":\"\x7f\"}\n", 1, "raw control character 0x7f"),
+			table.Entry("escaped ANSI escape", `{"a":"\u001b[31mred"}`, 1, "escaped control character"),
+			table.Entry("escaped NUL", `{"a":"\\\u0000"}`, 1, "escaped control character"),
+		)
+
+		It("detects escaped ANSI sequences logged by lager", func() {
+			logger := NewLogger("test")
+			logger.Info("colored", lager.Data{"text": "\x1b[31mred\x1b[0m"})
+
+			Expect(logger).ToNot(BeCleanUTF8())
+		})
+	})
+
+	Describe(".HaveNoLineLongerThan", func() {
+		var logger *TestLogger
+
+		BeforeEach(func() {
+			logger = NewLogger("test")
+			logger.Info("short")
+		})
+
+		It("matches a log with short lines", func() {
+			Expect(logger).To(HaveNoLineLongerThan(1024))
+		})
+
+		It("does not count the newline", func() {
+			Expect(strings.NewReader("1234\n")).To(HaveNoLineLongerThan(4))
+			Expect(strings.NewReader("12345\n")).ToNot(HaveNoLineLongerThan(4))
+		})
+
+		It("reports the offending line truncated", func() {
+			logger.Info("long", lager.Data{"payload": strings.Repeat("x", 64*1024)})
+
+			matcher := HaveNoLineLongerThan(64 * 1024)
+			Expect(matcher.Match(logger)).To(BeFalse())
+
+			message := matcher.FailureMessage(logger)
+			Expect(message).To(ContainSubstring("to have no line longer than 65536 bytes, line 2 is invalid"))
+			Expect(message).To(ContainSubstring(`xxx..."`))
+			Expect(len(message)).To(BeNumerically("<", 1024))
+		})
+	})
+
+	Describe(".HaveLoggedAtMostBytes", func() {
+		It("matches a log within budget", func() {
+			Expect(strings.NewReader("1234\n")).To(HaveLoggedAtMostBytes(5))
+			Expect(strings.NewReader("")).To(HaveLoggedAtMostBytes(0))
+		})
+
+		It("does not match a log exceeding the budget", func() {
+			matcher := HaveLoggedAtMostBytes(4)
+			Expect(matcher.Match(strings.NewReader("1234\n"))).To(BeFalse())
+			Expect(matcher.FailureMessage(nil)).To(Equal("Expected log of 5 bytes to stay within budget of 4 bytes"))
+		})
+
+		It("works with a logger", func() {
+			logger := NewLogger("test")
+			logger.Debug("hot-path", lager.Data{"payload": strings.Repeat("x", 1024)})
+
+			Expect(logger).ToNot(HaveLoggedAtMostBytes(1024))
+		})
+
+		It("returns an error for invalid actuals", func() {
+			_, err := HaveLoggedAtMostBytes(1).Match(42)
+			Expect(err).To(MatchError(ContainSubstring("HaveLoggedAtMostBytes must be passed")))
+		})
+	})
+})
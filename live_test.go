@@ -0,0 +1,101 @@
+package glager_test
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+type contents []byte
+
+func (c contents) Contents() []byte {
+	return c
+}
+
+var _ = Describe("live buffers", func() {
+	It("can be matched while being written to", func() {
+		logger := NewLogger("server")
+
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func(worker lager.Logger) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					worker.Info("request", lager.Data{"i": i})
+					time.Sleep(time.Millisecond)
+				}
+			}(WorkerLogger(logger, fmt.Sprintf("worker-%d", w)))
+		}
+
+		Eventually(logger).Should(HaveLogged(
+			Info(Worker("worker-0"), Data("i", 0)),
+			Info(Worker("worker-0"), Data("i", 49)),
+		))
+		Eventually(logger).Should(EachWorker(ContainSequence(Info(Data("i", 49)))))
+
+		wg.Wait()
+	})
+
+	Context("when polled by Eventually while many goroutines are logging", func() {
+		logConcurrently := func(logger lager.Logger) *sync.WaitGroup {
+			wg := &sync.WaitGroup{}
+			for w := 0; w < 8; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer GinkgoRecover()
+					defer wg.Done()
+					for i := 0; i < 100; i++ {
+						logger.Info("request", lager.Data{"worker": w, "i": i})
+					}
+				}(w)
+			}
+			return wg
+		}
+
+		It("matches a lagertest.TestSink", func() {
+			logger := lager.NewLogger("server")
+			sink := lagertest.NewTestSink()
+			logger.RegisterSink(sink)
+
+			wg := logConcurrently(logger)
+			Eventually(sink).Should(HaveEntryCount(800, Info(Action("server.request"))))
+			wg.Wait()
+		})
+
+		It("matches a ConcurrentBuffer", func() {
+			logger := lager.NewLogger("server")
+			buffer := NewConcurrentBuffer()
+			logger.RegisterSink(buffer)
+
+			wg := logConcurrently(logger)
+			Eventually(buffer).Should(HaveEntryCount(800, Info(Action("server.request"))))
+			wg.Wait()
+		})
+	})
+
+	It("ignores an entry that is still being written", func() {
+		log := contents(`{"timestamp":"1","source":"server","message":"server.start","log_level":1,"data":{}}` + "\n" +
+			`{"timestamp":"2","source":"server","mess`)
+
+		Expect(log).To(HaveLogged(Info(Action("server.start"))))
+		Expect(Entries(log)).To(HaveLen(1))
+	})
+
+	It("does not ignore invalid entries", func() {
+		log := contents(`{"timestamp":"1","source":"server","message":"server.start","log_level":1,"data":{}}` + "\n" +
+			`{"timestamp":"2",}` + "\n")
+
+		_, err := HaveLogged(Info()).Match(log)
+		Expect(err).To(HaveOccurred())
+	})
+})
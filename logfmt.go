@@ -0,0 +1,125 @@
+package glager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// Logfmt is a Format for logs written in logfmt, e.g.
+// `level=info msg="server started" port=8080`. The "time", "level", and "msg"
+// keys are mapped to the timestamp, level, and message of the entry, "ts" is
+// accepted as timestamp as well. All other keys become its data. Since logfmt
+// is untyped, data values are always strings, keys without a value are true.
+// The levels trace and debug map to lager.DEBUG, info to lager.INFO, warn to
+// LevelWarn, error to lager.ERROR, and fatal, panic, and crit to lager.FATAL.
+func Logfmt(line []byte) ([]byte, error) {
+	return convertLogfmt(line, commonLevels("logfmt"))
+}
+
+// Logfmt returns the Logfmt format, mapping level names using the given levels
+// first.
+func (l Levels) Logfmt() Format {
+	return func(line []byte) ([]byte, error) {
+		return convertLogfmt(line, l.parser(commonLevels("logfmt")))
+	}
+}
+
+// convertLogfmt converts a logfmt line to the lager format, parsing its level
+// using the given function.
+func convertLogfmt(line []byte, parseLevel func(string) (lager.LogLevel, error)) ([]byte, error) {
+	fields, err := parseLogfmt(string(line))
+	if err != nil {
+		return nil, err
+	}
+
+	if ts, found := fields["ts"]; found {
+		if _, found := fields["time"]; !found {
+			fields["time"] = ts
+			delete(fields, "ts")
+		}
+	}
+
+	return convertFields(fields, fieldKeys{
+		time:    "time",
+		level:   "level",
+		message: "msg",
+	}, parseLevel)
+}
+
+// parseLogfmt parses the key-value pairs of a logfmt line.
+func parseLogfmt(line string) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+
+	for i := 0; i < len(line); {
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' && line[i] != '\t' {
+			i++
+		}
+		key := line[start:i]
+
+		if i == len(line) || line[i] != '=' {
+			fields[key] = true
+			continue
+		}
+		i++
+
+		if i < len(line) && line[i] == '"' {
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil, fmt.Errorf("Invalid logfmt line %q. Unterminated quoted value of key %q.", line, key)
+			}
+
+			value, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid logfmt line %q. Malformed quoted value of key %q.", line, key)
+			}
+			fields[key] = value
+			i = end + 1
+			continue
+		}
+
+		start = i
+		for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+			i++
+		}
+		fields[key] = line[start:i]
+	}
+
+	return fields, nil
+}
+
+// commonLevels returns a function that maps a level name commonly used by
+// logging libraries, e.g. "warning", to the corresponding lager level. The
+// name of the format is used in errors.
+func commonLevels(format string) func(name string) (lager.LogLevel, error) {
+	return func(name string) (lager.LogLevel, error) {
+		switch strings.ToLower(name) {
+		case "trace", "debug":
+			return lager.DEBUG, nil
+		case "info":
+			return lager.INFO, nil
+		case "warn", "warning":
+			return LevelWarn.LogLevel(), nil
+		case "error", "err":
+			return lager.ERROR, nil
+		case "fatal", "panic", "crit":
+			return lager.FATAL, nil
+		default:
+			return 0, unknownLevel(format, name)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package glager_test
+
+import (
+	"errors"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("Logfmt", func() {
+	const log = `time=2024-01-01T00:00:00Z level=info msg="server started" port=8080 tls
+ts=2024-01-01T00:00:01.5Z level=error msg="request failed" error="read: \"boom\"" path=/v1
+`
+
+	It("maps logfmt entries onto lager entries", func() {
+		Expect(WithFormat(Logfmt, strings.NewReader(log))).To(ContainSequence(
+			Info(Message("server started"), ExactData("port", "8080", "tls", true)),
+			Error(errors.New(`read: "boom"`), Message("request failed"), Data("path", "/v1")),
+		))
+	})
+
+	It("parses the timestamps", func() {
+		entries, err := Entries(WithFormat(Logfmt, strings.NewReader(log)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(2))
+
+		t, err := entries[1].Time()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(t.UnixMilli()).To(Equal(int64(1704067201500)))
+	})
+
+	table.DescribeTable("mapping levels",
+		func(level string, expected lager.LogLevel) {
+			Expect(WithFormat(Logfmt, strings.NewReader("level="+level+" msg=test\n"))).To(ContainSequence(Entry(expected, Message("test"))))
+		},
+		table.Entry("trace", "trace", lager.DEBUG),
+		table.Entry("debug", "DEBUG", lager.DEBUG),
+		table.Entry("info", "info", lager.INFO),
+		table.Entry("warn", "warn", LevelWarn.LogLevel()),
+		table.Entry("warning", "warning", LevelWarn.LogLevel()),
+		table.Entry("error", "err", lager.ERROR),
+		table.Entry("fatal", "fatal", lager.FATAL),
+	)
+
+	It("returns an error for unterminated quoted values", func() {
+		_, err := ContainSequence(Info()).Match(WithFormat(Logfmt, strings.NewReader(`level=info msg="oops`+"\n")))
+		Expect(err).To(MatchError(ContainSubstring(`Unterminated quoted value of key "msg".`)))
+	})
+
+	It("returns an error for invalid levels", func() {
+		_, err := ContainSequence(Info()).Match(WithFormat(Logfmt, strings.NewReader("level=loud msg=test\n")))
+		Expect(err).To(MatchError(ContainSubstring(`Invalid logfmt level "loud". Use Levels to map it onto a glager level`)))
+	})
+})
@@ -0,0 +1,128 @@
+package glager
+
+import (
+	"sort"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// OriginKey is the data key used to tag the entries of a merged log with the
+// name of the log they originate from.
+const OriginKey = "origin"
+
+type originLog struct {
+	name string
+	log  interface{}
+}
+
+type mergedLog []originLog
+
+// FromOrigin names a log that is to be merged with others using Merge. The log
+// can be anything that is accepted by the ContainSequence matcher.
+func FromOrigin(name string, log interface{}) originLog {
+	return originLog{
+		name: name,
+		log:  log,
+	}
+}
+
+// Merge combines the entries of several logs into a single one that can be
+// passed to any of the matchers. The entries are ordered by their timestamps,
+// and every entry is tagged with the name of the log it originates from using
+// the data key OriginKey. Logs are only read when matching.
+//
+// Example:
+//
+//	Expect(Merge(
+//	  FromOrigin("server", serverLog),
+//	  FromOrigin("client", clientLog),
+//	)).To(ContainSequence(
+//	  Info(Origin("client"), Action("client.request")),
+//	  Info(Origin("server"), Action("server.handle")),
+//	  Info(Origin("client"), Action("client.response")),
+//	))
+func Merge(logs ...originLog) mergedLog {
+	return mergedLog(logs)
+}
+
+// Interleave combines the entries of several logs into a single one, ordered
+// by their timestamps, just like Merge does. Unlike Merge, the entries are not
+// tagged with their origin. Use it to match a single sequence across streams
+// that belong together, e.g. the stdout and stderr of a gexec.Session.
+//
+// Example:
+//
+//	Expect(Interleave(session.Out, session.Err)).To(ContainSequence(
+//	  Info(Action("app.start")),
+//	  Error(AnyErr, Action("app.failed")),
+//	  Info(Action("app.exit")),
+//	))
+func Interleave(logs ...interface{}) mergedLog {
+	merged := mergedLog{}
+	for _, log := range logs {
+		merged = append(merged, FromOrigin("", log))
+	}
+	return merged
+}
+
+// Origin specifies the name of the log a given entry of a merged log
+// originates from. It is a shorthand for Data(OriginKey, name).
+func Origin(name string) Option {
+	return Data(OriginKey, name)
+}
+
+func (logs mergedLog) entries() (logEntries, error) {
+	result := logEntries{}
+	for _, origin := range logs {
+		entries, err := parseEntries("Merge", origin.log)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if origin.name == "" {
+				result = append(result, entry)
+				continue
+			}
+
+			data := lager.Data{}
+			for k, v := range entry.Data {
+				data[k] = v
+			}
+			data[OriginKey] = origin.name
+
+			entry.Data = data
+			result = append(result, entry)
+		}
+	}
+
+	return result.sortByTime()
+}
+
+// sortByTime returns the entries ordered by their timestamps. Entries with the
+// same timestamp retain their original order.
+func (entries logEntries) sortByTime() (logEntries, error) {
+	times := make([]int64, len(entries))
+	for i, entry := range entries {
+		t, err := entry.Time()
+		if err != nil {
+			return nil, err
+		}
+		times[i] = t.UnixNano()
+	}
+
+	indices := make([]int, len(entries))
+	for i := range indices {
+		indices[i] = i
+	}
+
+	sort.SliceStable(indices, func(a, b int) bool {
+		return times[indices[a]] < times[indices[b]]
+	})
+
+	result := make(logEntries, len(entries))
+	for i, index := range indices {
+		result[i] = entries[index]
+	}
+	return result, nil
+}
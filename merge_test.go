@@ -0,0 +1,141 @@
+package glager_test
+
+import (
+	"errors"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("merged logs", func() {
+	var server, client *TestLogger
+
+	BeforeEach(func() {
+		server = NewLogger("server")
+		client = NewLogger("client")
+
+		client.Info("request")
+		server.Info("handle", lager.Data{"id": 1})
+		client.Info("response")
+	})
+
+	Describe(".Merge", func() {
+		It("orders the entries by their timestamps", func() {
+			Expect(Merge(
+				FromOrigin("server", server),
+				FromOrigin("client", client),
+			)).To(ContainSequence(
+				Info(Action("client.request")),
+				Info(Action("server.handle"), Data("id", 1)),
+				Info(Action("client.response")),
+			))
+		})
+
+		It("orders entries with the same timestamp by origin", func() {
+			first := strings.NewReader(`{"timestamp":"1.0","source":"first","message":"a","log_level":1,"data":{}}`)
+			second := strings.NewReader(`{"timestamp":"1.0","source":"second","message":"b","log_level":1,"data":{}}`)
+
+			entries, err := Entries(Merge(FromOrigin("first", first), FromOrigin("second", second)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].Source).To(Equal("first"))
+			Expect(entries[1].Source).To(Equal("second"))
+		})
+
+		It("can be used with matchers that read raw contents", func() {
+			Expect(Merge(FromOrigin("server", server), FromOrigin("client", client))).To(BeNDJSON())
+		})
+
+		It("returns an error for invalid logs", func() {
+			_, err := ContainSequence().Match(Merge(FromOrigin("invalid", 42)))
+			Expect(err).To(MatchError(ContainSubstring("Merge must be passed")))
+		})
+
+		It("returns an error for invalid timestamps", func() {
+			log := strings.NewReader(`{"timestamp":"invalid","source":"test","message":"a","log_level":1,"data":{}}`)
+
+			_, err := ContainSequence().Match(Merge(FromOrigin("invalid", log)))
+			Expect(err).To(MatchError(ContainSubstring("Invalid timestamp")))
+		})
+	})
+
+	Describe(".Origin", func() {
+		It("matches the origin of merged entries", func() {
+			merged := Merge(FromOrigin("server", server), FromOrigin("client", client))
+
+			Expect(merged).To(ContainSequence(
+				Info(Origin("client")),
+				Info(Origin("server")),
+				Info(Origin("client")),
+			))
+			Expect(merged).ToNot(ContainSequence(
+				Info(Origin("server")),
+				Info(Origin("server")),
+			))
+		})
+	})
+
+	Describe(".Interleave", func() {
+		It("matches a sequence spanning several streams", func() {
+			stdout := gbytes.NewBuffer()
+			stderr := gbytes.NewBuffer()
+
+			out := lager.NewLogger("app")
+			out.RegisterSink(lager.NewWriterSink(stdout, lager.DEBUG))
+			err := lager.NewLogger("app")
+			err.RegisterSink(lager.NewWriterSink(stderr, lager.DEBUG))
+
+			out.Info("start")
+			err.Error("failed", errors.New("boom"))
+			out.Info("exit")
+
+			Expect(stdout).ToNot(ContainSequence(
+				Error(AnyErr, Action("app.failed")),
+				Info(Action("app.exit")),
+			))
+
+			Expect(Interleave(stdout, stderr)).To(ContainSequence(
+				Info(Action("app.start")),
+				Error(errors.New("boom"), Action("app.failed")),
+				Info(Action("app.exit")),
+			))
+		})
+
+		It("reads the logs every time it is matched", func() {
+			bufA := gbytes.NewBuffer()
+			bufB := gbytes.NewBuffer()
+
+			a := lager.NewLogger("a")
+			a.RegisterSink(lager.NewWriterSink(bufA, lager.DEBUG))
+			b := lager.NewLogger("b")
+			b.RegisterSink(lager.NewWriterSink(bufB, lager.DEBUG))
+
+			merged := Interleave(bufA, bufB)
+			sequence := ContainSequence(Info(Action("a.request")), Info(Action("b.receipt")))
+
+			b.Info("ready")
+			Expect(merged).ToNot(sequence)
+
+			go func() {
+				a.Info("request")
+				b.Info("receipt")
+			}()
+
+			Eventually(merged).Should(sequence)
+			Expect(merged).ToNot(ContainSequence(Info(Action("b.receipt")), Info(Action("a.request"))))
+		})
+
+		It("does not tag entries with their origin", func() {
+			entries, err := Entries(Interleave(server, client))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(3))
+			Expect(entries[0].Data).ToNot(HaveKey(OriginKey))
+		})
+	})
+})
@@ -0,0 +1,220 @@
+package glager
+
+import (
+	"fmt"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// Counts maps the values entries have been grouped by to the number of entries
+// in each group. It can be asserted on using Gomega's map matchers, e.g.
+// HaveKeyWithValue.
+type Counts map[string]int
+
+// CountByAction parses the given actual and counts its entries by their
+// actions. If any entries are given, only the entries matching any of them are
+// counted. The actual can be anything that is accepted by the ContainSequence
+// matcher.
+//
+// Example:
+//
+//	counts, err := CountByAction(logger, Error(AnyErr))
+//	Expect(err).ToNot(HaveOccurred())
+//	Expect(counts).To(HaveKeyWithValue("cache.miss", 3))
+func CountByAction(actual interface{}, entries ...ExpectedEntry) (Counts, error) {
+	return countBy("CountByAction", actual, entries, func(entry LogEntry) (string, bool, error) {
+		return entry.Message, true, nil
+	})
+}
+
+// CountByLevel parses the given actual and counts its entries by the names of
+// their log levels, e.g. "info". If any entries are given, only the entries
+// matching any of them are counted.
+func CountByLevel(actual interface{}, entries ...ExpectedEntry) (Counts, error) {
+	return countBy("CountByLevel", actual, entries, func(entry LogEntry) (string, bool, error) {
+		return levelName(entry.LogLevel), true, nil
+	})
+}
+
+// CountByData parses the given actual and counts its entries by the value of
+// the given data key. String values are used as is, all other values are
+// rendered as JSON. Entries without the key are not counted. If any entries
+// are given, only the entries matching any of them are counted.
+//
+// Example:
+//
+//	misses, err := CountByData(logger, "tenant", Info(Action("cache.miss")))
+//	Expect(err).ToNot(HaveOccurred())
+//	Expect(misses).To(Equal(Counts{"acme": 2, "globex": 1}))
+func CountByData(actual interface{}, key string, entries ...ExpectedEntry) (Counts, error) {
+	return countBy("CountByData", actual, entries, func(entry LogEntry) (string, bool, error) {
+		value, found := entry.Data[key]
+		if !found {
+			return "", false, nil
+		}
+
+		if s, ok := value.(string); ok {
+			return s, true, nil
+		}
+
+		encoded, err := canonicalJSON(value)
+		return string(encoded), true, err
+	})
+}
+
+func countBy(name string, actual interface{}, specs []logEntry, group func(LogEntry) (string, bool, error)) (Counts, error) {
+
+	entries, err := parseEntries(name, actual)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err = entries.filter(specs)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := Counts{}
+	for _, entry := range entries {
+		key, ok, err := group(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			counts[key]++
+		}
+	}
+	return counts, nil
+}
+
+// The bounds of the count of an entryCountMatcher, besides an exact count.
+const (
+	boundAtMost  = "at most"
+	boundAtLeast = "at least"
+)
+
+type entryCountMatcher struct {
+	name    string
+	count   interface{}
+	bound   string
+	filters []logEntry
+	found   int
+}
+
+// HaveEntryCount checks the number of entries matching any of the given
+// entries. The count can be an int or a Gomega matcher, e.g.
+// BeNumerically("<=", 10). If no entries are given, all entries in the log are
+// counted. Use it to catch log spam or to verify the number of retries.
+//
+// Example:
+//
+//	Expect(logger).To(HaveEntryCount(3, Error(AnyErr, Action("client.retry"))))
+//	Expect(logger).To(HaveEntryCount(BeNumerically("<=", 10)))
+func HaveEntryCount(count interface{}, entries ...ExpectedEntry) types.GomegaMatcher {
+
+	return &entryCountMatcher{
+		name:    "HaveEntryCount",
+		count:   count,
+		filters: entries,
+	}
+}
+
+// HaveAtMost checks that the log contains at most n entries matching any of
+// the given entries, or at most n entries at all if none are given. Use it to
+// enforce log volume budgets, e.g. that a request handler logs at most two
+// info entries.
+//
+// Example:
+//
+//	Expect(logger).To(HaveAtMost(2, Info(Source("handler"))))
+func HaveAtMost(n int, entries ...ExpectedEntry) types.GomegaMatcher {
+
+	return &entryCountMatcher{
+		name:    "HaveAtMost",
+		count:   n,
+		bound:   boundAtMost,
+		filters: entries,
+	}
+}
+
+// HaveAtLeast checks that the log contains at least n entries matching any of
+// the given entries, or at least n entries at all if none are given.
+//
+// Example:
+//
+//	Expect(logger).To(HaveAtLeast(1, Error(AnyErr)))
+func HaveAtLeast(n int, entries ...ExpectedEntry) types.GomegaMatcher {
+
+	return &entryCountMatcher{
+		name:    "HaveAtLeast",
+		count:   n,
+		bound:   boundAtLeast,
+		filters: entries,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (cm *entryCountMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries(cm.name, actual)
+	if err != nil {
+		return false, err
+	}
+
+	entries, err = entries.filter(cm.filters)
+	if err != nil {
+		return false, err
+	}
+
+	cm.found = len(entries)
+
+	switch count := cm.count.(type) {
+	case int:
+		switch cm.bound {
+		case boundAtMost:
+			return cm.found <= count, nil
+		case boundAtLeast:
+			return cm.found >= count, nil
+		default:
+			return cm.found == count, nil
+		}
+	case types.GomegaMatcher:
+		return count.Match(cm.found)
+	default:
+		return false, fmt.Errorf("HaveEntryCount must be passed an int or a matcher as count. Got:\n%s", format.Object(cm.count, 1))
+	}
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (cm *entryCountMatcher) FailureMessage(actual interface{}) (message string) {
+	return cm.message("to be")
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (cm *entryCountMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return cm.message("not to be")
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (cm *entryCountMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+func (cm *entryCountMatcher) message(expectation string) string {
+	if cm.bound != "" {
+		expectation += " " + cm.bound
+	}
+
+	if len(cm.filters) == 0 {
+		return fmt.Sprintf("Expected the number of entries %s %s, found %d", expectation, describeValue(cm.count), cm.found)
+	}
+
+	return fmt.Sprintf(
+		"Expected the number of entries matching\n\t%s\n%s %s, found %d",
+		renderSequence(cm.filters),
+		expectation,
+		describeValue(cm.count),
+		cm.found,
+	)
+}
@@ -0,0 +1,132 @@
+package glager_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("metrics", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("cache")
+		logger.Info("miss", lager.Data{"tenant": "acme", "size": 1})
+		logger.Info("hit", lager.Data{"tenant": "acme", "size": 1})
+		logger.Info("miss", lager.Data{"tenant": "globex", "size": 2})
+		logger.Info("miss", lager.Data{"tenant": "acme", "size": 1})
+		logger.Error("evict", errors.New("full"))
+		logger.Debug("gc")
+	})
+
+	Describe(".CountByAction", func() {
+		It("counts all entries by action", func() {
+			Expect(CountByAction(logger)).To(Equal(Counts{
+				"cache.miss":  3,
+				"cache.hit":   1,
+				"cache.evict": 1,
+				"cache.gc":    1,
+			}))
+		})
+
+		It("only counts matching entries", func() {
+			Expect(CountByAction(logger, Error(AnyErr), Debug())).To(Equal(Counts{
+				"cache.evict": 1,
+				"cache.gc":    1,
+			}))
+		})
+	})
+
+	Describe(".CountByLevel", func() {
+		It("counts entries by level name", func() {
+			Expect(CountByLevel(logger)).To(Equal(Counts{"info": 4, "error": 1, "debug": 1}))
+		})
+	})
+
+	Describe(".CountByData", func() {
+		It("counts matching entries by data value", func() {
+			counts, err := CountByData(logger, "tenant", Info(Action("cache.miss")))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(counts).To(Equal(Counts{"acme": 2, "globex": 1}))
+			Expect(counts).To(HaveKeyWithValue("acme", 2))
+		})
+
+		It("renders non-string values as JSON", func() {
+			Expect(CountByData(logger, "size")).To(Equal(Counts{"1": 3, "2": 1}))
+		})
+
+		It("returns an error for invalid actuals", func() {
+			_, err := CountByData(42, "tenant")
+			Expect(err).To(MatchError(HavePrefix("CountByData must be passed")))
+		})
+	})
+
+	Describe(".HaveEntryCount", func() {
+		It("counts all entries", func() {
+			Expect(logger).To(HaveEntryCount(6))
+			Expect(logger).To(HaveEntryCount(BeNumerically("<=", 10)))
+			Expect(logger).ToNot(HaveEntryCount(5))
+		})
+
+		It("only counts matching entries", func() {
+			Expect(logger).To(HaveEntryCount(3, Info(Action("cache.miss"))))
+			Expect(logger).To(HaveEntryCount(2, Error(AnyErr), Debug()))
+		})
+
+		It("reports the number of entries found", func() {
+			matcher := HaveEntryCount(BeNumerically("<", 3), Info(Action("cache.miss")))
+			Expect(matcher.Match(logger)).To(BeFalse())
+
+			message := matcher.FailureMessage(logger)
+			Expect(message).To(ContainSubstring(`Info(Message("cache.miss"))`))
+			Expect(message).To(ContainSubstring("found 3"))
+		})
+
+		It("returns an error for invalid counts", func() {
+			_, err := HaveEntryCount("3").Match(logger)
+			Expect(err).To(MatchError(HavePrefix("HaveEntryCount must be passed an int or a matcher as count.")))
+		})
+	})
+
+	Describe(".HaveAtMost", func() {
+		It("matches if there are at most n matching entries", func() {
+			Expect(logger).To(HaveAtMost(3, Info(Action("cache.miss"))))
+			Expect(logger).To(HaveAtMost(4, Info(Action("cache.miss"))))
+			Expect(logger).ToNot(HaveAtMost(2, Info(Action("cache.miss"))))
+			Expect(logger).To(HaveAtMost(6))
+		})
+
+		It("reports the budget and the number of entries found", func() {
+			matcher := HaveAtMost(2, Info(Action("cache.miss")))
+			Expect(matcher.Match(logger)).To(BeFalse())
+
+			message := matcher.FailureMessage(logger)
+			Expect(message).To(ContainSubstring("Expected the number of entries matching\n\t0: Info(Message(\"cache.miss\"))\nto be at most 2, found 3"))
+		})
+	})
+
+	Describe(".HaveAtLeast", func() {
+		It("matches if there are at least n matching entries", func() {
+			Expect(logger).To(HaveAtLeast(1, Error(AnyErr)))
+			Expect(logger).To(HaveAtLeast(3, Info(Action("cache.miss"))))
+			Expect(logger).ToNot(HaveAtLeast(4, Info(Action("cache.miss"))))
+		})
+
+		It("reports the expected minimum", func() {
+			matcher := HaveAtLeast(7)
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(Equal("Expected the number of entries to be at least 7, found 6"))
+			Expect(matcher.NegatedFailureMessage(logger)).To(Equal("Expected the number of entries not to be at least 7, found 6"))
+		})
+
+		It("returns an error for invalid actuals", func() {
+			_, err := HaveAtLeast(1).Match(42)
+			Expect(err).To(MatchError(ContainSubstring("HaveAtLeast must be passed")))
+		})
+	})
+})
@@ -0,0 +1,230 @@
+package glager
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/onsi/gomega/format"
+)
+
+// TestReporter is used by the MockLogger to report failures. It is satisfied
+// by *testing.T as well as by ginkgo.GinkgoT().
+type TestReporter interface {
+	Errorf(format string, args ...interface{})
+}
+
+// MockLogger is a lager.Logger that is primed with the entries it expects to
+// be logged. Every call that does not match the next expected entry is
+// reported as a failure right away, expectations that have not been met are
+// reported when calling Finish.
+//
+// This is the inverse workflow of matching a log buffer after the fact and is
+// best suited for strict unit tests.
+type MockLogger struct {
+	component string
+	task      string
+	sessionID string
+	data      lager.Data
+	nextID    *uint32
+	state     *mockState
+}
+
+var _ lager.Logger = &MockLogger{}
+
+type mockState struct {
+	sync.Mutex
+	reporter TestReporter
+	expected []logEntry
+	sinks    []lager.Sink
+}
+
+// NewMockLogger returns a new MockLogger for the given component that reports
+// failures to the given TestReporter.
+func NewMockLogger(t TestReporter, component string) *MockLogger {
+	return &MockLogger{
+		component: component,
+		task:      component,
+		data:      lager.Data{},
+		nextID:    new(uint32),
+		state:     &mockState{reporter: t},
+	}
+}
+
+// Expect primes the logger with the given entries. Calls to the logger, and to
+// any of its sessions, have to match the expected entries in the specified
+// order.
+//
+// Example:
+//
+//	logger := NewMockLogger(GinkgoT(), "test")
+//	logger.Expect(
+//	  Info(Action("test.myFunc"), Data("event", "start")),
+//	  Error(AnyErr, Action("test.myFunc")),
+//	)
+//	defer logger.Finish()
+//
+//	myFunc(logger)
+func (l *MockLogger) Expect(expected ...RequiredEntry) *MockLogger {
+	l.state.Lock()
+	defer l.state.Unlock()
+
+	l.state.expected = append(l.state.expected, expandRepetitions(sequenceOf(expected))...)
+	return l
+}
+
+// Finish reports all expected entries that have not been logged.
+func (l *MockLogger) Finish() {
+	l.state.Lock()
+	defer l.state.Unlock()
+
+	if len(l.state.expected) > 0 {
+		l.state.reporter.Errorf(
+			"Expected log entries have not been logged:\n%s",
+			format.Object(l.state.expected, 1),
+		)
+	}
+}
+
+// RegisterSink implements lager.Logger. Logged entries are passed on to all
+// registered sinks, regardless of whether they were expected or not.
+func (l *MockLogger) RegisterSink(sink lager.Sink) {
+	l.state.Lock()
+	defer l.state.Unlock()
+
+	l.state.sinks = append(l.state.sinks, sink)
+}
+
+// Session implements lager.Logger.
+func (l *MockLogger) Session(task string, data ...lager.Data) lager.Logger {
+	sid := atomic.AddUint32(l.nextID, 1)
+
+	sessionID := fmt.Sprintf("%d", sid)
+	if l.sessionID != "" {
+		sessionID = fmt.Sprintf("%s.%d", l.sessionID, sid)
+	}
+
+	return &MockLogger{
+		component: l.component,
+		task:      fmt.Sprintf("%s.%s", l.task, task),
+		sessionID: sessionID,
+		data:      l.baseData(data...),
+		nextID:    new(uint32),
+		state:     l.state,
+	}
+}
+
+// SessionName implements lager.Logger.
+func (l *MockLogger) SessionName() string {
+	return l.task
+}
+
+// WithData implements lager.Logger.
+func (l *MockLogger) WithData(data lager.Data) lager.Logger {
+	return &MockLogger{
+		component: l.component,
+		task:      l.task,
+		sessionID: l.sessionID,
+		data:      l.baseData(data),
+		nextID:    l.nextID,
+		state:     l.state,
+	}
+}
+
+// Debug implements lager.Logger.
+func (l *MockLogger) Debug(action string, data ...lager.Data) {
+	l.log(lager.DEBUG, action, nil, data...)
+}
+
+// Info implements lager.Logger.
+func (l *MockLogger) Info(action string, data ...lager.Data) {
+	l.log(lager.INFO, action, nil, data...)
+}
+
+// Error implements lager.Logger.
+func (l *MockLogger) Error(action string, err error, data ...lager.Data) {
+	l.log(lager.ERROR, action, err, data...)
+}
+
+// Fatal implements lager.Logger. Just like lager, it panics after logging.
+func (l *MockLogger) Fatal(action string, err error, data ...lager.Data) {
+	l.log(lager.FATAL, action, err, data...)
+	panic(err)
+}
+
+func (l *MockLogger) log(level lager.LogLevel, action string, err error, data ...lager.Data) {
+	logData := l.baseData(data...)
+	if err != nil {
+		logData["error"] = err.Error()
+	}
+
+	log := lager.LogFormat{
+		Source:   l.component,
+		Message:  fmt.Sprintf("%s.%s", l.task, action),
+		LogLevel: level,
+		Data:     logData,
+		Error:    err,
+	}
+
+	l.state.Lock()
+	defer l.state.Unlock()
+
+	for _, sink := range l.state.sinks {
+		sink.Log(log)
+	}
+
+	// round-trip the entry through JSON to match it just like a logged entry
+	var actual LogEntry
+	if err := json.Unmarshal(log.ToJSON(), &actual); err != nil {
+		l.state.reporter.Errorf("Failed to decode log entry: %s", err)
+		return
+	}
+	actual.err = err
+
+	if len(l.state.expected) == 0 {
+		l.state.reporter.Errorf("Unexpected log entry:\n%s", format.IndentString(actual.String(), 1))
+		return
+	}
+
+	expected := l.state.expected[0]
+
+	matches, matchErr := actual.contains(expected)
+	if matchErr != nil {
+		l.state.reporter.Errorf("Failed to match log entry: %s", matchErr)
+		return
+	}
+
+	if !matches {
+		l.state.reporter.Errorf(
+			"Unexpected log entry:\n%s\nExpected:\n%s",
+			format.IndentString(actual.String(), 1),
+			format.Object(expected, 1),
+		)
+		return
+	}
+
+	expected.capture(actual)
+	l.state.expected = l.state.expected[1:]
+}
+
+func (l *MockLogger) baseData(data ...lager.Data) lager.Data {
+	result := lager.Data{}
+
+	for k, v := range l.data {
+		result[k] = v
+	}
+
+	for _, d := range data {
+		for k, v := range d {
+			result[k] = v
+		}
+	}
+
+	if l.sessionID != "" {
+		result["session"] = l.sessionID
+	}
+
+	return result
+}
@@ -0,0 +1,130 @@
+package glager_test
+
+import (
+	"errors"
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+type fakeReporter struct {
+	failures []string
+}
+
+func (r *fakeReporter) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+var _ = Describe("MockLogger", func() {
+	var (
+		reporter *fakeReporter
+		logger   *MockLogger
+	)
+
+	BeforeEach(func() {
+		reporter = &fakeReporter{}
+		logger = NewMockLogger(reporter, "test")
+	})
+
+	Context("when all expected entries are logged", func() {
+		It("does not report any failures", func() {
+			logger.Expect(
+				Info(Action("test.start"), Data("event", "start")),
+				Error(errors.New("some-error"), Action("test.failed")),
+			)
+
+			logger.Info("start", lager.Data{"event": "start", "more": "stuff"})
+			logger.Error("failed", errors.New("some-error"))
+			logger.Finish()
+
+			Expect(reporter.failures).To(BeEmpty())
+		})
+	})
+
+	Context("when an expected entry is not logged", func() {
+		It("reports a failure on Finish", func() {
+			logger.Expect(Info(Action("test.start")), Info(Action("test.done")))
+
+			logger.Info("start")
+			Expect(reporter.failures).To(BeEmpty())
+
+			logger.Finish()
+			Expect(reporter.failures).To(ConsistOf(ContainSubstring("have not been logged")))
+			Expect(reporter.failures[0]).To(ContainSubstring("test.done"))
+		})
+	})
+
+	Context("when an unexpected entry is logged", func() {
+		It("reports a failure right away", func() {
+			logger.Info("start")
+			Expect(reporter.failures).To(ConsistOf(ContainSubstring("Unexpected log entry")))
+		})
+	})
+
+	Context("when entries are logged out of order", func() {
+		It("reports a failure", func() {
+			logger.Expect(Info(Action("test.start")), Debug(Action("test.done")))
+
+			logger.Debug("done")
+			Expect(reporter.failures).To(ConsistOf(ContainSubstring("Unexpected log entry")))
+		})
+	})
+
+	Context("when using sessions", func() {
+		It("matches session entries like lager does", func() {
+			logger.Expect(
+				Info(Action("test.request.handle"), Data("session", "1", "id", 42)),
+				Info(Action("test.request.nested.handle"), Data("session", "1.1", "id", 42)),
+			)
+
+			session := logger.Session("request", lager.Data{"id": 42})
+			session.Info("handle")
+			session.Session("nested").Info("handle")
+			logger.Finish()
+
+			Expect(reporter.failures).To(BeEmpty())
+			Expect(session.SessionName()).To(Equal("test.request"))
+		})
+	})
+
+	Context("when using WithData", func() {
+		It("adds the data to all entries", func() {
+			logger.Expect(Debug(Data("worker", "a", "n", 1)))
+
+			logger.WithData(lager.Data{"worker": "a"}).Debug("work", lager.Data{"n": 1})
+			logger.Finish()
+
+			Expect(reporter.failures).To(BeEmpty())
+		})
+	})
+
+	Context("when logging a fatal entry", func() {
+		It("panics after matching the entry", func() {
+			err := errors.New("boom")
+			logger.Expect(Fatal(err, Action("test.crash")))
+
+			Expect(func() { logger.Fatal("crash", err) }).To(Panic())
+			logger.Finish()
+
+			Expect(reporter.failures).To(BeEmpty())
+		})
+	})
+
+	Context("when a sink is registered", func() {
+		It("passes entries on to the sink", func() {
+			buffer := gbytes.NewBuffer()
+			logger.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+
+			logger.Expect(Info())
+			logger.Info("start")
+
+			Expect(buffer).To(ContainSequence(Info(Action("test.start"))))
+		})
+	})
+})
@@ -0,0 +1,86 @@
+package glager
+
+import (
+	"fmt"
+	"strings"
+)
+
+// subMap is an expected nested map that is matched partially, i.e. the actual
+// map may contain additional keys.
+type subMap map[string]interface{}
+
+// SubMap specifies a nested map that is matched partially. Unlike a plain map,
+// the actual map may contain keys that are not specified. Arguments are
+// specified as an alternating sequence of keys (string) and values
+// (interface{}), just like for Data. Values can be matchers or sub maps
+// themselves.
+//
+// Example:
+//
+//	Expect(logger).To(ContainSequence(
+//	  Info(Data("request", SubMap("method", "GET"))),
+//	))
+func SubMap(kv ...interface{}) subMap {
+	if len(kv)%2 == 1 {
+		kv = append(kv, "")
+	}
+
+	sub := subMap{}
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			err := fmt.Errorf("Invalid type for sub map key. Want string. Got %T:%v.", kv[i], kv[i])
+			panic(err)
+		}
+		sub[key] = kv[i+1]
+	}
+	return sub
+}
+
+// DataAt specifies a nested data value by its dot-separated path, e.g.
+// "request.method". It is a shorthand for nesting sub maps, i.e.
+// DataAt("request.method", "GET") is equivalent to
+// Data("request", SubMap("method", "GET")). Several paths below the same key
+// can be combined.
+func DataAt(path string, value interface{}) Option {
+	keys := strings.Split(path, ".")
+	for _, key := range keys {
+		if key == "" {
+			panic(fmt.Errorf("Invalid data path %q. Keys must not be empty.", path))
+		}
+	}
+
+	return func(e *logEntry) {
+		if len(keys) == 1 {
+			e.Data[keys[0]] = value
+			return
+		}
+
+		parent, ok := e.Data[keys[0]].(subMap)
+		if !ok {
+			parent = subMap{}
+			e.Data[keys[0]] = parent
+		}
+
+		for _, key := range keys[1 : len(keys)-1] {
+			child, ok := parent[key].(subMap)
+			if !ok {
+				child = subMap{}
+				parent[key] = child
+			}
+			parent = child
+		}
+
+		parent[keys[len(keys)-1]] = value
+	}
+}
+
+// GomegaString renders the sub map the way it has been constructed, e.g.
+// SubMap("method", "GET").
+func (sub subMap) GomegaString() string {
+	args := []string{}
+	for _, key := range sortedKeys(sub) {
+		args = append(args, fmt.Sprintf("%q, %s", key, describeValue(sub[key])))
+	}
+	return fmt.Sprintf("SubMap(%s)", strings.Join(args, ", "))
+}
@@ -0,0 +1,69 @@
+package glager_test
+
+import (
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("nested data", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		logger.Info("request", lager.Data{
+			"request": map[string]interface{}{
+				"method": "GET",
+				"path":   "/v1",
+				"client": map[string]interface{}{"ip": "10.0.0.1", "agent": "curl"},
+			},
+		})
+	})
+
+	Describe("SubMap", func() {
+		It("matches nested maps partially", func() {
+			Expect(logger).To(ContainSequence(Info(Data("request", SubMap("method", "GET")))))
+			Expect(logger).To(ContainSequence(Info(Data("request", SubMap(
+				"path", HavePrefix("/v"),
+				"client", SubMap("ip", BeAnIP()),
+			)))))
+		})
+
+		It("does not match missing or different values", func() {
+			Expect(logger).ToNot(ContainSequence(Info(Data("request", SubMap("method", "POST")))))
+			Expect(logger).ToNot(ContainSequence(Info(Data("request", SubMap("body", "")))))
+		})
+
+		It("reports the path of the first difference", func() {
+			matcher := ContainSequence(Info(Data("request", SubMap("client", SubMap("agent", "wget")))))
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring(`data.request.client.agent: expected "wget", got "curl"`))
+		})
+
+		It("is rendered the way it has been constructed", func() {
+			entry := Info(Data("request", SubMap("method", "GET")))
+			Expect(entry.GomegaString()).To(Equal(`Info(Data("request", SubMap("method", "GET")))`))
+		})
+
+		It("panics for non-string keys", func() {
+			Expect(func() { SubMap(1, "GET") }).To(Panic())
+		})
+	})
+
+	Describe("DataAt", func() {
+		It("matches nested values by their path", func() {
+			Expect(logger).To(ContainSequence(Info(
+				DataAt("request.method", "GET"),
+				DataAt("request.client.agent", "curl"),
+			)))
+			Expect(logger).ToNot(ContainSequence(Info(DataAt("request.client.agent", "wget"))))
+		})
+
+		It("panics for empty keys", func() {
+			Expect(func() { DataAt("request..method", "GET") }).To(Panic())
+		})
+	})
+})
@@ -0,0 +1,81 @@
+package glager
+
+import (
+	"fmt"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+type onlyMatcher struct {
+	expected  []logEntry
+	offending int
+	entry     LogEntry
+}
+
+// ContainOnly checks that every entry of the log matches at least one of the
+// specified entries. An empty log matches as well. Use it with Consistently to
+// verify that a code path never logs anything else, e.g. nothing above INFO.
+// The failure message shows the first entry that does not match.
+//
+// Example:
+//
+//	Consistently(buffer).Should(ContainOnly(Debug(), Info()))
+func ContainOnly(expected ...UnorderedEntry) types.GomegaMatcher {
+	return &onlyMatcher{
+		expected: sequenceOf(expected),
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (om *onlyMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("ContainOnly", actual)
+	if err != nil {
+		return false, err
+	}
+
+	om.offending = -1
+
+	for i, entry := range entries {
+		matched := false
+		for _, expected := range om.expected {
+			if matched, err = entry.contains(expected); err != nil {
+				return false, err
+			}
+
+			if matched {
+				break
+			}
+		}
+
+		if !matched {
+			om.offending, om.entry = i, entry
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (om *onlyMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected log to contain only entries matching\n\t%s\nentry %d does not match any of them\n%s",
+		renderSequence(om.expected),
+		om.offending,
+		format.IndentString(om.entry.String(), 1),
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (om *onlyMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected log to contain entries not matching any of\n\t%s",
+		renderSequence(om.expected),
+	)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (om *onlyMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
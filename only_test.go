@@ -0,0 +1,48 @@
+package glager_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".ContainOnly", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		logger.Debug("lookup")
+		logger.Info("hit")
+	})
+
+	It("matches a log whose entries all match one of the given entries", func() {
+		Expect(logger).To(ContainOnly(Debug(), Info()))
+		Expect(logger).To(ContainOnly(Debug(Action("test.lookup")), Info(Action("test.hit"))))
+	})
+
+	It("matches an empty log", func() {
+		Expect(NewLogger("test")).To(ContainOnly(Info()))
+	})
+
+	It("can be used with Consistently", func() {
+		Consistently(logger, 50*time.Millisecond).Should(ContainOnly(Debug(), Info()))
+	})
+
+	It("reports the first entry that does not match", func() {
+		logger.Error("miss", errors.New("boom"))
+		logger.Error("miss", errors.New("again"))
+
+		matcher := ContainOnly(Debug(), Info())
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		message := matcher.FailureMessage(logger)
+		Expect(message).To(ContainSubstring("to contain only entries matching\n\t0: Debug()\n\t1: Info()"))
+		Expect(message).To(ContainSubstring("entry 2 does not match any of them"))
+		Expect(message).To(ContainSubstring("boom"))
+		Expect(message).ToNot(ContainSubstring("again"))
+	})
+})
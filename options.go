@@ -0,0 +1,558 @@
+package glager
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/onsi/gomega/types"
+)
+
+// NoData specifies that a given log entry must not contain any data besides
+// the keys lager adds implicitly, i.e. "session", "error", and "trace". Use it
+// to make sure a log entry does not accidentally serialize a large payload.
+func NoData() Option {
+	return withCheck("no data", func(actual LogEntry) (bool, error) {
+		for key := range actual.Data {
+			if !implicitDataKeys[key] {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+var implicitDataKeys = map[string]bool{
+	"session": true,
+	"error":   true,
+	"trace":   true,
+}
+
+// ExactData specifies the data logged by a given log entry just like Data
+// does, but additionally requires the entry not to contain any other data keys
+// besides the keys lager adds implicitly, i.e. "session", "error", and
+// "trace".
+//
+// Example:
+//
+//	Info(Action("api.request"), ExactData("method", "GET", "path", "/v2/apps"))
+func ExactData(kv ...interface{}) Option {
+	data := Data(kv...)
+	expected := Entry(lager.INFO, data).Data
+
+	return Options(data, withCheck(fmt.Sprintf("no data keys but %q", sortedKeys(expected)), func(actual LogEntry) (bool, error) {
+		for key := range actual.Data {
+			if _, ok := expected[key]; !ok && !implicitDataKeys[key] {
+				return false, nil
+			}
+		}
+		return true, nil
+	}))
+}
+
+// DataKey specifies that a given log entry must contain the given data
+// keys, regardless of their values.
+func DataKey(keys ...string) Option {
+	return withCheck(fmt.Sprintf("data keys %q present", keys), func(actual LogEntry) (bool, error) {
+		for _, key := range keys {
+			if _, found := actual.Data[key]; !found {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// DataAbsent specifies that a given log entry must not contain any of the given
+// data keys. Use it to make sure sensitive data, e.g. a password or a token,
+// is not logged.
+//
+// Example:
+//
+//	Info(Action("api.login"), DataAbsent("password", "token"))
+func DataAbsent(keys ...string) Option {
+	return withCheck(fmt.Sprintf("data keys %q absent", keys), func(actual LogEntry) (bool, error) {
+		for _, key := range keys {
+			if _, found := actual.Data[key]; found {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// DataNot specifies that a given log entry must contain the given data key
+// with any value but the given one. Unlike DataAbsent, it does not match
+// entries missing the key. Values are compared just like the ones of Data,
+// i.e. the value can be a Gomega matcher, too.
+//
+// Example:
+//
+//	Info(Action("api.job-completed"), DataNot("status", "failed"))
+func DataNot(key string, value interface{}) Option {
+	description := fmt.Sprintf("data %q not %s", key, describeValue(value))
+
+	return withCheck(description, func(actual LogEntry) (bool, error) {
+		actualVal, found := actual.Data[key]
+		if !found {
+			return false, nil
+		}
+
+		mismatch, err := deepMismatch("data."+key, value, actualVal)
+		return mismatch != "", err
+	})
+}
+
+// IgnoringData specifies data keys of a given log entry that are disregarded
+// when matching it, e.g. volatile durations or goroutine IDs. The keys are
+// removed from both the expected and the actual data, i.e. they neither break
+// ExactData nor show up in the data mismatches of failure messages.
+//
+// Example:
+//
+//	Info(Action("api.request"), ExactData("path", "/v2/apps"), IgnoringData("duration", "ts"))
+func IgnoringData(keys ...string) Option {
+	return func(e *logEntry) {
+		if e.ignoredData == nil {
+			e.ignoredData = map[string]bool{}
+		}
+
+		for _, key := range keys {
+			e.ignoredData[key] = true
+		}
+	}
+}
+
+// ignoreData removes the data keys ignored by the expected entry from the data
+// of both entries.
+func ignoreData(expected logEntry, actual LogEntry) (logEntry, LogEntry) {
+	if len(expected.ignoredData) == 0 {
+		return expected, actual
+	}
+
+	without := func(data lager.Data) lager.Data {
+		result := lager.Data{}
+		for key, value := range data {
+			if !expected.ignoredData[key] {
+				result[key] = value
+			}
+		}
+		return result
+	}
+
+	expected.Data, actual.Data = without(expected.Data), without(actual.Data)
+	return expected, actual
+}
+
+// DataMatching specifies that a given log entry must contain a string value for
+// the given data key that matches the given regular expression. This comes in
+// handy for dynamically generated values like IDs. The function panics if the
+// regular expression cannot be compiled.
+func DataMatching(key, pattern string) Option {
+	re := regexp.MustCompile(pattern)
+
+	description := fmt.Sprintf("data %q matching %q", key, pattern)
+
+	return withCheck(description, func(actual LogEntry) (bool, error) {
+		value, ok := actual.Data[key].(string)
+		return ok && re.MatchString(value), nil
+	})
+}
+
+// MessageMatching specifies that the message of a given log entry must match
+// the given pattern instead of being equal to a given string. The pattern is
+// either a regular expression or a Gomega matcher. Use it for messages that
+// embed dynamic segments, e.g. handler IDs. The function panics if the regular
+// expression cannot be compiled or the pattern has any other type.
+//
+// Example:
+//
+//	Info(MessageMatching(`^server\.request-\d+\.handle$`))
+//	Info(MessageMatching(HaveSuffix(".handle")))
+func MessageMatching(pattern interface{}) Option {
+	return matching("MessageMatching", "message", pattern, func(actual LogEntry) (string, bool) {
+		return actual.Message, true
+	})
+}
+
+// matching returns a check that the string value returned by the given
+// function matches the given regular expression or Gomega matcher.
+func matching(option, subject string, pattern interface{}, value func(actual LogEntry) (string, bool)) Option {
+	switch p := pattern.(type) {
+	case string:
+		re := regexp.MustCompile(p)
+		return withCheck(fmt.Sprintf("%s matching %q", subject, p), func(actual LogEntry) (bool, error) {
+			v, ok := value(actual)
+			return ok && re.MatchString(v), nil
+		})
+	case types.GomegaMatcher:
+		return withCheck(fmt.Sprintf("%s matching <%T>", subject, p), func(actual LogEntry) (bool, error) {
+			v, ok := value(actual)
+			if !ok {
+				return false, nil
+			}
+			return p.Match(v)
+		})
+	default:
+		panic(fmt.Errorf("%s must be passed a regular expression or a matcher. Got %T.", option, pattern))
+	}
+}
+
+// ActionMatching is an alias for MessageMatching, lager uses the term action
+// alternatively to message.
+func ActionMatching(pattern interface{}) Option {
+	return MessageMatching(pattern)
+}
+
+// SourcePrefix specifies that the source of a given log entry must be the
+// given source or one of its descendants in the dotted source hierarchy, e.g.
+// SourcePrefix("api.server") matches "api.server" and "api.server.request" but
+// not "api.serverless".
+func SourcePrefix(prefix string) Option {
+	return withCheck(fmt.Sprintf("source under %q", prefix), func(actual LogEntry) (bool, error) {
+		return actual.Source == prefix || strings.HasPrefix(actual.Source, prefix+"."), nil
+	})
+}
+
+// SourceMatching specifies that the source of a given log entry must match the
+// given pattern instead of being equal to a given string. The pattern is
+// either a regular expression or a Gomega matcher. The function panics if the
+// regular expression cannot be compiled or the pattern has any other type.
+//
+// Example:
+//
+//	Info(SourceMatching(`^api\.server\.request-\d+$`))
+func SourceMatching(pattern interface{}) Option {
+	return matching("SourceMatching", "source", pattern, func(actual LogEntry) (string, bool) {
+		return actual.Source, true
+	})
+}
+
+// TopLevelField specifies a top-level key of a given log entry, besides the
+// keys of lager's format, and its value, e.g. a "trace_id" added by another log
+// schema. Unlike the Data option, the key is not looked up in the data of the
+// entry. Values are matched just like the values of the Data option, i.e. they
+// can be Gomega matchers. The function panics for keys of lager's format, use
+// the corresponding options instead.
+//
+// Example:
+//
+//	Info(Action("api.request"), TopLevelField("trace_id", "4bf92f3577b34da6"))
+func TopLevelField(key string, value interface{}) Option {
+	if lagerKeys[key] {
+		panic(fmt.Errorf("TopLevelField must be passed a key that is not part of lager's format. Got %q.", key))
+	}
+
+	return withCheck(fmt.Sprintf("field %q: %s", key, describeValue(value)), func(actual LogEntry) (bool, error) {
+		actualValue, found := actual.Fields[key]
+		if !found {
+			return false, nil
+		}
+
+		mismatch, err := deepMismatch(key, value, actualValue)
+		return mismatch == "", err
+	})
+}
+
+// ErrorMatching specifies that the error logged by a given log entry must match
+// the given pattern, which is either a regular expression or a Gomega matcher.
+// Use it together with AnyErr for errors that embed dynamic details.
+//
+// Example:
+//
+//	Error(AnyErr, ErrorMatching(`^dial tcp .*: connection refused$`))
+func ErrorMatching(pattern interface{}) Option {
+	return matching("ErrorMatching", "error", pattern, func(actual LogEntry) (string, bool) {
+		err, ok := actual.Data["error"].(string)
+		return err, ok
+	})
+}
+
+// Trace specifies that the stack trace logged by a given log entry must match
+// the given pattern, which is either a regular expression or a Gomega matcher.
+// lager logs the stack trace under the "trace" data key, e.g. for Fatal
+// entries. Use it to verify the call site an entry has been logged from.
+//
+// Example:
+//
+//	Fatal(AnyErr, Trace(ContainSubstring("server.(*Server).Shutdown")))
+func Trace(pattern interface{}) Option {
+	return matching("Trace", "trace", pattern, func(actual LogEntry) (string, bool) {
+		trace, ok := actual.Data["trace"].(string)
+		return trace, ok
+	})
+}
+
+// ExitStatusKey is the data key under which ExitStatus expects the exit status
+// of a process.
+const ExitStatusKey = "exit-status"
+
+// PanicValueKey is the data key under which PanicValueMatching expects the value
+// passed to panic.
+const PanicValueKey = "panic"
+
+// ExitStatus specifies the exit status logged by a given log entry, usually a
+// Fatal entry that records the shutdown of a process. The status is expected
+// under the data key ExitStatusKey, use ExitStatusUnder for wrappers around
+// lager that log it under a different key.
+//
+// Example:
+//
+//	Fatal(AnyErr, Action("server.crashed"), ExitStatus(1))
+func ExitStatus(status int) Option {
+	return ExitStatusUnder(ExitStatusKey, status)
+}
+
+// ExitStatusUnder is like ExitStatus, but expects the exit status under the
+// given data key.
+//
+// Example:
+//
+//	Fatal(AnyErr, ExitStatusUnder("exit_code", 1))
+func ExitStatusUnder(key string, status int) Option {
+	return withCheck(fmt.Sprintf("exit status %d", status), func(actual LogEntry) (bool, error) {
+		mismatch, err := deepMismatch(key, status, actual.Data[key])
+		return mismatch == "", err
+	})
+}
+
+// PanicValueMatching specifies that the panic value logged by a given log
+// entry must match the given pattern, which is either a regular expression or
+// a Gomega matcher. The value is expected under the data key PanicValueKey,
+// use PanicValueUnder for wrappers around lager that log it under a different
+// key. Values other than strings are matched in their JSON representation.
+//
+// Example:
+//
+//	Fatal(AnyErr, PanicValueMatching(`index out of range`))
+func PanicValueMatching(pattern interface{}) Option {
+	return panicValue("PanicValueMatching", PanicValueKey, pattern)
+}
+
+// PanicValueUnder is like PanicValueMatching, but expects the panic value under
+// the given data key.
+//
+// Example:
+//
+//	Fatal(AnyErr, PanicValueUnder("recovered", `index out of range`))
+func PanicValueUnder(key string, pattern interface{}) Option {
+	return panicValue("PanicValueUnder", key, pattern)
+}
+
+func panicValue(name, key string, pattern interface{}) Option {
+	return matching(name, "panic value", pattern, func(actual LogEntry) (string, bool) {
+		value, found := actual.Data[key]
+		if !found {
+			return "", false
+		}
+
+		if s, ok := value.(string); ok {
+			return s, true
+		}
+
+		encoded, err := canonicalJSON(value)
+		return string(encoded), err == nil
+	})
+}
+
+// ErrorContaining specifies that the message of the error logged by a given
+// log entry must contain the message of the given error, e.g. a sentinel
+// error. Since only the message of an error is logged, errors.Is cannot be
+// applied to it. Message containment holds for errors that have been wrapped
+// using fmt.Errorf("%w"), but also for errors that merely repeat the message.
+// The function panics if the given error is nil.
+//
+// Example:
+//
+//	Error(AnyErr, ErrorContaining(io.ErrUnexpectedEOF))
+func ErrorContaining(err error) Option {
+	if err == nil {
+		panic(fmt.Errorf("ErrorContaining must be passed an error. Got nil."))
+	}
+
+	message := err.Error()
+	return withCheck(fmt.Sprintf("error containing %q", message), func(actual LogEntry) (bool, error) {
+		logged, ok := actual.Data["error"].(string)
+		return ok && strings.Contains(logged, message), nil
+	})
+}
+
+// ErrorOfType specifies that the error logged by a given log entry must be of
+// the type the given target points to, or wrap an error of that type, just
+// like errors.As reports. The target is a pointer to an interface type or to a
+// type implementing error. A pointer to a struct, whose pointer type implements
+// error, is accepted as well. Only the message of an error is logged, its type
+// is only known for entries logged to a MockLogger. Matching other logs fails
+// with an error for entries that have logged an error.
+//
+// Example:
+//
+//	logger.Expect(Error(AnyErr, ErrorOfType(&fs.PathError{})))
+func ErrorOfType(target interface{}) Option {
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+
+	targetType := reflect.TypeOf(target)
+	if targetType == nil || targetType.Kind() != reflect.Ptr {
+		panic(fmt.Errorf("ErrorOfType must be passed a pointer. Got %T.", target))
+	}
+
+	// errors.As expects a pointer to the type to look for, a pointer to a
+	// struct whose pointer type implements error looks for the pointer type
+	if elem := targetType.Elem(); elem.Kind() != reflect.Interface && !elem.Implements(errorType) {
+		if !targetType.Implements(errorType) {
+			panic(fmt.Errorf("ErrorOfType must be passed a pointer to an interface or to a type implementing error. Got %T.", target))
+		}
+		targetType = reflect.PointerTo(targetType)
+	}
+
+	errorOfType := targetType.Elem()
+	return withCheck(fmt.Sprintf("error of type %s", errorOfType), func(actual LogEntry) (bool, error) {
+		if actual.err == nil {
+			if _, logged := actual.Data["error"]; logged {
+				return false, fmt.Errorf("ErrorOfType can only be applied to entries logged to a MockLogger, the type of logged errors is unknown.")
+			}
+			return false, nil
+		}
+
+		return errors.As(actual.err, reflect.New(errorOfType).Interface()), nil
+	})
+}
+
+// ErrorData specifies structured context of the error logged by a given log
+// entry and its value. If the error has been logged as a JSON object, e.g. by
+// a library that encodes its errors, the key is looked up in that object
+// first. Otherwise, or if the object does not contain the key, it is looked up
+// in the data of the entry, where libraries commonly attach context like an
+// "error-code" or a "cause" next to the error. Values are matched just like the
+// values of the Data option.
+//
+// Example:
+//
+//	Error(AnyErr, ErrorData("error-code", 503), ErrorData("cause", ContainSubstring("timeout")))
+func ErrorData(key string, value interface{}) Option {
+	return withCheck(fmt.Sprintf("error data %q: %s", key, describeValue(value)), func(actual LogEntry) (bool, error) {
+		actualValue, found := actual.Data[key]
+		if object, ok := actual.Data["error"].(map[string]interface{}); ok {
+			if v, inObject := object[key]; inObject {
+				actualValue, found = v, true
+			}
+		}
+
+		if !found {
+			return false, nil
+		}
+
+		mismatch, err := deepMismatch(key, value, actualValue)
+		return mismatch == "", err
+	})
+}
+
+// DataSatisfying specifies a predicate that the entire data of a given log
+// entry has to satisfy. Use it for complex invariants that cannot be expressed
+// with the other options. The description is used in failure messages.
+//
+// Example:
+//
+//	Info(DataSatisfying("more successes than failures", func(d map[string]interface{}) bool {
+//	  return d["successes"].(float64) > d["failures"].(float64)
+//	}))
+func DataSatisfying(description string, predicate func(data map[string]interface{}) bool) Option {
+	return withCheck(description, func(actual LogEntry) (bool, error) {
+		return predicate(actual.Data), nil
+	})
+}
+
+// CaptureInto stores the actual log entry that matched a given log entry in the
+// variable the given pointer points to. Entries are only captured once the
+// entire sequence has been matched successfully. Use it for follow-up
+// assertions, e.g. on timestamps or additional data, without parsing the log
+// again.
+//
+// Example:
+//
+//	var start LogEntry
+//	Expect(logger).To(HaveLogged(Info(Action("test.start"), CaptureInto(&start))))
+//	Expect(start.Time()).To(BeTemporally("<", deadline))
+func CaptureInto(entry *LogEntry) Option {
+	return func(e *logEntry) {
+		e.captures = append(e.captures, func(actual LogEntry) {
+			*entry = actual
+		})
+	}
+}
+
+func (entry logEntry) capture(actual LogEntry) {
+	for _, capture := range entry.captures {
+		capture(actual)
+	}
+	entry.captureAny(actual)
+}
+
+// CaptureData stores the value of the given data key of the actual log entry
+// that matched a given log entry in the variable the given pointer points to.
+// The value is converted to type T just like GetData does. Entries only match
+// if they contain the key and its value can be converted. Values are only
+// captured once the entire sequence has been matched successfully. Use it to
+// get hold of generated values, e.g. a request ID, for later use in a test.
+//
+// Example:
+//
+//	var requestID string
+//	Expect(logger).To(HaveLogged(Info(Action("api.request"), CaptureData("request_id", &requestID))))
+//	Expect(logger).To(HaveLogged(Info(Action("db.query"), Data("request_id", requestID))))
+func CaptureData[T any](key string, target *T) Option {
+	check := withCheck(fmt.Sprintf("data %q of type %s", key, reflect.TypeOf(target).Elem()), func(actual LogEntry) (bool, error) {
+		_, err := GetData[T](actual, key)
+		return err == nil, nil
+	})
+
+	return func(e *logEntry) {
+		check(e)
+		e.captures = append(e.captures, func(actual LogEntry) {
+			*target, _ = GetData[T](actual, key)
+		})
+	}
+}
+
+// Check specifies an arbitrary check that a given log entry has to pass. Use
+// it to write custom options that compose with the built-in ones. The
+// description is used in failure messages.
+//
+// Example:
+//
+//	func Tenant(id string) glager.Option {
+//	  return glager.Check("tenant "+id, func(actual glager.LogEntry) (bool, error) {
+//	    tenant, ok := actual.Data["tenant"].(map[string]interface{})
+//	    return ok && tenant["id"] == id, nil
+//	  })
+//	}
+func Check(description string, match func(actual LogEntry) (bool, error)) Option {
+	return withCheck(description, match)
+}
+
+// Options combines the given options into a single one.
+//
+// Example:
+//
+//	func Request(id string) glager.Option {
+//	  return glager.Options(glager.Source("api"), glager.Data("request_id", id))
+//	}
+func Options(options ...Option) Option {
+	return func(e *logEntry) {
+		for _, apply := range options {
+			apply(e)
+		}
+	}
+}
+
+func withCheck(description string, match func(actual LogEntry) (bool, error)) Option {
+	return func(e *logEntry) {
+		e.checks = append(e.checks, entryCheck{
+			description: description,
+			match:       match,
+		})
+	}
+}
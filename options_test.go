@@ -0,0 +1,630 @@
+package glager_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("entry options", func() {
+	var logger lager.Logger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+	})
+
+	Describe(".NoData", func() {
+		It("matches an entry without data", func() {
+			logger.Info("no-data")
+			Expect(logger).To(ContainSequence(Info(NoData())))
+		})
+
+		It("ignores the implicit session, error and trace keys", func() {
+			session := logger.Session("session")
+			session.Error("failed", errors.New("some-error"))
+			Expect(func() { session.Fatal("crashed", errors.New("some-error")) }).To(Panic())
+
+			Expect(logger).To(ContainSequence(
+				Error(AnyErr, NoData()),
+				Fatal(AnyErr, NoData()),
+			))
+		})
+
+		It("does not match an entry with data", func() {
+			logger.Info("data", lager.Data{"payload": "large"})
+			Expect(logger).ToNot(ContainSequence(Info(NoData())))
+		})
+	})
+
+	Describe(".ExactData", func() {
+		BeforeEach(func() {
+			logger.Session("request").Info("handle", lager.Data{"method": "GET", "path": "/v2/apps"})
+		})
+
+		It("matches entries with exactly the given data", func() {
+			Expect(logger).To(ContainSequence(Info(ExactData("method", "GET", "path", "/v2/apps"))))
+			Expect(logger).To(ContainSequence(Info(ExactData("method", "GET", "path", HavePrefix("/v2")))))
+		})
+
+		It("does not match entries with additional data keys", func() {
+			Expect(logger).To(ContainSequence(Info(Data("method", "GET"))))
+			Expect(logger).ToNot(ContainSequence(Info(ExactData("method", "GET"))))
+		})
+
+		It("does not match entries with different values", func() {
+			Expect(logger).ToNot(ContainSequence(Info(ExactData("method", "POST", "path", "/v2/apps"))))
+		})
+
+		It("panics for non-string keys", func() {
+			Expect(func() { ExactData(1, "GET") }).To(Panic())
+		})
+	})
+
+	Describe(".IgnoringData", func() {
+		BeforeEach(func() {
+			logger.Info("request", lager.Data{"path": "/v2/apps", "duration": 0.42, "ts": 17})
+		})
+
+		It("disregards the keys for ExactData", func() {
+			Expect(logger).ToNot(ContainSequence(Info(ExactData("path", "/v2/apps"))))
+			Expect(logger).To(ContainSequence(Info(ExactData("path", "/v2/apps"), IgnoringData("duration", "ts"))))
+			Expect(logger).To(ContainSequence(Info(IgnoringData("duration"), IgnoringData("ts"), ExactData("path", "/v2/apps"))))
+		})
+
+		It("disregards expected values for the keys", func() {
+			Expect(logger).To(ContainSequence(Info(Data("path", "/v2/apps", "duration", 1), IgnoringData("duration"))))
+		})
+
+		It("excludes the keys from data mismatches", func() {
+			matcher := ContainSequence(Info(Data("path", "/v2/users", "duration", 1), IgnoringData("duration")))
+			Expect(matcher.Match(logger)).To(BeFalse())
+
+			message := matcher.FailureMessage(logger)
+			Expect(message).To(ContainSubstring(`IgnoringData("duration")`))
+			Expect(message).To(ContainSubstring(`data.path: expected "/v2/users", got "/v2/apps"`))
+			Expect(message).ToNot(ContainSubstring("data.duration"))
+		})
+	})
+
+	Describe(".DataKey", func() {
+		BeforeEach(func() {
+			logger.Info("request", lager.Data{"request_id": "abc", "user": nil})
+		})
+
+		It("matches entries containing the keys regardless of their values", func() {
+			Expect(logger).To(ContainSequence(Info(DataKey("request_id", "user"))))
+		})
+
+		It("does not match entries missing any of the keys", func() {
+			Expect(logger).ToNot(ContainSequence(Info(DataKey("request_id", "tenant"))))
+		})
+	})
+
+	Describe(".DataAbsent", func() {
+		BeforeEach(func() {
+			logger.Info("login", lager.Data{"user": "admin"})
+		})
+
+		It("matches entries not containing any of the keys", func() {
+			Expect(logger).To(ContainSequence(Info(Action("test.login"), DataAbsent("password", "token"))))
+		})
+
+		It("does not match entries containing any of the keys", func() {
+			logger.Info("login", lager.Data{"user": "admin", "token": "secret"})
+
+			Expect(logger).To(HaveEntryCount(1, Info(Action("test.login"), DataAbsent("password", "token"))))
+		})
+	})
+
+	Describe(".DataNot", func() {
+		BeforeEach(func() {
+			logger.Info("job-completed", lager.Data{"status": "succeeded", "attempts": 2})
+		})
+
+		It("matches entries with any other value", func() {
+			Expect(logger).To(ContainSequence(Info(Action("test.job-completed"), DataNot("status", "failed"))))
+			Expect(logger).To(ContainSequence(Info(DataNot("attempts", 3))))
+		})
+
+		It("does not match entries with the given value", func() {
+			Expect(logger).ToNot(ContainSequence(Info(DataNot("status", "succeeded"))))
+			Expect(logger).ToNot(ContainSequence(Info(DataNot("attempts", 2))))
+		})
+
+		It("does not match entries missing the key", func() {
+			Expect(logger).ToNot(ContainSequence(Info(DataNot("error", "boom"))))
+		})
+
+		It("accepts matchers", func() {
+			Expect(logger).To(ContainSequence(Info(DataNot("status", HavePrefix("fail")))))
+			Expect(logger).ToNot(ContainSequence(Info(DataNot("status", HavePrefix("succ")))))
+		})
+
+		It("is rendered in failure messages", func() {
+			matcher := ContainSequence(Info(DataNot("status", "succeeded")))
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring(`<data "status" not "succeeded">`))
+		})
+	})
+
+	Describe(".DataMatching", func() {
+		BeforeEach(func() {
+			logger.Info("request", lager.Data{"path": "/v2/apps/0e2b-4f1a", "count": 1})
+		})
+
+		It("matches a value matching the regular expression", func() {
+			Expect(logger).To(ContainSequence(
+				Info(DataMatching("path", `^/v2/apps/[a-f0-9-]+$`)),
+			))
+		})
+
+		It("does not match a value not matching the regular expression", func() {
+			Expect(logger).ToNot(ContainSequence(
+				Info(DataMatching("path", `^/v3/`)),
+			))
+		})
+
+		It("does not match a missing key", func() {
+			Expect(logger).ToNot(ContainSequence(
+				Info(DataMatching("missing", `.*`)),
+			))
+		})
+
+		It("does not match a non-string value", func() {
+			Expect(logger).ToNot(ContainSequence(
+				Info(DataMatching("count", `1`)),
+			))
+		})
+
+		It("panics for an invalid regular expression", func() {
+			Expect(func() { DataMatching("path", `(`) }).To(Panic())
+		})
+	})
+
+	Describe(".MessageMatching", func() {
+		BeforeEach(func() {
+			logger.Session("request-42").Info("handle")
+		})
+
+		It("matches a message matching the regular expression", func() {
+			Expect(logger).To(ContainSequence(Info(MessageMatching(`^test\.request-\d+\.handle$`))))
+			Expect(logger).ToNot(ContainSequence(Info(MessageMatching(`^test\.request-\d+\.done$`))))
+		})
+
+		It("matches a message matching the matcher", func() {
+			Expect(logger).To(ContainSequence(Info(ActionMatching(HaveSuffix(".handle")))))
+			Expect(logger).ToNot(ContainSequence(Info(ActionMatching(HavePrefix("server.")))))
+		})
+
+		It("panics for an invalid regular expression", func() {
+			Expect(func() { MessageMatching(`(`) }).To(Panic())
+		})
+
+		It("panics for other types", func() {
+			Expect(func() { MessageMatching(42) }).To(PanicWith(MatchError("MessageMatching must be passed a regular expression or a matcher. Got int.")))
+		})
+	})
+
+	Describe("source hierarchies", func() {
+		BeforeEach(func() {
+			logger = NewLogger("api.server.request-42")
+			logger.Info("handle")
+		})
+
+		It("matches sources under the prefix", func() {
+			Expect(logger).To(ContainSequence(Info(SourcePrefix("api.server"))))
+			Expect(logger).To(ContainSequence(Info(SourcePrefix("api.server.request-42"))))
+			Expect(logger).ToNot(ContainSequence(Info(SourcePrefix("api.serv"))))
+			Expect(logger).ToNot(ContainSequence(Info(SourcePrefix("worker"))))
+		})
+
+		It("matches sources matching the pattern", func() {
+			Expect(logger).To(ContainSequence(Info(SourceMatching(`^api\.server\.request-\d+$`))))
+			Expect(logger).To(ContainSequence(Info(SourceMatching(HavePrefix("api.")))))
+			Expect(logger).ToNot(ContainSequence(Info(SourceMatching(`^worker\.`))))
+		})
+
+		It("panics for other types", func() {
+			Expect(func() { SourceMatching(42) }).To(PanicWith(MatchError("SourceMatching must be passed a regular expression or a matcher. Got int.")))
+		})
+	})
+
+	Describe(".TopLevelField", func() {
+		const log = `{"timestamp":"1","source":"api","message":"api.request","log_level":1,"data":{"path":"/"},"trace_id":"abc","span":{"id":7}}` + "\n"
+
+		It("matches top-level keys besides the ones of lager's format", func() {
+			Expect(strings.NewReader(log)).To(ContainSequence(Info(TopLevelField("trace_id", "abc"), TopLevelField("span", map[string]interface{}{"id": 7}))))
+			Expect(strings.NewReader(log)).To(ContainSequence(Info(TopLevelField("trace_id", HaveLen(3)))))
+			Expect(strings.NewReader(log)).ToNot(ContainSequence(Info(TopLevelField("trace_id", "def"))))
+			Expect(strings.NewReader(log)).ToNot(ContainSequence(Info(TopLevelField("path", "/"))))
+		})
+
+		It("retains the fields of selected entries", func() {
+			entries, err := Select(strings.NewReader(log))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries[0].Fields).To(Equal(map[string]interface{}{"trace_id": "abc", "span": map[string]interface{}{"id": float64(7)}}))
+			Expect(entries).To(ContainSequence(Info(TopLevelField("trace_id", "abc"))))
+		})
+
+		It("includes the field in failure messages", func() {
+			matcher := ContainSequence(Info(TopLevelField("trace_id", "def")))
+			Expect(matcher.Match(strings.NewReader(log))).To(BeFalse())
+			Expect(matcher.FailureMessage(strings.NewReader(log))).To(ContainSubstring(`field "trace_id": "def"`))
+		})
+
+		It("panics for keys of lager's format", func() {
+			Expect(func() { TopLevelField("source", "api") }).To(PanicWith(MatchError(`TopLevelField must be passed a key that is not part of lager's format. Got "source".`)))
+			Expect(func() { TopLevelField("data", "api") }).To(Panic())
+		})
+	})
+
+	Describe(".ErrorData", func() {
+		It("matches context attached next to the error", func() {
+			logger.Error("request", errors.New("unavailable"), lager.Data{"error-code": 503, "cause": "upstream timeout"})
+
+			Expect(logger).To(ContainSequence(Error(errors.New("unavailable"), ErrorData("error-code", 503), ErrorData("cause", ContainSubstring("timeout")))))
+			Expect(logger).ToNot(ContainSequence(Error(AnyErr, ErrorData("error-code", 500))))
+			Expect(logger).ToNot(ContainSequence(Error(AnyErr, ErrorData("retryable", true))))
+		})
+
+		It("matches keys of errors logged as JSON object", func() {
+			log := `{"timestamp":"1","source":"api","message":"api.request","log_level":2,"data":{"error":{"message":"unavailable","code":503,"details":{"region":"eu"}},"cause":"timeout"}}` + "\n"
+
+			Expect(strings.NewReader(log)).To(ContainSequence(Error(AnyErr,
+				ErrorData("message", "unavailable"),
+				ErrorData("code", 503),
+				ErrorData("details", map[string]interface{}{"region": "eu"}),
+				ErrorData("cause", "timeout"),
+			)))
+			Expect(strings.NewReader(log)).ToNot(ContainSequence(Error(AnyErr, ErrorData("code", "503"))))
+		})
+
+		It("includes the context in failure messages", func() {
+			logger.Error("request", errors.New("unavailable"))
+
+			matcher := ContainSequence(Error(AnyErr, ErrorData("error-code", 503)))
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring(`error data "error-code": 503`))
+		})
+	})
+
+	Describe(".ErrorMatching", func() {
+		BeforeEach(func() {
+			logger.Error("dial", errors.New("dial tcp 10.0.0.1:443: connection refused"))
+		})
+
+		It("matches an error matching the regular expression", func() {
+			Expect(logger).To(ContainSequence(Error(AnyErr, ErrorMatching(`^dial tcp .*: connection refused$`))))
+			Expect(logger).ToNot(ContainSequence(Error(AnyErr, ErrorMatching(`timeout`))))
+		})
+
+		It("matches an error matching the matcher", func() {
+			Expect(logger).To(ContainSequence(Error(AnyErr, ErrorMatching(ContainSubstring("refused")))))
+		})
+
+		It("does not match entries without error", func() {
+			logger.Info("no-error")
+			Expect(logger).ToNot(ContainSequence(Info(ErrorMatching(`.*`))))
+		})
+
+		It("panics for other types", func() {
+			Expect(func() { ErrorMatching(42) }).To(PanicWith(MatchError("ErrorMatching must be passed a regular expression or a matcher. Got int.")))
+		})
+	})
+
+	Describe(".Trace", func() {
+		logFatal := func() {
+			defer func() { recover() }()
+			logger.Fatal("crashed", errors.New("boom"))
+		}
+
+		BeforeEach(func() {
+			logFatal()
+		})
+
+		It("matches a stack trace matching the matcher", func() {
+			Expect(logger).To(ContainSequence(Fatal(AnyErr, Trace(ContainSubstring("options_test.go")))))
+			Expect(logger).ToNot(ContainSequence(Fatal(AnyErr, Trace(ContainSubstring("main.go")))))
+		})
+
+		It("matches a stack trace matching the regular expression", func() {
+			Expect(logger).To(ContainSequence(Fatal(AnyErr, Trace(`(?m)^goroutine \d+`))))
+		})
+
+		It("does not match entries without stack trace", func() {
+			logger.Error("failed", errors.New("boom"))
+			Expect(logger).ToNot(ContainSequence(Error(AnyErr, Action("test.failed"), Trace(`.*`))))
+		})
+
+		It("panics for other types", func() {
+			Expect(func() { Trace(42) }).To(PanicWith(MatchError("Trace must be passed a regular expression or a matcher. Got int.")))
+		})
+	})
+
+	Describe("shutdown records", func() {
+		const v3Log = `{"timestamp":"2024-01-01T00:00:00.123456789Z","level":"fatal","source":"app","message":"app.crashed","data":{"error":"boom","exit-status":2,"panic":"runtime error: index out of range [3] with length 2","trace":"goroutine 1 [running]:"}}` + "\n"
+
+		It("matches the fatal entries of lager", func() {
+			func() {
+				defer func() { recover() }()
+				logger.Fatal("crashed", errors.New("boom"), lager.Data{"exit-status": 1, "panic": map[string]interface{}{"code": 7}})
+			}()
+
+			Expect(logger).To(ContainSequence(Fatal(nil)))
+			Expect(logger).To(ContainSequence(Fatal(errors.New("boom"), Action("test.crashed"), ExitStatus(1), PanicValueMatching(`"code":7`))))
+			Expect(logger).ToNot(ContainSequence(Fatal(AnyErr, ExitStatus(2))))
+		})
+
+		It("matches the fatal entries of lager v3", func() {
+			Expect(strings.NewReader(v3Log)).To(ContainSequence(Fatal(nil)))
+			Expect(strings.NewReader(v3Log)).To(ContainSequence(Fatal(errors.New("boom"),
+				Source("app"),
+				Action("app.crashed"),
+				ExitStatus(2),
+				PanicValueMatching(`^runtime error: index out of range`),
+				Trace(HavePrefix("goroutine")),
+			)))
+			Expect(strings.NewReader(v3Log)).ToNot(ContainSequence(Fatal(AnyErr, PanicValueMatching(ContainSubstring("nil pointer")))))
+		})
+
+		It("does not match entries without exit status or panic value", func() {
+			logger.Error("failed", errors.New("boom"))
+			Expect(logger).ToNot(ContainSequence(Error(AnyErr, ExitStatus(0))))
+			Expect(logger).ToNot(ContainSequence(Error(AnyErr, PanicValueMatching(`.*`))))
+		})
+
+		It("matches exit status and panic value under other keys", func() {
+			func() {
+				defer func() { recover() }()
+				logger.Fatal("crashed", errors.New("boom"), lager.Data{"exit_code": 3, "recovered": "nil map"})
+			}()
+
+			Expect(logger).To(ContainSequence(Fatal(AnyErr, ExitStatusUnder("exit_code", 3), PanicValueUnder("recovered", `^nil map$`))))
+			Expect(logger).ToNot(ContainSequence(Fatal(AnyErr, ExitStatus(3))))
+			Expect(logger).ToNot(ContainSequence(Fatal(AnyErr, PanicValueMatching(`nil map`))))
+		})
+
+		It("panics for other types", func() {
+			Expect(func() { PanicValueMatching(42) }).To(PanicWith(MatchError("PanicValueMatching must be passed a regular expression or a matcher. Got int.")))
+			Expect(func() { PanicValueUnder("recovered", 42) }).To(PanicWith(MatchError("PanicValueUnder must be passed a regular expression or a matcher. Got int.")))
+		})
+	})
+
+	Describe(".ErrorContaining", func() {
+		var errNotFound = errors.New("not found")
+
+		It("matches an error containing the message of the given error", func() {
+			logger.Error("lookup", fmt.Errorf("looking up app %s: %w", "42", errNotFound))
+
+			Expect(logger).To(ContainSequence(Error(AnyErr, ErrorContaining(errNotFound))))
+			Expect(logger).ToNot(ContainSequence(Error(AnyErr, ErrorContaining(errors.New("forbidden")))))
+		})
+
+		It("panics for nil", func() {
+			Expect(func() { ErrorContaining(nil) }).To(PanicWith(MatchError("ErrorContaining must be passed an error. Got nil.")))
+		})
+	})
+
+	Describe(".ErrorOfType", func() {
+		var (
+			reporter *fakeReporter
+			mock     *MockLogger
+		)
+
+		BeforeEach(func() {
+			reporter = &fakeReporter{}
+			mock = NewMockLogger(reporter, "test")
+		})
+
+		It("matches an error wrapping an error of the given type", func() {
+			mock.Expect(Error(AnyErr, ErrorOfType(&fs.PathError{})))
+			mock.Error("open", fmt.Errorf("loading config: %w", &fs.PathError{Op: "open", Path: "/etc/app", Err: fs.ErrNotExist}))
+			mock.Finish()
+
+			Expect(reporter.failures).To(BeEmpty())
+		})
+
+		It("accepts a pointer to an interface type", func() {
+			var target interface{ Timeout() bool }
+
+			mock.Expect(Error(AnyErr, ErrorOfType(&target)))
+			mock.Error("open", &fs.PathError{Op: "open", Path: "/etc/app", Err: fs.ErrNotExist})
+			mock.Finish()
+
+			Expect(reporter.failures).To(BeEmpty())
+		})
+
+		It("does not match an error of another type", func() {
+			mock.Expect(Error(AnyErr, ErrorOfType(&fs.PathError{})))
+			mock.Error("open", errors.New("some-error"))
+
+			Expect(reporter.failures).To(ConsistOf(ContainSubstring("Unexpected log entry")))
+		})
+
+		It("returns an error for logs that do not retain the logged error", func() {
+			logger.Error("open", &fs.PathError{Op: "open", Path: "/etc/app", Err: fs.ErrNotExist})
+
+			_, err := ContainSequence(Error(AnyErr, ErrorOfType(&fs.PathError{}))).Match(logger)
+			Expect(err).To(MatchError(ContainSubstring("ErrorOfType can only be applied to entries logged to a MockLogger")))
+		})
+
+		It("panics for targets that are not pointers to error types", func() {
+			Expect(func() { ErrorOfType(fs.PathError{}) }).To(PanicWith(MatchError("ErrorOfType must be passed a pointer. Got fs.PathError.")))
+			Expect(func() { ErrorOfType(new(string)) }).To(Panic())
+		})
+	})
+
+	Describe(".DataSatisfying", func() {
+		BeforeEach(func() {
+			logger.Info("stats", lager.Data{"successes": 3, "failures": 1})
+		})
+
+		It("matches data satisfying the predicate", func() {
+			Expect(logger).To(ContainSequence(
+				Info(DataSatisfying("more successes than failures", func(d map[string]interface{}) bool {
+					return d["successes"].(float64) > d["failures"].(float64)
+				})),
+			))
+		})
+
+		It("does not match data not satisfying the predicate", func() {
+			Expect(logger).ToNot(ContainSequence(
+				Info(DataSatisfying("no failures", func(d map[string]interface{}) bool {
+					return d["failures"].(float64) == 0
+				})),
+			))
+		})
+
+		It("includes the description in the failure message", func() {
+			matcher := ContainSequence(Info(DataSatisfying("no failures", func(map[string]interface{}) bool {
+				return false
+			})))
+
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring("no failures"))
+		})
+	})
+
+	Describe(".CaptureInto", func() {
+		BeforeEach(func() {
+			logger.Info("start", lager.Data{"id": 1})
+			logger.Info("progress", lager.Data{"id": 2})
+			logger.Info("done", lager.Data{"id": 3})
+		})
+
+		It("captures the matched entries", func() {
+			var start, done LogEntry
+			Expect(logger).To(HaveLogged(
+				Info(CaptureInto(&start)),
+				Info(Action("test.done"), CaptureInto(&done)),
+			))
+
+			Expect(start.Message).To(Equal("test.start"))
+			Expect(done.Data).To(HaveKeyWithValue("id", BeNumerically("==", 3)))
+			startTime, err := start.Time()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done.Time()).To(BeTemporally(">=", startTime))
+		})
+
+		It("does not capture anything if the sequence does not match", func() {
+			var start LogEntry
+			Expect(logger).ToNot(HaveLogged(
+				Info(Action("test.start"), CaptureInto(&start)),
+				Info(Action("test.missing")),
+			))
+
+			Expect(start).To(BeZero())
+		})
+
+		It("captures entries matched by a MockLogger", func() {
+			var captured LogEntry
+			reporter := &fakeReporter{}
+			mock := NewMockLogger(reporter, "mock")
+			mock.Expect(Info(CaptureInto(&captured)))
+
+			mock.Info("start", lager.Data{"id": 42})
+			mock.Finish()
+
+			Expect(reporter.failures).To(BeEmpty())
+			Expect(captured.Message).To(Equal("mock.start"))
+		})
+	})
+
+	Describe("CaptureData", func() {
+		var logger *TestLogger
+
+		BeforeEach(func() {
+			logger = NewLogger("test")
+			logger.Info("request", lager.Data{"request_id": "abc-123", "attempt": 2})
+			logger.Info("query", lager.Data{"request_id": "abc-123"})
+		})
+
+		It("captures the data value of the matched entry", func() {
+			var requestID string
+			var attempt int
+			Expect(logger).To(HaveLogged(
+				Info(Action("test.request"), CaptureData("request_id", &requestID), CaptureData("attempt", &attempt)),
+			))
+
+			Expect(requestID).To(Equal("abc-123"))
+			Expect(attempt).To(Equal(2))
+			Expect(logger).To(HaveLogged(Info(Action("test.query"), Data("request_id", requestID))))
+		})
+
+		It("does not match entries without the key", func() {
+			var attempt int
+			Expect(logger).ToNot(HaveLogged(Info(Action("test.query"), CaptureData("attempt", &attempt))))
+		})
+
+		It("does not match entries with a value of a different type", func() {
+			var requestID int
+			Expect(logger).ToNot(HaveLogged(Info(CaptureData("request_id", &requestID))))
+		})
+
+		It("does not capture anything if the sequence does not match", func() {
+			var requestID string
+			Expect(logger).ToNot(HaveLogged(
+				Info(CaptureData("request_id", &requestID)),
+				Info(Action("test.missing")),
+			))
+
+			Expect(requestID).To(BeEmpty())
+		})
+
+		It("describes the type of the target in failure messages", func() {
+			var value interface{}
+			matcher := HaveLogged(Info(Action("test.missing"), CaptureData("request_id", &value)))
+
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring(`data "request_id" of type interface {}`))
+		})
+	})
+
+	Describe("custom options", func() {
+		tenant := func(id string) Option {
+			return Check("tenant "+id, func(actual LogEntry) (bool, error) {
+				tenant, ok := actual.Data["tenant"].(map[string]interface{})
+				return ok && tenant["id"] == id, nil
+			})
+		}
+
+		request := func(id string) Option {
+			return Options(Source("test"), Data("request_id", id))
+		}
+
+		BeforeEach(func() {
+			logger.Info("handle", lager.Data{
+				"tenant":     map[string]interface{}{"id": "acme"},
+				"request_id": "abc",
+			})
+		})
+
+		It("composes with the built-in options", func() {
+			Expect(logger).To(ContainSequence(Info(Action("test.handle"), tenant("acme"), request("abc"))))
+			Expect(logger).ToNot(ContainSequence(Info(tenant("other"))))
+			Expect(logger).ToNot(ContainSequence(Info(request("def"))))
+		})
+
+		It("includes the description in failure messages", func() {
+			matcher := ContainSequence(Info(tenant("other")))
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring("<tenant other>"))
+		})
+
+		It("returns errors of the check", func() {
+			_, err := ContainSequence(Info(Check("failing", func(LogEntry) (bool, error) {
+				return false, errors.New("check failed")
+			}))).Match(logger)
+
+			Expect(err).To(MatchError("check failed"))
+		})
+	})
+})
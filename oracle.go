@@ -0,0 +1,43 @@
+package glager
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+
+	"github.com/onsi/gomega/gbytes"
+)
+
+// mayChange reports whether matching the given actual might have a different
+// outcome in the future. It implements gomega's OracleMatcher for the
+// matchers, i.e. Eventually and Consistently stop polling as soon as it
+// returns false. Logs that are fixed, e.g. strings, entries or closed buffers,
+// do not change. Neither do readers that can not be replayed, as they have
+// been consumed by the first match. Other logs, e.g. files, remote logs, or
+// custom readers and ContentsProviders, might change at any time.
+func mayChange(actual interface{}) bool {
+	switch x := actual.(type) {
+	case string, []byte, []LogEntry, logEntries, snapshot, *strings.Reader, *bytes.Reader:
+		return false
+	case *gbytes.Buffer:
+		return !x.Closed()
+	case gbytes.BufferProvider:
+		return !x.Buffer().Closed()
+	case formattedLog:
+		return mayChange(x.log)
+	case otlpLog:
+		return mayChange(x.log)
+	case mergedLog:
+		for _, origin := range x {
+			if mayChange(origin.log) {
+				return true
+			}
+		}
+		return false
+	case ContentsProvider, RemoteLog, logFile, logFiles:
+		return true
+	default:
+		// readers that are passed by value are consumed by the first match
+		return reflect.ValueOf(actual).Kind() == reflect.Ptr
+	}
+}
@@ -0,0 +1,74 @@
+package glager_test
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/types"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("MatchMayChangeInTheFuture", func() {
+	const log = `{"timestamp":"1","source":"test","message":"test.started","log_level":1,"data":{}}` + "\n"
+
+	var matcher types.GomegaMatcher
+
+	BeforeEach(func() {
+		matcher = ContainSequence(Info(Action("test.started")))
+	})
+
+	mayChange := func(actual interface{}) bool {
+		oracle, ok := matcher.(interface {
+			MatchMayChangeInTheFuture(interface{}) bool
+		})
+		Expect(ok).To(BeTrue())
+		return oracle.MatchMayChangeInTheFuture(actual)
+	}
+
+	It("reports that fixed logs do not change", func() {
+		Expect(mayChange(log)).To(BeFalse())
+		Expect(mayChange([]byte(log))).To(BeFalse())
+		Expect(mayChange(strings.NewReader(log))).To(BeFalse())
+		Expect(mayChange(bytes.NewReader([]byte(log)))).To(BeFalse())
+		Expect(mayChange([]LogEntry{})).To(BeFalse())
+		Expect(mayChange(WithFormat(Slog, log))).To(BeFalse())
+	})
+
+	It("reports that closed buffers do not change", func() {
+		buffer := gbytes.NewBuffer()
+		Expect(mayChange(buffer)).To(BeTrue())
+
+		buffer.Close()
+		Expect(mayChange(buffer)).To(BeFalse())
+	})
+
+	It("reports that live logs might change", func() {
+		Expect(mayChange(NewLogger("test"))).To(BeTrue())
+		Expect(mayChange(&bytes.Buffer{})).To(BeTrue())
+		Expect(mayChange(FromFile("app.log"))).To(BeTrue())
+		Expect(mayChange(Merge(FromOrigin("a", log), FromOrigin("b", gbytes.NewBuffer())))).To(BeTrue())
+	})
+
+	It("is implemented by the other matchers", func() {
+		matcher = HaveNoDuplicateEntries()
+		Expect(mayChange(log)).To(BeFalse())
+
+		matcher = ContainAll(Info())
+		Expect(mayChange(log)).To(BeFalse())
+	})
+
+	It("lets Eventually fail fast on logs that do not change", func() {
+		start := time.Now()
+		failures := InterceptGomegaFailures(func() {
+			Eventually(log, 10*time.Second).Should(ContainSequence(Info(Action("test.finished"))))
+		})
+
+		Expect(failures).To(HaveLen(1))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+})
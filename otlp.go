@@ -0,0 +1,264 @@
+package glager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// otlpRequest is the JSON encoding of an OTLP ExportLogsServiceRequest.
+type otlpRequest struct {
+	ResourceLogs []struct {
+		ScopeLogs []struct {
+			Scope struct {
+				Name string `json:"name"`
+			} `json:"scope"`
+			LogRecords []otlpRecord `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+// otlpRecord is the JSON encoding of an OTLP LogRecord.
+type otlpRecord struct {
+	TimeUnixNano         json.Number    `json:"timeUnixNano"`
+	ObservedTimeUnixNano json.Number    `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 *otlpValue     `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes"`
+	TraceID              string         `json:"traceId"`
+	SpanID               string         `json:"spanId"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+// otlpValue is the JSON encoding of an OTLP AnyValue. Integers are encoded as
+// strings or numbers.
+type otlpValue struct {
+	StringValue *string      `json:"stringValue"`
+	BoolValue   *bool        `json:"boolValue"`
+	IntValue    *json.Number `json:"intValue"`
+	DoubleValue *float64     `json:"doubleValue"`
+	BytesValue  *string      `json:"bytesValue"`
+	ArrayValue  *struct {
+		Values []otlpValue `json:"values"`
+	} `json:"arrayValue"`
+	KvlistValue *struct {
+		Values []otlpKeyValue `json:"values"`
+	} `json:"kvlistValue"`
+}
+
+type otlpLog struct {
+	log    interface{}
+	levels Levels
+}
+
+// WithOTLP converts a log of OpenTelemetry log records, encoded as OTLP JSON,
+// to the lager format, so that it can be passed to any of the matchers. The
+// log can be anything that is accepted by the ContainSequence matcher and
+// contains a sequence of ExportLogsServiceRequest payloads, e.g. as written by
+// the file exporter of the OpenTelemetry Collector, or of single LogRecords.
+// Payloads do not have to be written on a single line.
+//
+// The body of a record becomes the message of the entry, its attributes become
+// the data, and the name of its instrumentation scope becomes the source.
+// Severity numbers are mapped to lager levels, i.e. TRACE and DEBUG to
+// lager.DEBUG, INFO to lager.INFO, WARN to LevelWarn, ERROR to lager.ERROR,
+// and FATAL to lager.FATAL. Records without a severity are mapped to
+// lager.INFO. The traceId and spanId of a record are kept as fields of the
+// entry, see TopLevelField. Resource attributes are ignored.
+//
+// Example:
+//
+//	Expect(WithOTLP(FromFile("logs.json"))).To(ContainSequence(
+//	  Info(Source("checkout"), Message("order placed"), Data("order.id", "42")),
+//	))
+func WithOTLP(log interface{}) otlpLog {
+	return otlpLog{log: log}
+}
+
+// WithOTLP is like the WithOTLP function, but maps the severity texts of the
+// records using the given levels first, e.g. to alias "WARN" onto LevelInfo.
+//
+// Example:
+//
+//	levels := Levels{"warn": LevelInfo}
+//
+//	Expect(levels.WithOTLP(FromFile("logs.json"))).To(ContainSequence(Info(Message("slow request"))))
+func (l Levels) WithOTLP(log interface{}) otlpLog {
+	return otlpLog{log: log, levels: l}
+}
+
+// entries reads and converts all payloads of the log. A trailing payload that
+// is still being written is ignored if the log is live.
+func (o otlpLog) entries() (logEntries, error) {
+	reader, err := contentsReader("WithOTLP", o.log)
+	if err != nil {
+		return nil, err
+	}
+	defer closeContents(reader)
+
+	entries := logEntries{}
+	decoder := json.NewDecoder(reader)
+
+	for {
+		var payload struct {
+			otlpRequest
+			otlpRecord
+		}
+
+		err := decoder.Decode(&payload)
+		if err == io.EOF {
+			return entries, nil
+		}
+
+		if err != nil {
+			if err == io.ErrUnexpectedEOF && isLive(o.log) {
+				return entries, nil
+			}
+			return nil, err
+		}
+
+		if payload.ResourceLogs == nil {
+			entry, err := payload.otlpRecord.entry("", o.levels)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+			continue
+		}
+
+		for _, resourceLogs := range payload.ResourceLogs {
+			for _, scopeLogs := range resourceLogs.ScopeLogs {
+				for _, record := range scopeLogs.LogRecords {
+					entry, err := record.entry(scopeLogs.Scope.Name, o.levels)
+					if err != nil {
+						return nil, err
+					}
+					entries = append(entries, entry)
+				}
+			}
+		}
+	}
+}
+
+// entry converts the record into a lager entry.
+func (r otlpRecord) entry(source string, levels Levels) (LogEntry, error) {
+	level, err := parseOTLPSeverity(r.SeverityNumber, r.SeverityText, levels)
+	if err != nil {
+		return LogEntry{}, err
+	}
+
+	entry := LogEntry{
+		Source:    source,
+		LogLevel:  level,
+		Timestamp: otlpTimestamp(r.TimeUnixNano),
+		Data:      lager.Data{},
+	}
+
+	if entry.Timestamp == "" {
+		entry.Timestamp = otlpTimestamp(r.ObservedTimeUnixNano)
+	}
+
+	if r.Body != nil {
+		switch body := r.Body.decode().(type) {
+		case string:
+			entry.Message = body
+		default:
+			encoded, err := json.Marshal(body)
+			if err != nil {
+				return LogEntry{}, err
+			}
+			entry.Message = string(encoded)
+		}
+	}
+
+	for _, attribute := range r.Attributes {
+		entry.Data[attribute.Key] = attribute.Value.decode()
+	}
+
+	if r.TraceID != "" || r.SpanID != "" {
+		entry.Fields = map[string]interface{}{}
+		if r.TraceID != "" {
+			entry.Fields["traceId"] = r.TraceID
+		}
+		if r.SpanID != "" {
+			entry.Fields["spanId"] = r.SpanID
+		}
+	}
+
+	return entry, nil
+}
+
+// decode converts the value into the value it would be decoded to if it had
+// been logged as plain JSON.
+func (v otlpValue) decode() interface{} {
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.BoolValue != nil:
+		return *v.BoolValue
+	case v.IntValue != nil:
+		n, _ := v.IntValue.Float64()
+		return n
+	case v.DoubleValue != nil:
+		return *v.DoubleValue
+	case v.BytesValue != nil:
+		return *v.BytesValue
+	case v.ArrayValue != nil:
+		values := make([]interface{}, len(v.ArrayValue.Values))
+		for i, value := range v.ArrayValue.Values {
+			values[i] = value.decode()
+		}
+		return values
+	case v.KvlistValue != nil:
+		values := make(map[string]interface{}, len(v.KvlistValue.Values))
+		for _, kv := range v.KvlistValue.Values {
+			values[kv.Key] = kv.Value.decode()
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// otlpTimestamp renders nanoseconds since the epoch as epoch seconds, the way
+// lager does.
+func otlpTimestamp(nanos json.Number) string {
+	n, err := strconv.ParseInt(string(nanos), 10, 64)
+	if err != nil || n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d.%09d", n/1e9, n%1e9)
+}
+
+// parseOTLPSeverity maps an OTLP severity number to the corresponding lager
+// level. Severity texts mapped by the given levels take precedence.
+func parseOTLPSeverity(number int, text string, levels Levels) (lager.LogLevel, error) {
+	if level, found := levels.lookup(text); found {
+		return level.LogLevel(), nil
+	}
+
+	switch {
+	case number == 0:
+		return lager.INFO, nil
+	case number >= 1 && number <= 8:
+		return lager.DEBUG, nil
+	case number >= 9 && number <= 12:
+		return lager.INFO, nil
+	case number >= 13 && number <= 16:
+		return LevelWarn.LogLevel(), nil
+	case number >= 17 && number <= 20:
+		return lager.ERROR, nil
+	case number >= 21 && number <= 24:
+		return lager.FATAL, nil
+	default:
+		return 0, fmt.Errorf("Invalid OTLP severity number %d.", number)
+	}
+}
@@ -0,0 +1,131 @@
+package glager_test
+
+import (
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".WithOTLP", func() {
+	const payload = `{
+  "resourceLogs": [{
+    "resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "checkout"}}]},
+    "scopeLogs": [{
+      "scope": {"name": "checkout.orders"},
+      "logRecords": [
+        {
+          "timeUnixNano": "1700000000500000000",
+          "severityNumber": 9,
+          "severityText": "INFO",
+          "body": {"stringValue": "order placed"},
+          "attributes": [
+            {"key": "order.id", "value": {"stringValue": "42"}},
+            {"key": "items", "value": {"intValue": "3"}},
+            {"key": "total", "value": {"doubleValue": 9.99}},
+            {"key": "express", "value": {"boolValue": true}},
+            {"key": "tags", "value": {"arrayValue": {"values": [{"stringValue": "gift"}]}}},
+            {"key": "customer", "value": {"kvlistValue": {"values": [{"key": "id", "value": {"intValue": 7}}]}}}
+          ],
+          "traceId": "5b8efff798038103d269b633813fc60c",
+          "spanId": "eee19b7ec3c1b174"
+        },
+        {
+          "observedTimeUnixNano": "1700000001000000000",
+          "severityNumber": 17,
+          "body": {"stringValue": "payment failed"},
+          "attributes": [{"key": "error", "value": {"stringValue": "card declined"}}]
+        }
+      ]
+    }]
+  }]
+}
+`
+
+	It("maps OTLP log records onto lager entries", func() {
+		Expect(WithOTLP(payload)).To(ContainExactSequence(
+			Info(
+				Source("checkout.orders"),
+				Message("order placed"),
+				Data("order.id", "42", "items", 3, "total", 9.99, "express", true, "tags", []string{"gift"}),
+				DataAt("customer.id", 7),
+				TopLevelField("traceId", "5b8efff798038103d269b633813fc60c"),
+			),
+			Error(AnyErr, Source("checkout.orders"), Message("payment failed"), Data("error", "card declined")),
+		))
+	})
+
+	It("parses the timestamps", func() {
+		entries, err := Entries(WithOTLP(payload))
+		Expect(err).ToNot(HaveOccurred())
+
+		t, err := entries[0].Time()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(t.UnixNano()).To(Equal(int64(1700000000500000000)))
+
+		t, err = entries[1].Time()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(t.Unix()).To(Equal(int64(1700000001)))
+	})
+
+	It("reads a sequence of payloads and single records", func() {
+		log := strings.ReplaceAll(payload, "\n", "") + "\n" +
+			`{"severityNumber":5,"body":{"stringValue":"cache warmed"}}` + "\n"
+
+		Expect(WithOTLP(log)).To(ContainSequence(
+			Info(Message("order placed")),
+			Error(AnyErr, Message("payment failed")),
+			Debug(Message("cache warmed")),
+		))
+	})
+
+	It("encodes structured bodies as JSON", func() {
+		log := `{"body":{"kvlistValue":{"values":[{"key":"event","value":{"stringValue":"login"}}]}}}`
+		Expect(WithOTLP(log)).To(ContainSequence(Info(Message(`{"event":"login"}`))))
+	})
+
+	It("ignores a trailing payload that is still being written to a live log", func() {
+		buffer := gbytes.NewBuffer()
+		buffer.Write([]byte(payload + `{"resourceLogs": [{"scopeLogs": [`))
+
+		Expect(WithOTLP(buffer)).To(ContainSequence(Info(Message("order placed"))))
+	})
+
+	table.DescribeTable("mapping severity numbers",
+		func(number string, expected lager.LogLevel) {
+			log := `{"severityNumber":` + number + `,"body":{"stringValue":"test"}}`
+			Expect(WithOTLP(log)).To(ContainSequence(Entry(expected, Message("test"))))
+		},
+		table.Entry("unspecified", "0", lager.INFO),
+		table.Entry("trace", "1", lager.DEBUG),
+		table.Entry("debug", "8", lager.DEBUG),
+		table.Entry("info", "9", lager.INFO),
+		table.Entry("info", "12", lager.INFO),
+		table.Entry("warn", "13", LevelWarn.LogLevel()),
+		table.Entry("error", "17", lager.ERROR),
+		table.Entry("fatal", "24", lager.FATAL),
+	)
+
+	It("maps severity texts using the given levels", func() {
+		log := `{"severityNumber":13,"severityText":"WARN","body":{"stringValue":"slow"}}`
+
+		Expect(Levels{"warn": Level(10)}.WithOTLP(log)).To(ContainSequence(Entry(10, Message("slow"))))
+		Expect(WithOTLP(log)).To(ContainSequence(Entry(LevelWarn.LogLevel(), Message("slow"))))
+	})
+
+	It("returns an error for invalid severity numbers", func() {
+		_, err := ContainSequence(Info()).Match(WithOTLP(`{"severityNumber":25}`))
+		Expect(err).To(MatchError("Invalid OTLP severity number 25."))
+	})
+
+	It("returns an error for invalid payloads", func() {
+		_, err := ContainSequence(Info()).Match(WithOTLP(`{"resourceLogs": 42}`))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,82 @@
+package glager
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// placeholderPattern matches the placeholders of a message pattern, e.g.
+// "{id}".
+var placeholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// MessagePattern specifies that the message of a given log entry must match
+// the given pattern, in which placeholders like {id} match any segment of a
+// dotted message, i.e. any non-empty text without dots. Everything else has to
+// match literally. The text matched by the placeholders is captured and can
+// be retrieved from the Captures of a MatchResult. MessagePattern panics if a
+// placeholder occurs more than once.
+//
+// Example:
+//
+//	result, err := MatchSequence(logger, Info(MessagePattern("server.request-{id}.finished")))
+//	Expect(err).ToNot(HaveOccurred())
+//	Expect(result.Captures).To(HaveKeyWithValue("id", "42"))
+func MessagePattern(pattern string) Option {
+	re := compileMessagePattern(pattern)
+
+	check := withCheck(fmt.Sprintf("message pattern %q", pattern), func(actual LogEntry) (bool, error) {
+		return re.MatchString(actual.Message), nil
+	})
+
+	return func(e *logEntry) {
+		check(e)
+		e.patterns = append(e.patterns, re)
+	}
+}
+
+// compileMessagePattern converts a message pattern into a regular expression
+// with a named group per placeholder.
+func compileMessagePattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+
+	seen := map[string]bool{}
+	last := 0
+
+	for _, loc := range placeholderPattern.FindAllStringSubmatchIndex(pattern, -1) {
+		name := pattern[loc[2]:loc[3]]
+		if seen[name] {
+			panic(fmt.Errorf("MessagePattern must be passed a pattern with distinct placeholders. Got %q.", pattern))
+		}
+		seen[name] = true
+
+		b.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		fmt.Fprintf(&b, `(?P<%s>[^.]+)`, name)
+		last = loc[1]
+	}
+
+	b.WriteString(regexp.QuoteMeta(pattern[last:]))
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String())
+}
+
+// placeholderValues returns the text matched by the placeholders of the
+// message patterns of the expected entry in the message of the actual entry.
+func (entry logEntry) placeholderValues(actual LogEntry) map[string]string {
+	values := map[string]string{}
+	for _, re := range entry.patterns {
+		match := re.FindStringSubmatch(actual.Message)
+		if match == nil {
+			continue
+		}
+
+		for i, name := range re.SubexpNames() {
+			if name != "" {
+				values[name] = match[i]
+			}
+		}
+	}
+	return values
+}
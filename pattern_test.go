@@ -0,0 +1,57 @@
+package glager_test
+
+import (
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".MessagePattern", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("server")
+		logger.Info("request-42.started")
+		logger.Info("request-42.finished", lager.Data{"status": 200})
+		logger.Info("request-43.finished.early")
+	})
+
+	It("matches messages with any segment in place of the placeholders", func() {
+		Expect(logger).To(ContainSequence(
+			Info(MessagePattern("server.request-{id}.started")),
+			Info(MessagePattern("{source}.request-{id}.finished"), Data("status", 200)),
+		))
+	})
+
+	It("matches the rest of the pattern literally", func() {
+		Expect(logger).ToNot(HaveLogged(Info(MessagePattern("server.request-{id}.finished.*"))))
+		Expect(logger).ToNot(HaveLogged(Info(MessagePattern("server.request-{id}"))))
+	})
+
+	It("does not match text including dots", func() {
+		Expect(logger).ToNot(HaveLogged(Info(MessagePattern("server.{action}"))))
+	})
+
+	It("captures the placeholders in the match result", func() {
+		result, err := MatchSequence(logger,
+			Info(MessagePattern("server.request-{first}.started")),
+			Info(MessagePattern("server.request-{second}.finished.{how}")),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Success).To(BeTrue())
+		Expect(result.Captures).To(Equal(map[string]string{"first": "42", "second": "43", "how": "early"}))
+	})
+
+	It("is rendered in failure messages", func() {
+		matcher := HaveLogged(Info(MessagePattern("server.job-{id}.started")))
+		Expect(matcher.Match(logger)).To(BeFalse())
+		Expect(matcher.FailureMessage(logger)).To(ContainSubstring(`Info(<message pattern "server.job-{id}.started">)`))
+	})
+
+	It("panics for repeated placeholders", func() {
+		Expect(func() { MessagePattern("{id}.{id}") }).To(PanicWith(MatchError(`MessagePattern must be passed a pattern with distinct placeholders. Got "{id}.{id}".`)))
+	})
+})
@@ -0,0 +1,84 @@
+package glager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxPolls is the number of distinct poll outcomes that are included in the
+// failure message of a matcher that has been polled, e.g. by Eventually.
+const maxPolls = 10
+
+// poll is the outcome of a single call to Match.
+type poll struct {
+	at      time.Time
+	entries int
+	matched int
+	nearest string
+	repeats int
+}
+
+func (p poll) sameOutcome(other poll) bool {
+	return p.entries == other.entries && p.matched == other.matched && p.nearest == other.nearest
+}
+
+// recordPoll remembers the outcome of the current match. Subsequent polls with
+// the same outcome are collapsed.
+func (lm *logMatcher) recordPoll() {
+	current := poll{
+		at:      time.Now(),
+		entries: lm.entries(),
+		matched: len(lm.matched),
+	}
+
+	if len(lm.mismatches) > 0 {
+		current.nearest = lm.mismatches[0]
+	}
+
+	if n := len(lm.polls); n > 0 && lm.polls[n-1].sameOutcome(current) {
+		lm.polls[n-1].repeats++
+		return
+	}
+
+	lm.polls = append(lm.polls, current)
+}
+
+// polled reports whether the matcher has been matched more than once, e.g. by
+// Eventually.
+func (lm *logMatcher) polled() bool {
+	return len(lm.polls) > 1 || len(lm.polls) == 1 && lm.polls[0].repeats > 0
+}
+
+// timeline renders the outcomes of all polls, i.e. which expected entry has
+// been blocking the sequence and how close the nearest candidate came. This
+// helps to triage flaky assertions using Eventually.
+func (lm *logMatcher) timeline() string {
+	polls := lm.polls
+	lines := []string{}
+
+	if len(polls) > maxPolls {
+		lines = append(lines, fmt.Sprintf("... %d earlier outcomes", len(polls)-maxPolls))
+		polls = polls[len(polls)-maxPolls:]
+	}
+
+	for _, p := range polls {
+		line := fmt.Sprintf("+%s: %d entries, matched %d of %d", p.at.Sub(lm.polls[0].at).Round(time.Millisecond), p.entries, p.matched, len(lm.expected))
+
+		if p.matched < len(lm.expected) {
+			line += fmt.Sprintf(", blocked by %d: %s", p.matched, lm.expected[p.matched].GomegaString())
+		}
+
+		if p.nearest != "" {
+			line += fmt.Sprintf(", nearest %s", p.nearest)
+		}
+
+		if p.repeats > 0 {
+			line += fmt.Sprintf(" (%d more polls)", p.repeats)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return fmt.Sprintf("\nPolls:\n\t%s", strings.Join(lines, "\n\t"))
+}
@@ -0,0 +1,68 @@
+package glager_test
+
+import (
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("polling", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		logger.Info("start")
+	})
+
+	It("includes a timeline of the polls in the failure message", func() {
+		matcher := ContainSequence(
+			Info(Action("test.start")),
+			Info(Action("test.ready"), Data("port", 8080)),
+			Info(Action("test.done")),
+		)
+
+		Expect(matcher.Match(logger)).To(BeFalse())
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		logger.Info("ready", lager.Data{"port": 9090})
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		message := matcher.FailureMessage(logger)
+		timeline := message[strings.Index(message, "\nPolls:\n"):]
+
+		Expect(timeline).To(MatchRegexp(`\+0s: 1 entries, matched 1 of 3, blocked by 1: Info\(Message\("test.ready"\), Data\("port", 8080\)\) \(1 more polls\)`))
+		Expect(timeline).To(MatchRegexp(`\+\d+(ms|µs|s|ns)?: 2 entries, matched 1 of 3, blocked by 1: .*, nearest entry 1: data.port: .*`))
+	})
+
+	It("does not include a timeline for a single poll", func() {
+		matcher := ContainSequence(Info(Action("test.done")))
+		Expect(matcher.Match(logger)).To(BeFalse())
+		Expect(matcher.FailureMessage(logger)).ToNot(ContainSubstring("Polls:"))
+	})
+
+	It("includes the timeline when used with Eventually", func() {
+		failures := InterceptGomegaFailures(func() {
+			Eventually(logger, "50ms", "5ms").Should(ContainSequence(Info(Action("test.done"))))
+		})
+
+		Expect(failures).To(HaveLen(1))
+		Expect(failures[0]).To(ContainSubstring("Polls:\n\t+0s: 1 entries, matched 0 of 1, blocked by 0: Info(Message(\"test.done\")) ("))
+	})
+
+	It("limits the number of outcomes", func() {
+		matcher := ContainSequence(Info(Action("test.done")))
+		for i := 0; i < 15; i++ {
+			logger.Info("tick")
+			matcher.Match(logger)
+		}
+
+		message := matcher.FailureMessage(logger)
+		Expect(message).To(ContainSubstring("... 5 earlier outcomes"))
+		Expect(strings.Count(message, "blocked by 0")).To(Equal(10))
+	})
+})
@@ -0,0 +1,65 @@
+package glager
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// decodeLines decodes the entries of the given reader line by line, converting
+// every line using the given format first. The returned offsets refer to the
+// raw lines. If the log is live, a trailing line that cannot be converted or
+// decoded is ignored, it is probably still being written.
+func decodeLines(reader io.Reader, format Format, live bool) (logEntries, []int64, error) {
+	buffered := bufio.NewReader(reader)
+
+	entries := logEntries{}
+	offsets := []int64{}
+
+	var offset int64
+	var lineNo int
+	for {
+		lineNo++
+		line, readErr := buffered.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return entries, offsets, readErr
+		}
+
+		offset += int64(len(line))
+		end := offset
+
+		terminated := bytes.HasSuffix(line, []byte("\n"))
+		if terminated {
+			line = line[:len(line)-1]
+			end--
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			converted, err := format(line)
+			if err != nil {
+				if !terminated && live {
+					return entries, offsets, nil
+				}
+				return entries, offsets, &ParseError{Line: lineNo, Content: string(line), Err: err}
+			}
+
+			if len(bytes.TrimSpace(converted)) > 0 {
+				var entry LogEntry
+				if err := json.Unmarshal(converted, &entry); err != nil {
+					if !terminated && live {
+						return entries, offsets, nil
+					}
+					return entries, offsets, &ParseError{Line: lineNo, Content: string(line), Err: err}
+				}
+
+				entries = append(entries, entry)
+				offsets = append(offsets, end)
+			}
+		}
+
+		if readErr == io.EOF {
+			return entries, offsets, nil
+		}
+	}
+}
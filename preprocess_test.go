@@ -0,0 +1,85 @@
+package glager_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("preprocessing lines using WithFormat", func() {
+	const (
+		first  = `{"timestamp":"1","source":"app","message":"app.start","log_level":1,"data":{}}`
+		second = `{"timestamp":"2","source":"app","message":"app.done","log_level":1,"data":{"ok":true}}`
+	)
+
+	It("pre-processes lines before decoding", func() {
+		preprocess := func(line []byte) ([]byte, error) {
+			return bytes.TrimPrefix(line, []byte("[app] ")), nil
+		}
+
+		log := "[app] " + first + "\n[app] " + second + "\n"
+		Expect(WithFormat(preprocess, strings.NewReader(log))).To(ContainSequence(
+			Info(Action("app.start")),
+			Info(Action("app.done"), Data("ok", true)),
+		))
+	})
+
+	It("decodes wrapped lines", func() {
+		preprocess := func(line []byte) ([]byte, error) {
+			return base64.StdEncoding.DecodeString(string(line))
+		}
+
+		log := base64.StdEncoding.EncodeToString([]byte(first)) + "\n"
+		Expect(WithFormat(preprocess, strings.NewReader(log))).To(ContainSequence(Info(Action("app.start"))))
+	})
+
+	It("skips lines the preprocessor discards", func() {
+		preprocess := func(line []byte) ([]byte, error) {
+			if !bytes.HasPrefix(line, []byte("{")) {
+				return nil, nil
+			}
+			return line, nil
+		}
+
+		log := "starting app...\n" + first + "\n\n" + second
+		entries, err := Entries(WithFormat(preprocess, strings.NewReader(log)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(2))
+	})
+
+	It("returns errors of the preprocessor along with the failing line", func() {
+		errCannotDecrypt := errors.New("cannot decrypt")
+		preprocess := func(line []byte) ([]byte, error) {
+			if strings.Contains(string(line), "app.done") {
+				return nil, errCannotDecrypt
+			}
+			return line, nil
+		}
+
+		_, err := Entries(WithFormat(preprocess, strings.NewReader(first+"\n"+second+"\n")))
+		Expect(err).To(MatchError(errCannotDecrypt))
+
+		var parseErr *ParseError
+		Expect(errors.As(err, &parseErr)).To(BeTrue())
+		Expect(parseErr.Line).To(Equal(2))
+		Expect(parseErr.Content).To(Equal(second))
+	})
+
+	It("only affects the given log", func() {
+		preprocess := func(line []byte) ([]byte, error) {
+			return bytes.TrimPrefix(line, []byte("[app] ")), nil
+		}
+
+		log := "[app] " + first + "\n"
+		Expect(WithFormat(preprocess, strings.NewReader(log))).To(ContainSequence(Info(Action("app.start"))))
+
+		_, err := Entries(strings.NewReader(log))
+		Expect(err).To(HaveOccurred())
+	})
+})
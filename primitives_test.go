@@ -0,0 +1,87 @@
+package glager_test
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+
+	. "github.com/st3v/glager"
+)
+
+// haveAuditTrail is an example of a custom matcher built on glager's
+// primitives. It checks that every login is followed by a logout of the
+// same user.
+func haveAuditTrail(login, logout func(user string) ExpectedEntry) types.GomegaMatcher {
+	return WithTransform(func(actual interface{}) (string, error) {
+		entries, err := Entries(actual)
+		if err != nil {
+			return "", err
+		}
+
+		for i, entry := range entries {
+			user, _ := entry.Data["user"].(string)
+
+			matches, err := entry.Matches(login(user))
+			if err != nil || !matches {
+				continue
+			}
+
+			matched, err := ScanSequence(entries[i+1:], logout(user))
+			if err != nil {
+				return "", err
+			}
+
+			if len(matched) == 0 {
+				return fmt.Sprintf("missing logout of %s", user), nil
+			}
+		}
+
+		return "", nil
+	}, BeEmpty())
+}
+
+var _ = Describe("matching primitives", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("audit")
+		logger.Info("login", lager.Data{"user": "alice"})
+		logger.Info("login", lager.Data{"user": "bob"})
+		logger.Info("logout", lager.Data{"user": "alice"})
+	})
+
+	Describe("LogEntry.Matches", func() {
+		It("matches entries against expected entries", func() {
+			entries, err := Entries(logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(entries[0].Matches(Info(Action("audit.login"), Data("user", "alice")))).To(BeTrue())
+			Expect(entries[0].Matches(Info(Data("user", "bob")))).To(BeFalse())
+			Expect(entries[0].Matches(Error(AnyErr))).To(BeFalse())
+		})
+	})
+
+	Describe(".ScanSequence", func() {
+		It("returns the indices of the matched entries", func() {
+			entries, err := Entries(logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ScanSequence(entries, Info(Action("audit.login")), Info(Action("audit.logout")))).To(Equal([]int{0, 2}))
+			Expect(ScanSequence(entries, Info(Action("audit.logout")), Info(Action("audit.login")))).To(Equal([]int{2}))
+		})
+	})
+
+	It("can be used to build custom matchers", func() {
+		login := func(user string) ExpectedEntry { return Info(Action("audit.login"), Data("user", user)) }
+		logout := func(user string) ExpectedEntry { return Info(Action("audit.logout"), Data("user", user)) }
+
+		Expect(logger).ToNot(haveAuditTrail(login, logout))
+
+		logger.Info("logout", lager.Data{"user": "bob"})
+		Expect(logger).To(haveAuditTrail(login, logout))
+	})
+})
@@ -0,0 +1,29 @@
+package glager
+
+import (
+	"os/exec"
+
+	"github.com/onsi/gomega/gexec"
+)
+
+// StartProcess starts the given command and captures its stdout and stderr
+// using gexec. The returned session can be passed to any of the matchers to
+// match its stdout, and to ProcessLog to match stdout and stderr combined.
+//
+// Example:
+//
+//	session, err := StartProcess(exec.Command(pathToServer))
+//	Expect(err).ToNot(HaveOccurred())
+//	Eventually(ProcessLog(session)).Should(ContainSequence(
+//	  Info(Action("server.started")),
+//	))
+func StartProcess(cmd *exec.Cmd) (*gexec.Session, error) {
+	return gexec.Start(cmd, nil, nil)
+}
+
+// ProcessLog returns the combined log written by the given gexec session to
+// stdout and stderr, ordered by timestamps. It is read every time it is
+// matched, which makes it suitable for Eventually.
+func ProcessLog(session *gexec.Session) mergedLog {
+	return Interleave(session.Out, session.Err)
+}
@@ -0,0 +1,46 @@
+package glager_test
+
+import (
+	"os/exec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("processes", func() {
+	script := `
+echo '{"timestamp":"1.0","source":"app","message":"app.start","log_level":1,"data":{}}'
+echo '{"timestamp":"2.0","source":"app","message":"app.failed","log_level":2,"data":{"error":"boom"}}' >&2
+sleep 0.2
+echo '{"timestamp":"3.0","source":"app","message":"app.exit","log_level":1,"data":{}}'
+`
+
+	It("matches the stdout of a started process", func() {
+		session, err := StartProcess(exec.Command("sh", "-c", script))
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(session).Should(ContainSequence(Info(Action("app.start")), Info(Action("app.exit"))))
+		Expect(session).ToNot(ContainSequence(Error(AnyErr)))
+		Eventually(session).Should(gexec.Exit(0))
+	})
+
+	It("matches the combined stdout and stderr of a process", func() {
+		session, err := StartProcess(exec.Command("sh", "-c", script))
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(ProcessLog(session)).Should(ContainSequence(
+			Info(Action("app.start")),
+			Error(AnyErr, Action("app.failed")),
+			Info(Action("app.exit")),
+		))
+		Eventually(session).Should(gexec.Exit(0))
+	})
+
+	It("returns an error if the process cannot be started", func() {
+		_, err := StartProcess(exec.Command("/does/not/exist"))
+		Expect(err).To(HaveOccurred())
+	})
+})
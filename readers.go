@@ -0,0 +1,85 @@
+package glager
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"sync"
+)
+
+// ReplayableReader is an io.Reader actual that can be matched multiple times,
+// see Replayable.
+type ReplayableReader struct {
+	reader   io.Reader
+	contents []byte
+	lock     sync.Mutex
+}
+
+// Replayable wraps the given reader so it can be matched multiple times, e.g.
+// after a failed assertion or by several matchers. Reading a plain io.Reader
+// consumes it, i.e. every match only sees what has been written to it since
+// the previous one. The returned reader instead retains everything that has
+// been read from the given reader and replays it on every match, followed by
+// whatever the reader has to offer by then. The retained contents are released
+// along with the returned reader.
+//
+// Example:
+//
+//	log := Replayable(session.Out)
+//	Expect(log).To(ContainSequence(Info(Action("app.start"))))
+//	Eventually(log).Should(ContainSequence(Info(Action("app.ready"))))
+func Replayable(reader io.Reader) *ReplayableReader {
+	return &ReplayableReader{reader: reader}
+}
+
+// replay returns a reader for everything that has been read from the wrapped
+// reader so far, including whatever it currently has to offer.
+func (r *ReplayableReader) replay() (io.Reader, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	contents, err := io.ReadAll(r.reader)
+	r.contents = append(r.contents, contents...)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(r.contents), nil
+}
+
+// replayReader returns a reader for the contents of the given reader. Readers
+// that implement io.Seeker are rewound to their current offset after reading,
+// which allows matching files multiple times. Other readers are consumed by
+// reading them, unless they have been wrapped using Replayable.
+func replayReader(reader io.Reader) (io.Reader, error) {
+	if seeker, ok := reader.(io.Seeker); ok {
+		return rewindReader(reader, seeker)
+	}
+	return reader, nil
+}
+
+// rewindReader reads the contents of the given reader starting at its current
+// offset, and seeks back to that offset afterwards. Readers that implement
+// io.ReaderAt, e.g. files, are read from that offset in place instead, without
+// loading them into memory up front.
+func rewindReader(reader io.Reader, seeker io.Seeker) (io.Reader, error) {
+	offset, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	if readerAt, ok := reader.(io.ReaderAt); ok {
+		return io.NewSectionReader(readerAt, offset, math.MaxInt64-offset), nil
+	}
+
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(contents), nil
+}
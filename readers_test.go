@@ -0,0 +1,122 @@
+package glager_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("io.Reader actuals", func() {
+	var (
+		log    *bytes.Buffer
+		logger lager.Logger
+	)
+
+	BeforeEach(func() {
+		log = &bytes.Buffer{}
+		logger = lager.NewLogger("test")
+		logger.RegisterSink(lager.NewWriterSink(log, lager.DEBUG))
+		logger.Info("start")
+	})
+
+	It("are consumed by matching them", func() {
+		Expect(log).To(ContainSequence(Info(Action("test.start"))))
+		Expect(log).ToNot(ContainSequence(Info(Action("test.start"))))
+	})
+
+	Context("when the reader is wrapped using Replayable", func() {
+		var replayable *ReplayableReader
+
+		BeforeEach(func() {
+			replayable = Replayable(log)
+		})
+
+		It("can be matched after a failed assertion", func() {
+			Expect(replayable).ToNot(ContainSequence(Info(Action("test.missing"))))
+			Expect(replayable).To(ContainSequence(Info(Action("test.start"))))
+		})
+
+		It("can be matched by several matchers", func() {
+			Expect(replayable).To(BeNDJSON())
+			Expect(replayable).To(ContainSequence(Info(Action("test.start"))))
+			Expect(Entries(replayable)).To(HaveLen(1))
+		})
+
+		It("includes contents written after previous assertions", func() {
+			Expect(replayable).To(ContainSequence(Info(Action("test.start"))))
+
+			logger.Info("done")
+			Expect(replayable).To(ContainSequence(
+				Info(Action("test.start")),
+				Info(Action("test.done")),
+			))
+		})
+
+		It("does not retain the contents of other readers", func() {
+			Expect(replayable).To(ContainSequence(Info(Action("test.start"))))
+			Expect(Replayable(log)).ToNot(ContainSequence(Info(Action("test.start"))))
+		})
+	})
+
+	Context("when the reader implements io.Seeker", func() {
+		var file *os.File
+
+		BeforeEach(func() {
+			var err error
+			file, err = os.CreateTemp("", "glager")
+			Expect(err).ToNot(HaveOccurred())
+
+			logger = lager.NewLogger("test")
+			logger.RegisterSink(lager.NewWriterSink(file, lager.DEBUG))
+			logger.Info("start")
+			logger.Info("done")
+
+			_, err = file.Seek(0, io.SeekStart)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			file.Close()
+			os.Remove(file.Name())
+		})
+
+		It("rewinds the reader after matching", func() {
+			Expect(file).ToNot(ContainSequence(Info(Action("test.missing"))))
+			Expect(file).To(ContainSequence(Info(Action("test.start")), Info(Action("test.done"))))
+
+			offset, err := file.Seek(0, io.SeekCurrent)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(offset).To(BeZero())
+		})
+
+		It("allows applying different matchers to the same file handle", func() {
+			Expect(file).To(HaveLogged(Info(Action("test.done"))))
+			Expect(file).To(ContainAll(Info(Action("test.done")), Info(Action("test.start"))))
+			Expect(file).To(HaveNoDuplicateEntries())
+
+			entries, err := Entries(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+		})
+
+		It("rewinds to the starting offset", func() {
+			reader := strings.NewReader(`{"message":"first"}` + "\n" + `{"message":"second"}` + "\n")
+			_, err := reader.Seek(20, io.SeekStart)
+			Expect(err).ToNot(HaveOccurred())
+
+			entries, err := Entries(reader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].Message).To(Equal("second"))
+			Expect(reader.Len()).To(Equal(21))
+		})
+	})
+})
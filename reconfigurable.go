@@ -0,0 +1,226 @@
+package glager
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/types"
+)
+
+// ReconfigurableLogger is a TestLogger that writes to a
+// lager.ReconfigurableSink. It keeps track of the level changes made through
+// SetMinLevel, which allows to assert that entries before and after a change
+// have been filtered appropriately.
+type ReconfigurableLogger struct {
+	*TestLogger
+	sink    *lager.ReconfigurableSink
+	lock    sync.Mutex
+	changes []levelChange
+}
+
+type levelChange struct {
+	offset int
+	level  lager.LogLevel
+}
+
+// NewReconfigurableLogger returns a new ReconfigurableLogger using the given
+// initial minimum log level.
+func NewReconfigurableLogger(component string, minLevel lager.LogLevel) *ReconfigurableLogger {
+	buf := gbytes.NewBuffer()
+	sink := lager.NewReconfigurableSink(lager.NewWriterSink(buf, lager.DEBUG), minLevel)
+	log := lager.NewLogger(component)
+	log.RegisterSink(sink)
+
+	return &ReconfigurableLogger{
+		TestLogger: &TestLogger{log, buf},
+		sink:       sink,
+		changes:    []levelChange{{0, minLevel}},
+	}
+}
+
+// SetMinLevel changes the minimum log level of the underlying sink and
+// remembers the position of the change inside the log.
+func (l *ReconfigurableLogger) SetMinLevel(level lager.LogLevel) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.sink.SetMinLevel(level)
+	l.changes = append(l.changes, levelChange{
+		offset: bytes.Count(l.buf.Contents(), []byte("\n")),
+		level:  level,
+	})
+}
+
+// GetMinLevel returns the current minimum log level of the underlying sink.
+func (l *ReconfigurableLogger) GetMinLevel() lager.LogLevel {
+	return l.sink.GetMinLevel()
+}
+
+// segments splits the given entries at the recorded level changes.
+func (l *ReconfigurableLogger) segments(entries logEntries) ([]logEntries, []lager.LogLevel) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	segments := make([]logEntries, len(l.changes))
+	levels := make([]lager.LogLevel, len(l.changes))
+
+	for i, change := range l.changes {
+		end := len(entries)
+		if i+1 < len(l.changes) && l.changes[i+1].offset < end {
+			end = l.changes[i+1].offset
+		}
+
+		start := change.offset
+		if start > end {
+			start = end
+		}
+
+		segments[i] = entries[start:end]
+		levels[i] = change.level
+	}
+
+	return segments, levels
+}
+
+func parseReconfigurable(matcher string, actual interface{}) (*ReconfigurableLogger, []logEntries, []lager.LogLevel, error) {
+	logger, ok := actual.(*ReconfigurableLogger)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("%s must be passed a glager.ReconfigurableLogger. Got:\n%s", matcher, format.Object(actual, 1))
+	}
+
+	entries, err := parseEntries(matcher, logger)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	segments, levels := logger.segments(entries)
+	return logger, segments, levels, nil
+}
+
+type honorLevelsMatcher struct {
+	segment   int
+	level     lager.LogLevel
+	offending LogEntry
+}
+
+// HonorMinLevels checks that the entries of a ReconfigurableLogger honor the
+// minimum log level that was active at the time they were logged.
+func HonorMinLevels() types.GomegaMatcher {
+	return &honorLevelsMatcher{}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (hm *honorLevelsMatcher) Match(actual interface{}) (success bool, err error) {
+	_, segments, levels, err := parseReconfigurable("HonorMinLevels", actual)
+	if err != nil {
+		return false, err
+	}
+
+	for i, segment := range segments {
+		for _, entry := range segment {
+			if entry.LogLevel < levels[i] {
+				hm.segment, hm.level, hm.offending = i, levels[i], entry
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (hm *honorLevelsMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected entries after level change %d to honor minimum level %s, found\n%s",
+		hm.segment,
+		levelName(hm.level),
+		format.IndentString(hm.offending.String(), 1),
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (hm *honorLevelsMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return "Expected entries not to honor the minimum levels"
+}
+
+type levelChangeMatcher struct {
+	change  int
+	before  types.GomegaMatcher
+	after   types.GomegaMatcher
+	failed  string
+	failure string
+}
+
+// AroundLevelChange applies the before matcher to the entries logged right
+// before the n-th level change, i.e. since the previous change, and the after
+// matcher to the entries logged right after it, i.e. until the next change.
+// Level changes are counted starting at 1. Both matchers have to be satisfied.
+//
+// Example:
+//
+//	logger := NewReconfigurableLogger("test", lager.DEBUG)
+//	logger.Debug("verbose")
+//	logger.SetMinLevel(lager.ERROR)
+//	logger.Debug("silent")
+//
+//	Expect(logger).To(AroundLevelChange(1,
+//	  ContainSequence(Debug()),
+//	  Not(ContainSequence(Debug())),
+//	))
+func AroundLevelChange(n int, before, after types.GomegaMatcher) types.GomegaMatcher {
+	return &levelChangeMatcher{
+		change: n,
+		before: before,
+		after:  after,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (lm *levelChangeMatcher) Match(actual interface{}) (success bool, err error) {
+	_, segments, _, err := parseReconfigurable("AroundLevelChange", actual)
+	if err != nil {
+		return false, err
+	}
+
+	if lm.change < 1 || lm.change >= len(segments) {
+		return false, fmt.Errorf("AroundLevelChange expects level change %d, got %d level changes", lm.change, len(segments)-1)
+	}
+
+	lm.failed = ""
+
+	for _, side := range []struct {
+		name    string
+		matcher types.GomegaMatcher
+		entries logEntries
+	}{
+		{"before", lm.before, segments[lm.change-1]},
+		{"after", lm.after, segments[lm.change]},
+	} {
+		success, err := side.matcher.Match(side.entries)
+		if err != nil {
+			return false, err
+		}
+
+		if !success {
+			lm.failed = side.name
+			lm.failure = side.matcher.FailureMessage(side.entries)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (lm *levelChangeMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("For the entries %s level change %d:\n%s", lm.failed, lm.change, lm.failure)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (lm *levelChangeMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected the entries around level change %d not to match", lm.change)
+}
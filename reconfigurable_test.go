@@ -0,0 +1,88 @@
+package glager_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("ReconfigurableLogger", func() {
+	var logger *ReconfigurableLogger
+
+	BeforeEach(func() {
+		logger = NewReconfigurableLogger("test", lager.DEBUG)
+		logger.Debug("verbose")
+		logger.Info("info")
+
+		logger.SetMinLevel(lager.ERROR)
+		logger.Debug("silent")
+		logger.Info("silent")
+		logger.Error("failed", errors.New("some-error"))
+
+		logger.SetMinLevel(lager.INFO)
+		logger.Debug("silent")
+		logger.Info("info-again")
+	})
+
+	It("captures the output of the reconfigurable sink", func() {
+		Expect(logger.GetMinLevel()).To(Equal(lager.INFO))
+		Expect(logger).To(ContainSequence(
+			Debug(Action("test.verbose")),
+			Info(Action("test.info")),
+			Error(AnyErr, Action("test.failed")),
+			Info(Action("test.info-again")),
+		))
+		Expect(logger).ToNot(ContainSequence(Info(Action("test.silent"))))
+	})
+
+	Describe(".HonorMinLevels", func() {
+		It("matches entries honoring the active minimum levels", func() {
+			Expect(logger).To(HonorMinLevels())
+		})
+
+		It("does not match entries violating the active minimum level", func() {
+			logger.SetMinLevel(lager.FATAL)
+			logger.Buffer().Write([]byte(`{"timestamp":"1.0","source":"test","message":"test.bypass","log_level":1,"data":{}}` + "\n"))
+
+			matcher := HonorMinLevels()
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring("after level change 3 to honor minimum level fatal"))
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring("test.bypass"))
+		})
+
+		It("returns an error for other actuals", func() {
+			_, err := HonorMinLevels().Match(NewLogger("test"))
+			Expect(err).To(MatchError(ContainSubstring("HonorMinLevels must be passed a glager.ReconfigurableLogger")))
+		})
+	})
+
+	Describe(".AroundLevelChange", func() {
+		It("matches the entries before and after a level change", func() {
+			Expect(logger).To(AroundLevelChange(1,
+				ContainSequence(Debug(), Info()),
+				And(ContainSequence(Error(AnyErr)), Not(ContainSequence(Info()))),
+			))
+
+			Expect(logger).To(AroundLevelChange(2,
+				Not(ContainSequence(Info())),
+				ContainSequence(Info(Action("test.info-again"))),
+			))
+		})
+
+		It("reports which side of the level change did not match", func() {
+			matcher := AroundLevelChange(1, ContainSequence(Debug()), ContainSequence(Debug()))
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(HavePrefix("For the entries after level change 1:"))
+		})
+
+		It("returns an error for unknown level changes", func() {
+			_, err := AroundLevelChange(3, ContainSequence(), ContainSequence()).Match(logger)
+			Expect(err).To(MatchError("AroundLevelChange expects level change 3, got 2 level changes"))
+		})
+	})
+})
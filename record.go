@@ -0,0 +1,263 @@
+package glager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"gopkg.in/yaml.v3"
+)
+
+// The placeholders used by RecordYAML and RecordJSON for dynamic values.
+// Sequence specs match them using BeAUUID, BeAURL, and BeAnIP respectively.
+// PlaceholderAny is never recorded, it can be used in specs to match any value
+// other than null of a data key that has to be present.
+const (
+	PlaceholderUUID = "{{uuid}}"
+	PlaceholderURL  = "{{url}}"
+	PlaceholderIP   = "{{ip}}"
+	PlaceholderAny  = "{{any}}"
+)
+
+// PlaceholderRegexp returns a placeholder that makes sequence specs match a
+// string value against the given regular expression, e.g. {{regexp:^req-\d+$}}.
+func PlaceholderRegexp(pattern string) string {
+	return placeholderRegexpPrefix + pattern + placeholderSuffix
+}
+
+const (
+	placeholderRegexpPrefix = "{{regexp:"
+	placeholderSuffix       = "}}"
+)
+
+// unrecordedKeys are data keys that are not recorded since their values are
+// specific to a given run.
+var unrecordedKeys = map[string]bool{
+	"trace": true,
+}
+
+// Record parses the given actual and renders its entries as a ContainSequence
+// matcher using glager's DSL. Use it to bootstrap assertions for components
+// that already log extensively. Timestamps and stack traces are not recorded,
+// and data values that are UUIDs, URLs, or IP addresses are rendered as
+// BeAUUID, BeAURL, and BeAnIP respectively. The actual can be anything that is
+// accepted by the ContainSequence matcher.
+//
+// Example:
+//
+//	code, err := Record(logger)
+//	fmt.Println(code)
+func Record(actual interface{}) (string, error) {
+	entries, err := parseEntries("Record", actual)
+	if err != nil {
+		return "", err
+	}
+
+	lines := []string{"ContainSequence("}
+	for _, entry := range entries {
+		lines = append(lines, fmt.Sprintf("\t%s,", recordEntry(entry)))
+	}
+	lines = append(lines, ")")
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// RecordYAML parses the given actual and renders its entries as a sequence
+// fixture that can be loaded using SequenceFromFile. Just like Record, it does
+// not record timestamps and stack traces. Dynamic data values are rendered as
+// PlaceholderUUID, PlaceholderURL, and PlaceholderIP respectively.
+func RecordYAML(actual interface{}) ([]byte, error) {
+	specs, err := recordSpecs("RecordYAML", actual)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(specs)
+}
+
+// RecordJSON is like RecordYAML, but renders the entries as a JSON sequence
+// fixture that can be loaded using SequenceFromJSON. Use it together with
+// FindSequence to dump the entries that matched a sequence.
+//
+// Example:
+//
+//	matched, err := FindSequence(logger, Info(Action("app.start")), Info(Action("app.ready")))
+//	fixture, err := RecordJSON(matched)
+func RecordJSON(actual interface{}) ([]byte, error) {
+	specs, err := recordSpecs("RecordJSON", actual)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(specs, "", "  ")
+}
+
+// recordSpecs parses the given actual and converts its entries into specs.
+func recordSpecs(matcher string, actual interface{}) ([]SpecEntry, error) {
+	entries, err := parseEntries(matcher, actual)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make([]SpecEntry, len(entries))
+	for i, entry := range entries {
+		spec := SpecEntry{
+			Level:   levelName(entry.LogLevel),
+			Source:  entry.Source,
+			Message: entry.Message,
+		}
+
+		for key, value := range entry.Data {
+			if !recordKey(key) {
+				continue
+			}
+
+			if key == "error" && (entry.LogLevel == lager.ERROR || entry.LogLevel == lager.FATAL) {
+				if msg, ok := value.(string); ok {
+					spec.Error = msg
+					continue
+				}
+			}
+
+			if spec.Data == nil {
+				spec.Data = map[string]interface{}{}
+			}
+
+			if placeholder, dynamic := dynamicValue(value); dynamic {
+				value = placeholder
+			}
+			spec.Data[key] = value
+		}
+
+		specs[i] = spec
+	}
+
+	return specs, nil
+}
+
+func recordKey(key string) bool {
+	return !unrecordedKeys[key]
+}
+
+func recordEntry(entry LogEntry) string {
+	var name string
+	var args []string
+
+	data := lager.Data{}
+	for key, value := range entry.Data {
+		if recordKey(key) {
+			data[key] = value
+		}
+	}
+
+	switch entry.LogLevel {
+	case lager.DEBUG:
+		name = "Debug"
+	case lager.INFO:
+		name = "Info"
+	case lager.ERROR, lager.FATAL:
+		name = "Error"
+		if entry.LogLevel == lager.FATAL {
+			name = "Fatal"
+		}
+
+		args = []string{"AnyErr"}
+		if msg, ok := data["error"].(string); ok {
+			args = []string{fmt.Sprintf("errors.New(%q)", msg)}
+			delete(data, "error")
+		}
+	default:
+		name, args = "Entry", []string{fmt.Sprintf("%d", entry.LogLevel)}
+	}
+
+	if entry.Source != "" {
+		args = append(args, fmt.Sprintf("Source(%q)", entry.Source))
+	}
+
+	if entry.Message != "" {
+		args = append(args, fmt.Sprintf("Message(%q)", entry.Message))
+	}
+
+	if len(data) > 0 {
+		kv := []string{}
+		for _, key := range sortedKeys(data) {
+			kv = append(kv, strconv.Quote(key), recordValue(data[key]))
+		}
+		args = append(args, fmt.Sprintf("Data(%s)", strings.Join(kv, ", ")))
+	}
+
+	return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+}
+
+// recordValue renders the given decoded JSON value as a Go literal.
+func recordValue(value interface{}) string {
+	if placeholder, dynamic := dynamicValue(value); dynamic {
+		switch placeholder {
+		case PlaceholderUUID:
+			return "BeAUUID()"
+		case PlaceholderURL:
+			return "BeAURL()"
+		case PlaceholderIP:
+			return "BeAnIP()"
+		}
+	}
+
+	switch x := value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	case []interface{}:
+		values := make([]string, len(x))
+		for i, v := range x {
+			values[i] = recordValue(v)
+		}
+		return fmt.Sprintf("[]interface{}{%s}", strings.Join(values, ", "))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for key := range x {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		fields := make([]string, len(keys))
+		for i, key := range keys {
+			fields[i] = fmt.Sprintf("%q: %s", key, recordValue(x[key]))
+		}
+		return fmt.Sprintf("map[string]interface{}{%s}", strings.Join(fields, ", "))
+	default:
+		return fmt.Sprintf("%#v", x)
+	}
+}
+
+// dynamicValue returns the placeholder for values that are likely to differ
+// between runs, i.e. UUIDs, URLs, and IP addresses.
+func dynamicValue(value interface{}) (string, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return "", false
+	}
+
+	if uuidRegexp.MatchString(s) {
+		return PlaceholderUUID, true
+	}
+
+	if net.ParseIP(s) != nil {
+		return PlaceholderIP, true
+	}
+
+	if u, err := url.Parse(s); err == nil && u.Scheme != "" && u.Host != "" {
+		return PlaceholderURL, true
+	}
+
+	return "", false
+}
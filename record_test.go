@@ -0,0 +1,120 @@
+package glager_test
+
+import (
+	"bytes"
+	"errors"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("recording", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("app")
+		logger.Info("start", lager.Data{
+			"guid": "6f2d3a4e-1b5c-4d7e-9f80-123456789abc",
+			"port": 8080,
+			"url":  "https://example.com/api",
+		})
+		logger.Debug("peer", lager.Data{"ip": "10.0.0.1", "tags": []string{"a"}, "opts": map[string]bool{"tls": true}})
+		logger.Error("failed", errors.New("boom"))
+	})
+
+	Describe(".Record", func() {
+		It("renders the entries as a ContainSequence matcher", func() {
+			Expect(Record(logger)).To(Equal(`ContainSequence(
+	Info(Source("app"), Message("app.start"), Data("guid", BeAUUID(), "port", 8080, "url", BeAURL())),
+	Debug(Source("app"), Message("app.peer"), Data("ip", BeAnIP(), "opts", map[string]interface{}{"tls": true}, "tags", []interface{}{"a"})),
+	Error(errors.New("boom"), Source("app"), Message("app.failed")),
+)`))
+		})
+
+		It("renders the code that matches the log", func() {
+			Expect(logger).To(ContainSequence(
+				Info(Source("app"), Message("app.start"), Data("guid", BeAUUID(), "port", 8080, "url", BeAURL())),
+				Debug(Source("app"), Message("app.peer"), Data("ip", BeAnIP(), "opts", map[string]interface{}{"tls": true}, "tags", []interface{}{"a"})),
+				Error(errors.New("boom"), Source("app"), Message("app.failed")),
+			))
+		})
+
+		It("returns an error for invalid actuals", func() {
+			_, err := Record(42)
+			Expect(err).To(MatchError(HavePrefix("Record must be passed")))
+		})
+	})
+
+	Describe(".RecordJSON", func() {
+		It("renders a fixture that matches the log", func() {
+			recorded, err := RecordJSON(logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(recorded)).To(ContainSubstring(`"guid": "{{uuid}}"`))
+			Expect(string(recorded)).ToNot(ContainSubstring("timestamp"))
+
+			sequence, err := SequenceFromJSON(bytes.NewReader(recorded))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logger).To(ContainSequence(sequence...))
+		})
+
+		It("dumps a matched sequence", func() {
+			matched, err := FindSequence(logger, Info(), Error(AnyErr))
+			Expect(err).ToNot(HaveOccurred())
+
+			recorded, err := RecordJSON(matched)
+			Expect(err).ToNot(HaveOccurred())
+
+			sequence, err := SequenceFromJSON(bytes.NewReader(recorded))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sequence).To(HaveLen(2))
+			Expect(logger).To(ContainSequence(sequence...))
+		})
+
+		It("returns an error for invalid actuals", func() {
+			_, err := RecordJSON(42)
+			Expect(err).To(MatchError(HavePrefix("RecordJSON must be passed")))
+		})
+	})
+
+	Describe(".RecordYAML", func() {
+		It("renders the entries as a sequence fixture", func() {
+			recorded, err := RecordYAML(logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(recorded)).To(ContainSubstring("guid: '{{uuid}}'"))
+			Expect(string(recorded)).To(ContainSubstring("ip: '{{ip}}'"))
+			Expect(string(recorded)).To(ContainSubstring("error: boom"))
+			Expect(string(recorded)).ToNot(ContainSubstring("timestamp"))
+		})
+
+		It("renders a fixture that matches the log", func() {
+			recorded, err := RecordYAML(logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			sequence, err := ParseSequenceYAML(recorded)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logger).To(ContainSequence(sequence...))
+		})
+
+		It("templates dynamic values", func() {
+			recorded, err := RecordYAML(logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			sequence, err := ParseSequenceYAML(recorded)
+			Expect(err).ToNot(HaveOccurred())
+
+			other := NewLogger("app")
+			other.Info("start", lager.Data{
+				"guid": "00000000-1b5c-4d7e-9f80-123456789abc",
+				"port": 8080,
+				"url":  "http://localhost:8080",
+			})
+			other.Debug("peer", lager.Data{"ip": "::1", "tags": []string{"a"}, "opts": map[string]bool{"tls": true}})
+			other.Error("failed", errors.New("boom"))
+			Expect(other).To(ContainSequence(sequence...))
+		})
+	})
+})
@@ -0,0 +1,77 @@
+package glager
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// RemoteLog is a log served via HTTP(S), e.g. by a log endpoint of a service or
+// as a CI artifact. It can be passed to any of the matchers and is fetched
+// every time it is matched, which makes it suitable for Eventually. A
+// *url.URL can be passed to the matchers as well.
+//
+// Example:
+//
+//	Eventually(RemoteLog{URL: "https://ci.example.com/artifacts/app.log"}).Should(ContainSequence(
+//	  Info(Action("app.start")),
+//	))
+type RemoteLog struct {
+	// URL is the http or https URL of the log.
+	URL string
+
+	// Header is added to the request, e.g. to authenticate against a log
+	// endpoint.
+	Header http.Header
+
+	// Client is the client used to fetch the log. If it is nil,
+	// http.DefaultClient is used.
+	Client *http.Client
+}
+
+// fetch returns a reader for the contents of the remote log.
+func (l RemoteLog) fetch() (io.Reader, error) {
+	u, err := url.Parse(l.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("Invalid scheme of remote log %s. Want http or https.", l.URL)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, l.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range l.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("Failed to fetch remote log %s: %s.", l.URL, resp.Status)
+	}
+
+	contents, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(contents), nil
+}
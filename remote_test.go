@@ -0,0 +1,95 @@
+package glager_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("remote logs", func() {
+	var (
+		server   *httptest.Server
+		contents string
+		requests []*http.Request
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		contents = `{"timestamp":"1.0","source":"app","message":"app.start","log_level":1,"data":{}}` + "\n"
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+			if r.URL.Path != "/app.log" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(contents))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("matches a RemoteLog", func() {
+		Expect(RemoteLog{URL: server.URL + "/app.log"}).To(ContainSequence(Info(Action("app.start"))))
+	})
+
+	It("matches a *url.URL", func() {
+		u, err := url.Parse(server.URL + "/app.log")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(u).To(ContainSequence(Info(Action("app.start"))))
+	})
+
+	It("fetches the log every time it is matched", func() {
+		log := RemoteLog{URL: server.URL + "/app.log"}
+		Expect(log).ToNot(ContainSequence(Info(Action("app.done"))))
+
+		contents += `{"timestamp":"2.0","source":"app","message":"app.done","log_level":1,"data":{}}` + "\n"
+		Expect(log).To(ContainSequence(Info(Action("app.start")), Info(Action("app.done"))))
+		Expect(requests).To(HaveLen(2))
+	})
+
+	It("sends the configured headers", func() {
+		log := RemoteLog{URL: server.URL + "/app.log", Header: http.Header{
+			"Authorization": []string{"Bearer token"},
+			"X-Tenant":      []string{"acme"},
+		}}
+
+		Expect(log).To(ContainSequence(Info()))
+		Expect(requests[0].Header.Get("Authorization")).To(Equal("Bearer token"))
+		Expect(requests[0].Header.Get("X-Tenant")).To(Equal("acme"))
+	})
+
+	It("uses the configured client", func() {
+		var used bool
+		client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			used = true
+			return http.DefaultTransport.RoundTrip(req)
+		})}
+
+		Expect(RemoteLog{URL: server.URL + "/app.log", Client: client}).To(ContainSequence(Info()))
+		Expect(used).To(BeTrue())
+	})
+
+	It("returns an error for unsuccessful responses", func() {
+		_, err := ContainSequence(Info()).Match(RemoteLog{URL: server.URL + "/missing.log"})
+		Expect(err).To(MatchError(HavePrefix("Failed to fetch remote log " + server.URL + "/missing.log: 404")))
+	})
+
+	It("returns an error for unsupported schemes", func() {
+		_, err := ContainSequence(Info()).Match(RemoteLog{URL: "file:///var/log/app.log"})
+		Expect(err).To(MatchError("Invalid scheme of remote log file:///var/log/app.log. Want http or https."))
+	})
+})
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
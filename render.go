@@ -0,0 +1,109 @@
+package glager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/onsi/gomega/format"
+)
+
+// maxCompactLength is the length up to which log entries are rendered on a
+// single line in Gomega failure messages.
+const maxCompactLength = 120
+
+// highlightMarker marks the lines of fields that do not match an expected
+// entry. Markers are used instead of colors to keep CI logs readable.
+const highlightMarker = ">"
+
+// render returns a multi-line representation of the log entry with its data
+// and fields as indented JSON. Lines of the given fields are marked, fields
+// are identified as "level", "source", "message", "timestamp", "data.<key>",
+// or "fields.<key>".
+func (e LogEntry) render(highlighted map[string]bool) string {
+	lines := []string{"glager.LogEntry{"}
+
+	add := func(field string, depth int, line string) {
+		indent := strings.Repeat(format.Indent, depth)
+		if highlighted[field] {
+			indent = highlightMarker + indent[len(highlightMarker):]
+		}
+		lines = append(lines, indent+strings.ReplaceAll(line, "\n", "\n"+strings.Repeat(format.Indent, depth)))
+	}
+
+	add("timestamp", 1, fmt.Sprintf("Timestamp: %q,", e.Timestamp))
+	add("source", 1, fmt.Sprintf("Source:    %q,", e.Source))
+	add("message", 1, fmt.Sprintf("Message:   %q,", e.Message))
+	add("level", 1, fmt.Sprintf("LogLevel:  %s,", levelName(e.LogLevel)))
+
+	for _, group := range []struct {
+		name   string
+		title  string
+		values map[string]interface{}
+	}{
+		{"fields", "Fields", e.Fields},
+		{"data", "Data", e.Data},
+	} {
+		if len(group.values) == 0 {
+			if group.name == "data" {
+				add("data", 1, "Data:      {},")
+			}
+			continue
+		}
+
+		add(group.name, 1, group.title+": {")
+		for _, key := range sortedKeys(group.values) {
+			add(group.name+"."+key, 2, fmt.Sprintf("%q: %s,", key, indentedJSON(group.values[key])))
+		}
+		add(group.name, 1, "},")
+	}
+
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n")
+}
+
+// indentedJSON renders a data value as indented JSON with sorted keys.
+func indentedJSON(value interface{}) string {
+	decoded, err := canonical(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	encoded, err := json.MarshalIndent(decoded, "", format.Indent)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(encoded)
+}
+
+// truncate shortens the given representation to format.MaxLength, unless it is
+// set to 0.
+func truncate(rendered string) string {
+	if format.MaxLength <= 0 || len(rendered) <= format.MaxLength {
+		return rendered
+	}
+
+	n := format.MaxLength
+	for n > 0 && !utf8.ValidString(rendered[:n]) {
+		n--
+	}
+
+	return fmt.Sprintf("%s... (%d bytes, see format.MaxLength)", rendered[:n], len(rendered))
+}
+
+// highlightedFields returns the fields of an entry that are named in the
+// given differences as reported by fieldDiffs.
+func highlightedFields(diffs []string) map[string]bool {
+	highlighted := map[string]bool{}
+	for _, diff := range diffs {
+		field := strings.SplitN(diff, ":", 2)[0]
+		if path := strings.TrimPrefix(field, "data."); path != field {
+			if keys := strings.FieldsFunc(path, func(r rune) bool { return r == '.' || r == '[' }); len(keys) > 0 {
+				field = "data." + keys[0]
+			}
+		}
+		highlighted[field] = true
+	}
+	return highlighted
+}
@@ -0,0 +1,71 @@
+package glager_test
+
+import (
+	"errors"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("rendering entries", func() {
+	var entry LogEntry
+
+	BeforeEach(func() {
+		entry = LogEntry{
+			Timestamp: "1",
+			Source:    "test",
+			Message:   "test.request",
+			LogLevel:  lager.INFO,
+			Data: lager.Data{
+				"path":    "/v2/apps",
+				"headers": map[string]interface{}{"accept": "application/json", "user-agent": strings.Repeat("x", 80)},
+			},
+		}
+	})
+
+	It("renders long entries on multiple lines with indented data", func() {
+		rendered := format.Object([]LogEntry{entry}, 0)
+		Expect(rendered).To(ContainSubstring("glager.LogEntry{\n"))
+		Expect(rendered).To(ContainSubstring(`Message:   "test.request",`))
+		Expect(rendered).To(ContainSubstring(`"headers": {`))
+		Expect(rendered).To(ContainSubstring(`"accept": "application/json",`))
+		Expect(rendered).To(ContainSubstring(strings.Repeat("x", 80)))
+	})
+
+	It("truncates long entries to format.MaxLength", func() {
+		defer func(maxLength int) { format.MaxLength = maxLength }(format.MaxLength)
+		format.MaxLength = 100
+
+		rendered := entry.GomegaString()
+		Expect(rendered).To(ContainSubstring(`"test.request"`))
+		Expect(rendered).To(MatchRegexp(`\.\.\. \(\d+ bytes, see format.MaxLength\)`))
+		Expect(rendered).ToNot(ContainSubstring("user-agent"))
+	})
+
+	It("renders entries compactly if format.UseStringerRepresentation is set", func() {
+		defer func() { format.UseStringerRepresentation = false }()
+		format.UseStringerRepresentation = true
+
+		Expect(format.Object([]LogEntry{entry}, 0)).To(ContainSubstring(`glager.LogEntry{Timestamp: "1", Source: "test"`))
+	})
+
+	It("highlights the fields of the closest candidate that do not match", func() {
+		logger := NewLogger("test")
+		logger.Error("request", errors.New("boom"), lager.Data{"path": "/v2/apps"})
+
+		matcher := ContainSequence(Info(Action("test.request"), Data("path", "/v3/apps")))
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		message := matcher.FailureMessage(logger)
+		Expect(message).To(ContainSubstring("\n\t>   LogLevel:  error,"))
+		Expect(message).To(ContainSubstring("\n\t        \"error\": \"boom\","))
+		Expect(message).To(ContainSubstring("\n\t>       \"path\": \"/v2/apps\","))
+		Expect(message).To(ContainSubstring("\n\t    Message:   \"test.request\","))
+	})
+})
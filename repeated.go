@@ -0,0 +1,76 @@
+package glager
+
+import "fmt"
+
+// repetition marks an expected entry as one of the copies of an entry passed
+// to Repeated.
+type repetition struct {
+	n     int
+	times int
+}
+
+// Repeated specifies that the given log entry has to appear exactly n times at
+// the given position of a sequence. The entries do not have to be contiguous,
+// but no further entry matching the given one may appear before the entry
+// that follows it in the sequence, or before the end of the log if it is the
+// last one. Repeated entries count as n entries of the sequence, e.g. when
+// reporting indices. ContainAll and MockLogger treat them as n copies of the
+// entry, without requiring exactly n of them.
+//
+// Example:
+//
+//	Expect(logger).To(ContainSequence(
+//	  Repeated(3, Info(Action("test.retrying"))),
+//	  Error(AnyErr, Action("test.gave-up")),
+//	))
+func Repeated(n int, entry ExpectedEntry) repeatedEntry {
+	if n < 1 {
+		panic(fmt.Errorf("Repeated must be passed a positive count. Got %d.", n))
+	}
+
+	entry.repeated = repetition{times: n}
+	return repeatedEntry(entry)
+}
+
+// repeatedEntry is an entry passed to Repeated.
+type repeatedEntry logEntry
+
+func (entry repeatedEntry) sequenceEntry() logEntry { return logEntry(entry) }
+func (entry repeatedEntry) requiredEntry()          {}
+func (entry repeatedEntry) unorderedEntry()         {}
+
+// expandRepetitions replaces every repeated entry of the given sequence by
+// the according number of copies.
+func expandRepetitions(sequence []logEntry) []logEntry {
+	expanded := make([]logEntry, 0, len(sequence))
+	for _, entry := range sequence {
+		if entry.repeated.times == 0 || entry.repeated.n > 0 {
+			expanded = append(expanded, entry)
+			continue
+		}
+
+		for n := 1; n <= entry.repeated.times; n++ {
+			entry.repeated.n = n
+			expanded = append(expanded, entry)
+		}
+	}
+	return expanded
+}
+
+// lastRepetition reports whether the entry is the last copy of a repeated
+// entry, i.e. whether no further entries matching it may follow.
+func (entry logEntry) lastRepetition() bool {
+	return entry.repeated.times > 0 && entry.repeated.n == entry.repeated.times
+}
+
+// firstRepetition reports whether the entry is the first copy of a repeated
+// entry.
+func (entry logEntry) firstRepetition() bool {
+	return entry.repeated.times > 0 && entry.repeated.n == 1
+}
+
+// contain reports whether any of the entries matches the expected entry.
+func (entries logEntries) contain(expected logEntry) (bool, error) {
+	_, found, err := entries.indexOf(expected)
+	return found, err
+}
@@ -0,0 +1,125 @@
+package glager_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".Repeated", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		logger.Info("starting")
+	})
+
+	logRetries := func(n int) {
+		for i := 0; i < n; i++ {
+			logger.Info("retrying")
+			logger.Debug("waiting")
+		}
+		logger.Error("gave-up", errors.New("boom"))
+	}
+
+	It("matches an entry repeated exactly n times", func() {
+		logRetries(3)
+
+		Expect(logger).To(ContainSequence(
+			Repeated(3, Info(Action("test.retrying"))),
+			Error(AnyErr, Action("test.gave-up")),
+		))
+	})
+
+	It("does not match an entry repeated fewer times", func() {
+		logRetries(2)
+
+		Expect(logger).ToNot(ContainSequence(
+			Repeated(3, Info(Action("test.retrying"))),
+			Error(AnyErr, Action("test.gave-up")),
+		))
+	})
+
+	It("does not match an entry repeated more often", func() {
+		logRetries(4)
+
+		Expect(logger).ToNot(ContainSequence(
+			Repeated(3, Info(Action("test.retrying"))),
+			Error(AnyErr, Action("test.gave-up")),
+		))
+	})
+
+	It("does not match an entry repeated more often at the end of the sequence", func() {
+		logRetries(4)
+
+		Expect(logger).To(ContainSequence(Repeated(4, Info(Action("test.retrying")))))
+		Expect(logger).ToNot(ContainSequence(Repeated(3, Info(Action("test.retrying")))))
+	})
+
+	It("ignores further entries before the previous entry of the sequence", func() {
+		logger.Info("retrying")
+		logRetries(2)
+
+		Expect(logger).To(ContainSequence(
+			Info(Action("test.retrying")),
+			Repeated(2, Info(Action("test.retrying"))),
+			Error(AnyErr),
+		))
+	})
+
+	It("counts as n entries of the sequence", func() {
+		logRetries(2)
+
+		matched, err := ScanSequence(mustEntries(logger), Repeated(2, Info(Action("test.retrying"))), Error(AnyErr))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(matched).To(Equal([]int{1, 3, 5}))
+	})
+
+	It("reports the repeated entry as blocking", func() {
+		logRetries(4)
+
+		matcher := ContainSequence(Repeated(3, Info(Action("test.retrying"))), Error(AnyErr))
+		Expect(matcher.Match(logger)).To(BeFalse())
+		Expect(matcher.FailureMessage(logger)).To(ContainSubstring(
+			`2: Repeated(3, Info(Message("test.retrying"))) (3 of 3)`,
+		))
+	})
+
+	It("panics if the count is not positive", func() {
+		Expect(func() { Repeated(0, Info()) }).To(Panic())
+	})
+
+	It("cannot be passed to functions and matchers that match single entries", func() {
+		var entry interface{} = Repeated(2, Info())
+
+		_, unordered := entry.(UnorderedEntry)
+		Expect(unordered).To(BeTrue())
+
+		_, single := entry.(ExpectedEntry)
+		Expect(single).To(BeFalse())
+	})
+
+	Context("when used with ContainExactSequence", func() {
+		BeforeEach(func() {
+			logger.Info("retrying")
+			logger.Info("retrying")
+			logger.Info("retrying")
+			logger.Info("gave-up")
+		})
+
+		It("requires exactly n contiguous entries", func() {
+			Expect(logger).To(ContainExactSequence(Repeated(3, Info(Action("test.retrying"))), Info(Action("test.gave-up"))))
+			Expect(logger).ToNot(ContainExactSequence(Repeated(2, Info(Action("test.retrying"))), Info(Action("test.gave-up"))))
+			Expect(logger).ToNot(ContainExactSequence(Info(Action("test.starting")), Repeated(2, Info(Action("test.retrying")))))
+		})
+	})
+})
+
+func mustEntries(actual interface{}) []LogEntry {
+	entries, err := Entries(actual)
+	Expect(err).ToNot(HaveOccurred())
+	return entries
+}
@@ -0,0 +1,65 @@
+package glager
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// Replay parses the given actual and writes its entries to the given sink,
+// preserving the time gaps between them. The gaps are multiplied by the given
+// scale, i.e. a scale of 0.5 replays the log twice as fast, a scale of 0
+// replays it without any delay. The entries retain their original timestamps.
+// Use it to test components that consume live log streams, e.g. alerting
+// rules. The actual can be anything that is accepted by the ContainSequence
+// matcher.
+//
+// Example:
+//
+//	go Replay(recorded, sink, 0.1)
+//	Eventually(alerts).Should(Receive())
+func Replay(actual interface{}, sink lager.Sink, scale float64) error {
+	if scale < 0 {
+		return fmt.Errorf("Invalid scale %v. Scale must not be negative.", scale)
+	}
+
+	entries, err := parseEntries("Replay", actual)
+	if err != nil {
+		return err
+	}
+
+	times := make([]time.Time, len(entries))
+	for i, entry := range entries {
+		if times[i], err = entry.Time(); err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	for i, entry := range entries {
+		if i > 0 {
+			offset := time.Duration(float64(times[i].Sub(times[0])) * scale)
+			if wait := time.Until(start.Add(offset)); wait > 0 {
+				time.Sleep(wait)
+			}
+		}
+
+		sink.Log(lager.LogFormat{
+			Timestamp: entry.Timestamp,
+			Source:    entry.Source,
+			Message:   entry.Message,
+			LogLevel:  entry.LogLevel,
+			Data:      entry.Data,
+		})
+	}
+
+	return nil
+}
+
+// ReplayTo replays the entries of the given actual just like Replay does, but
+// writes them to the given writer in lager's JSON format.
+func ReplayTo(actual interface{}, writer io.Writer, scale float64) error {
+	return Replay(actual, lager.NewWriterSink(writer, lager.DEBUG), scale)
+}
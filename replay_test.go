@@ -0,0 +1,81 @@
+package glager_test
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+type timedSink struct {
+	times   []time.Time
+	entries []lager.LogFormat
+}
+
+func (s *timedSink) Log(log lager.LogFormat) {
+	s.times = append(s.times, time.Now())
+	s.entries = append(s.entries, log)
+}
+
+var _ = Describe("replaying", func() {
+	var recorded *bytes.Reader
+
+	BeforeEach(func() {
+		recorded = bytes.NewReader([]byte(strings.Join([]string{
+			`{"timestamp":"100.000000000","source":"app","message":"app.start","log_level":1,"data":{"i":1}}`,
+			`{"timestamp":"100.500000000","source":"app","message":"app.request","log_level":0,"data":{}}`,
+			`{"timestamp":"101.000000000","source":"app","message":"app.failed","log_level":2,"data":{"error":"boom"}}`,
+		}, "\n") + "\n"))
+	})
+
+	Describe(".Replay", func() {
+		It("writes the entries to the sink preserving the scaled time gaps", func() {
+			sink := &timedSink{}
+			Expect(Replay(recorded, sink, 0.2)).To(Succeed())
+
+			Expect(sink.entries).To(HaveLen(3))
+			Expect(sink.entries[0].Timestamp).To(Equal("100.000000000"))
+			Expect(sink.entries[2].Data).To(HaveKeyWithValue("error", "boom"))
+
+			Expect(sink.times[1].Sub(sink.times[0])).To(BeNumerically("~", 100*time.Millisecond, 50*time.Millisecond))
+			Expect(sink.times[2].Sub(sink.times[0])).To(BeNumerically("~", 200*time.Millisecond, 50*time.Millisecond))
+		})
+
+		It("does not delay entries for a scale of 0", func() {
+			sink := &timedSink{}
+			start := time.Now()
+			Expect(Replay(recorded, sink, 0)).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically("<", 50*time.Millisecond))
+		})
+
+		It("returns an error for a negative scale", func() {
+			Expect(Replay(recorded, &timedSink{}, -1)).To(MatchError("Invalid scale -1. Scale must not be negative."))
+		})
+
+		It("returns an error for invalid timestamps", func() {
+			err := Replay([]LogEntry{{Timestamp: "yesterday"}}, &timedSink{}, 0)
+			Expect(err).To(MatchError(`Invalid timestamp "yesterday".`))
+		})
+	})
+
+	Describe(".ReplayTo", func() {
+		It("writes the entries to the writer", func() {
+			buffer := gbytes.NewBuffer()
+			Expect(ReplayTo(recorded, buffer, 0)).To(Succeed())
+
+			Expect(buffer).To(BeNDJSON())
+			Expect(buffer).To(ContainSequence(
+				Info(Action("app.start"), Data("i", 1)),
+				Debug(Action("app.request")),
+				Error(AnyErr, Action("app.failed")),
+			))
+		})
+	})
+})
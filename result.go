@@ -0,0 +1,78 @@
+package glager
+
+// MatchResult describes the outcome of matching a log against an expected
+// sequence of entries.
+type MatchResult struct {
+	// Success indicates whether the log contains the expected sequence.
+	Success bool
+
+	// Matched holds the indices of the log entries that matched the expected
+	// entries, in order. If the sequence has not been found, it only holds
+	// the indices of the entries that matched before matching diverged.
+	Matched []int
+
+	// Divergence is the index of the first expected entry that could not be
+	// found in the log, or -1 if the log contains the entire sequence.
+	Divergence int
+
+	// Offset is the byte offset right after the last matched entry, i.e. the
+	// number of bytes consumed to match the sequence, excluding the trailing
+	// newline. For logs wrapped using WithFormat, it refers to the raw lines
+	// of the wrapped log. It is 0 if no entry has been matched, and for logs
+	// that have no raw contents of their own, e.g. slices of entries, merged
+	// logs, or OTLP logs.
+	Offset int64
+
+	// Captures maps the names of the placeholders of MessagePatterns to the
+	// text they matched in the matched entries. If several entries capture
+	// the same name, the last one wins.
+	Captures map[string]string
+}
+
+// MatchSequence matches the given actual against the expected sequence, just
+// like the ContainSequence matcher, but returns a detailed MatchResult instead
+// of a plain boolean. The actual can be anything that is accepted by the
+// ContainSequence matcher. Use it to build custom behavior on top of match
+// outcomes.
+//
+// Example:
+//
+//	result, err := MatchSequence(log, Info(Action("test.start")), Info(Action("test.done")))
+//	Expect(err).ToNot(HaveOccurred())
+//	Expect(result.Matched).To(Equal([]int{0, 3}))
+func MatchSequence(actual interface{}, expectedSequence ...SequenceEntry) (MatchResult, error) {
+	entries, offsets, err := parseOffsets("MatchSequence", actual)
+	if err != nil {
+		return MatchResult{}, err
+	}
+
+	expected := compileSequence(sequenceOf(expectedSequence))
+
+	matched, err := entries.matchSequence(expected)
+	if err != nil {
+		return MatchResult{}, err
+	}
+
+	result := MatchResult{
+		Success:    len(matched) == len(expected),
+		Matched:    matched,
+		Divergence: -1,
+		Captures:   map[string]string{},
+	}
+
+	for n, i := range matched {
+		for name, value := range expected[n].placeholderValues(entries[i]) {
+			result.Captures[name] = value
+		}
+	}
+
+	if !result.Success {
+		result.Divergence = len(matched)
+	}
+
+	if len(matched) > 0 && offsets != nil {
+		result.Offset = offsets[matched[len(matched)-1]]
+	}
+
+	return result, nil
+}
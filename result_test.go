@@ -0,0 +1,87 @@
+package glager_test
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".MatchSequence", func() {
+	const (
+		first  = `{"timestamp":"1","source":"test","message":"test.start","log_level":1,"data":{}}`
+		second = `{"timestamp":"2","source":"test","message":"test.debug","log_level":0,"data":{}}`
+		third  = `{"timestamp":"3","source":"test","message":"test.done","log_level":1,"data":{}}`
+	)
+
+	var log string
+
+	BeforeEach(func() {
+		log = strings.Join([]string{first, second, third}, "\n") + "\n"
+	})
+
+	It("returns the indices of the matched entries", func() {
+		result, err := MatchSequence(strings.NewReader(log), Info(), Info())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Success).To(BeTrue())
+		Expect(result.Matched).To(Equal([]int{0, 2}))
+		Expect(result.Divergence).To(Equal(-1))
+		Expect(result.Offset).To(BeEquivalentTo(len(log) - 1))
+	})
+
+	It("returns where matching diverged", func() {
+		result, err := MatchSequence(strings.NewReader(log), Info(), Debug(), Debug())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Success).To(BeFalse())
+		Expect(result.Matched).To(Equal([]int{0, 1}))
+		Expect(result.Divergence).To(Equal(2))
+		Expect(result.Offset).To(BeEquivalentTo(len(first) + 1 + len(second)))
+	})
+
+	It("returns a zero offset if nothing matched", func() {
+		result, err := MatchSequence(strings.NewReader(log), Error(AnyErr))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Matched).To(BeEmpty())
+		Expect(result.Divergence).To(Equal(0))
+		Expect(result.Offset).To(BeZero())
+	})
+
+	It("ignores an entry that is still being written to a live log", func() {
+		buffer := gbytes.BufferWithBytes([]byte(first + "\n" + third[:10]))
+
+		result, err := MatchSequence(buffer, Info())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Success).To(BeTrue())
+		Expect(result.Offset).To(BeEquivalentTo(len(first)))
+	})
+
+	It("returns the offsets of the raw lines of formatted logs", func() {
+		stripPrefix := func(line []byte) ([]byte, error) {
+			return bytes.TrimPrefix(line, []byte("> ")), nil
+		}
+
+		result, err := MatchSequence(WithFormat(stripPrefix, strings.NewReader("> "+first+"\n> "+third+"\n")), Info(Action("test.done")))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Matched).To(Equal([]int{1}))
+		Expect(result.Offset).To(BeEquivalentTo(2*len("> ") + len(first) + 1 + len(third)))
+	})
+
+	It("returns a zero offset for logs without raw contents", func() {
+		entries, err := Entries(strings.NewReader(log))
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := MatchSequence(entries, Info(), Info())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Matched).To(Equal([]int{0, 2}))
+		Expect(result.Offset).To(BeZero())
+	})
+
+	It("returns an error for invalid actuals", func() {
+		_, err := MatchSequence(42)
+		Expect(err).To(MatchError(ContainSubstring("MatchSequence must be passed")))
+	})
+})
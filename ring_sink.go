@@ -0,0 +1,89 @@
+package glager
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// RingSink is a lager.Sink that retains only the most recent entries. It
+// implements ContentsProvider and can therefore be passed to any of the
+// matchers. Use it for long running tests, e.g. soak tests, that need to
+// assert recent behavior without growing memory unboundedly.
+type RingSink struct {
+	lock     sync.Mutex
+	minLevel lager.LogLevel
+	lines    [][]byte
+	next     int
+	full     bool
+	dropped  int
+}
+
+var _ lager.Sink = &RingSink{}
+
+// NewRingSink returns a sink that retains the last size entries at or above
+// the given minimum log level. It panics if size is not positive.
+func NewRingSink(size int, minLevel lager.LogLevel) *RingSink {
+	if size < 1 {
+		panic(fmt.Errorf("NewRingSink must be passed a positive size. Got %d.", size))
+	}
+
+	return &RingSink{
+		minLevel: minLevel,
+		lines:    make([][]byte, size),
+	}
+}
+
+// Log implements lager.Sink. Once the sink is full, every entry replaces the
+// oldest one.
+func (s *RingSink) Log(log lager.LogFormat) {
+	if log.LogLevel < s.minLevel {
+		return
+	}
+
+	line := append(log.ToJSON(), '\n')
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.full {
+		s.dropped++
+	}
+
+	s.lines[s.next] = line
+	s.next = (s.next + 1) % len(s.lines)
+	if s.next == 0 {
+		s.full = true
+	}
+}
+
+// Contents implements ContentsProvider. It returns the retained entries,
+// oldest first.
+func (s *RingSink) Contents() []byte {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	buf := &bytes.Buffer{}
+	if s.full {
+		for _, line := range s.lines[s.next:] {
+			buf.Write(line)
+		}
+	}
+
+	for _, line := range s.lines[:s.next] {
+		buf.Write(line)
+	}
+
+	return buf.Bytes()
+}
+
+// Dropped returns the number of entries that have been replaced by more
+// recent ones.
+func (s *RingSink) Dropped() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.dropped
+}
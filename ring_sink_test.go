@@ -0,0 +1,82 @@
+package glager_test
+
+import (
+	"fmt"
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("RingSink", func() {
+	var (
+		sink   *RingSink
+		logger lager.Logger
+	)
+
+	BeforeEach(func() {
+		sink = NewRingSink(3, lager.INFO)
+		logger = lager.NewLogger("test")
+		logger.RegisterSink(sink)
+	})
+
+	It("can be passed to the matchers", func() {
+		logger.Info("start")
+		logger.Info("done")
+
+		Expect(sink).To(ContainSequence(Info(Action("test.start")), Info(Action("test.done"))))
+		Expect(sink.Dropped()).To(BeZero())
+	})
+
+	It("retains the most recent entries only", func() {
+		for i := 0; i < 10; i++ {
+			logger.Info("tick", lager.Data{"i": i})
+		}
+
+		entries, err := Entries(sink)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(3))
+
+		Expect(sink).To(ContainSequence(
+			Info(Data("i", 7)),
+			Info(Data("i", 8)),
+			Info(Data("i", 9)),
+		))
+		Expect(sink).ToNot(ContainSequence(Info(Data("i", 6))))
+		Expect(sink.Dropped()).To(Equal(7))
+	})
+
+	It("honors the minimum log level", func() {
+		logger.Debug("noise")
+		logger.Info("start")
+
+		Expect(sink).ToNot(ContainSequence(Debug()))
+		Expect(sink).To(ContainSequence(Info(Action("test.start"))))
+	})
+
+	It("is safe for concurrent use", func() {
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer GinkgoRecover()
+
+				logger.Info(fmt.Sprintf("worker-%d", i))
+				Expect(sink).To(ContainSequence(Info()))
+			}(i)
+		}
+		wg.Wait()
+
+		Expect(Entries(sink)).To(HaveLen(3))
+		Expect(sink.Dropped()).To(Equal(7))
+	})
+
+	It("panics for a non-positive size", func() {
+		Expect(func() { NewRingSink(0, lager.DEBUG) }).To(Panic())
+	})
+})
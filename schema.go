@@ -0,0 +1,126 @@
+package glager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// DataMatchingSchema specifies a JSON Schema document that the data of a given
+// log entry has to conform to. The function panics if the schema is invalid.
+//
+// Example:
+//
+//	Info(DataMatchingSchema(`{
+//	  "type": "object",
+//	  "required": ["guid"],
+//	  "properties": {"guid": {"type": "string"}}
+//	}`))
+func DataMatchingSchema(schema string) Option {
+	s := mustCompileSchema(schema)
+
+	return withCheck("data matching JSON schema", func(actual LogEntry) (bool, error) {
+		result, err := s.Validate(gojsonschema.NewGoLoader(actual.Data))
+		if err != nil {
+			return false, err
+		}
+		return result.Valid(), nil
+	})
+}
+
+type schemaMatcher struct {
+	schema    *gojsonschema.Schema
+	filters   []logEntry
+	validated int
+	invalid   *LogEntry
+	errors    []string
+}
+
+// HaveDataMatchingSchema checks that the data of every log entry matching any
+// of the given entries conforms to the given JSON Schema document. If no
+// entries are given, the data of every entry in the log is validated. The
+// function panics if the schema is invalid.
+//
+// Use it to enforce logging contracts, e.g. that every "api.request" entry
+// carries a well-formed request description.
+//
+// Example:
+//
+//	Expect(logger).To(HaveDataMatchingSchema(
+//	  requestSchema,
+//	  Info(Action("api.request")),
+//	))
+func HaveDataMatchingSchema(schema string, entries ...logEntry) types.GomegaMatcher {
+
+	return &schemaMatcher{
+		schema:  mustCompileSchema(schema),
+		filters: entries,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (sm *schemaMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("HaveDataMatchingSchema", actual)
+	if err != nil {
+		return false, err
+	}
+
+	entries, err = entries.filter(sm.filters)
+	if err != nil {
+		return false, err
+	}
+
+	sm.validated = len(entries)
+	sm.invalid = nil
+	sm.errors = nil
+
+	for i := range entries {
+		result, err := sm.schema.Validate(gojsonschema.NewGoLoader(entries[i].Data))
+		if err != nil {
+			return false, err
+		}
+
+		if !result.Valid() {
+			sm.invalid = &entries[i]
+			for _, e := range result.Errors() {
+				sm.errors = append(sm.errors, e.String())
+			}
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (sm *schemaMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected data of log entry\n%s\nto match JSON schema:\n\t%s",
+		format.IndentString(sm.invalid.String(), 1),
+		strings.Join(sm.errors, "\n\t"),
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (sm *schemaMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected data of at least one of %d validated log entries not to match JSON schema",
+		sm.validated,
+	)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (sm *schemaMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+func mustCompileSchema(schema string) *gojsonschema.Schema {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(schema))
+	if err != nil {
+		panic(fmt.Errorf("Invalid JSON schema: %s", err))
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+package glager_test
+
+import (
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("JSON schema validation", func() {
+	const schema = `{
+		"type": "object",
+		"required": ["guid", "status"],
+		"properties": {
+			"guid": {"type": "string"},
+			"status": {"type": "integer"}
+		}
+	}`
+
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("api")
+		logger.Info("request", lager.Data{"guid": "some-guid", "status": 200})
+		logger.Info("request", lager.Data{"guid": "other-guid", "status": 404})
+		logger.Debug("cache", lager.Data{"hit": true})
+	})
+
+	Describe(".DataMatchingSchema", func() {
+		It("matches an entry with conforming data", func() {
+			Expect(logger).To(ContainSequence(Info(DataMatchingSchema(schema))))
+		})
+
+		It("does not match an entry with non-conforming data", func() {
+			Expect(logger).ToNot(ContainSequence(Debug(DataMatchingSchema(schema))))
+		})
+
+		It("panics for an invalid schema", func() {
+			Expect(func() { DataMatchingSchema(`{"type": 42}`) }).To(Panic())
+		})
+	})
+
+	Describe(".HaveDataMatchingSchema", func() {
+		It("matches if all filtered entries conform", func() {
+			Expect(logger).To(HaveDataMatchingSchema(schema, Info(Action("api.request"))))
+		})
+
+		It("validates all entries if no filter is given", func() {
+			matcher := HaveDataMatchingSchema(schema)
+
+			Expect(matcher.Match(logger)).To(BeFalse())
+			message := matcher.FailureMessage(logger)
+			Expect(message).To(ContainSubstring("api.cache"))
+			Expect(message).To(ContainSubstring("guid is required"))
+		})
+
+		It("does not match if a filtered entry does not conform", func() {
+			logger.Info("request", lager.Data{"guid": "third-guid", "status": "teapot"})
+			Expect(logger).ToNot(HaveDataMatchingSchema(schema, Info(Action("api.request"))))
+		})
+
+		It("returns an error for invalid actuals", func() {
+			_, err := HaveDataMatchingSchema(schema).Match(42)
+			Expect(err).To(MatchError(ContainSubstring("HaveDataMatchingSchema must be passed")))
+		})
+	})
+})
@@ -0,0 +1,192 @@
+package glager
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+type secretPattern struct {
+	name   string
+	regexp *regexp.Regexp
+}
+
+var builtinSecretPatterns = []secretPattern{
+	{"bearer token", regexp.MustCompile(`(?i)bearer\s+[a-z0-9\-._~+/]{8,}=*`)},
+	{"AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"AWS secret access key", regexp.MustCompile(`(?i)aws.{0,20}?secret.{0,20}?[0-9a-zA-Z/+]{40}`)},
+	{"PEM private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"password", regexp.MustCompile(`(?i)"[a-z_\-]*(password|passwd|pwd)"\s*:\s*"[^"]+"`)},
+}
+
+type secretsMatcher struct {
+	patterns []secretPattern
+	entry    *LogEntry
+	pattern  string
+}
+
+// ContainNoSecrets checks that none of the entries in the log contains a
+// secret. Every entry is scanned in its entire serialized form for a set of
+// built-in patterns, i.e. bearer tokens, AWS access keys, PEM encoded private
+// keys, and non-empty values of password keys. Additional regular expressions
+// can be passed to scan for custom secrets. The function panics if any of them
+// cannot be compiled.
+//
+// Example:
+//
+//	Expect(logger).To(ContainNoSecrets(`cf-[0-9a-f]{32}`))
+func ContainNoSecrets(patterns ...string) types.GomegaMatcher {
+	matcher := &secretsMatcher{
+		patterns: append([]secretPattern{}, builtinSecretPatterns...),
+	}
+
+	for _, pattern := range patterns {
+		matcher.patterns = append(matcher.patterns, secretPattern{
+			name:   fmt.Sprintf("pattern %q", pattern),
+			regexp: regexp.MustCompile(pattern),
+		})
+	}
+
+	return matcher
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (sm *secretsMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("ContainNoSecrets", actual)
+	if err != nil {
+		return false, err
+	}
+
+	sm.entry = nil
+	sm.pattern = ""
+
+	for i := range entries {
+		serialized, err := json.Marshal(entries[i])
+		if err != nil {
+			return false, err
+		}
+
+		for _, pattern := range sm.patterns {
+			if pattern.regexp.Match(serialized) {
+				sm.entry = &entries[i]
+				sm.pattern = pattern.name
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (sm *secretsMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected log entry\n%s\nnot to contain a secret, found %s",
+		format.IndentString(sm.entry.String(), 1),
+		sm.pattern,
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (sm *secretsMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return "Expected log to contain a secret"
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (sm *secretsMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+type valuesMatcher struct {
+	values []string
+	entry  *LogEntry
+	value  string
+}
+
+// NotContainValues checks that none of the given plaintext values appears
+// anywhere in the log, i.e. neither in the source nor the message nor any,
+// possibly nested, data key or value of any entry. It complements
+// ContainNoSecrets for tests that know the exact secrets being used.
+//
+// Example:
+//
+//	Expect(logger).To(NotContainValues("s3cr3t-password", token))
+func NotContainValues(values ...string) types.GomegaMatcher {
+	return &valuesMatcher{
+		values: values,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (vm *valuesMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("NotContainValues", actual)
+	if err != nil {
+		return false, err
+	}
+
+	vm.entry = nil
+	vm.value = ""
+
+	for i, entry := range entries {
+		for _, value := range vm.values {
+			if value == "" {
+				continue
+			}
+
+			if containsString(entry.Source, value) ||
+				containsString(entry.Message, value) ||
+				containsString(map[string]interface{}(entry.Data), value) {
+				vm.entry = &entries[i]
+				vm.value = value
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (vm *valuesMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected log entry\n%s\nnot to contain value %q",
+		format.IndentString(vm.entry.String(), 1),
+		vm.value,
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (vm *valuesMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected log to contain any of the values %q", vm.values)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (vm *valuesMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+// containsString walks the given value decoded from JSON and reports whether
+// any of its strings, including object keys, contains the given substring.
+func containsString(value interface{}, substr string) bool {
+	switch x := value.(type) {
+	case string:
+		return strings.Contains(x, substr)
+	case []interface{}:
+		for _, elem := range x {
+			if containsString(elem, substr) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for key, elem := range x {
+			if strings.Contains(key, substr) || containsString(elem, substr) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package glager
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// streamedLog is what is retained of a log that has been matched line by line
+// by matchSeekable, in place of its entries. Besides the matched entries, only
+// the last matched entry and the entries following it, up to
+// divergenceWindow, are retained for the failure message.
+type streamedLog struct {
+	// entries is the number of entries that have been read.
+	entries int
+	// matched holds the entries matched by the expected entries, in order.
+	matched logEntries
+	// window holds the entries around the point of divergence, the first of
+	// which is entry number first.
+	window logEntries
+	first  int
+	// levels and sources count the entries per level and per source.
+	levels  map[lager.LogLevel]int
+	sources map[string]int
+}
+
+// matchSeekable matches the expected sequence against a seekable reader, e.g.
+// a large log file passed as *os.File or using FromFile, line by line. Reading stops as soon as the sequence has
+// been matched, and the reader is rewound afterwards. Lines following the last
+// matched entry are not read, i.e. they are not validated either. Instead of
+// the entries of the log, only the state needed for the failure message is
+// retained, see streamedLog. If the sequence has not been matched, the lines
+// following the last matched entry are read a second time to describe how the
+// next expected entry has been missed. It reports whether the sequence has
+// been matched this way. If it has not, because the actual is no seekable
+// reader, the sequence cannot be matched greedily, or a line cannot be parsed,
+// the whole log has to be parsed to match it, or to report the error.
+func (lm *logMatcher) matchSeekable(actual interface{}) (streamed bool, err error) {
+	if lm.contiguous || lm.anchor != anchorNone || !plainSequence(lm.expected) {
+		return false, nil
+	}
+
+	if file, ok := actual.(logFile); ok {
+		// the file is opened for this match only, an entry that is still being
+		// written cannot be parsed, so that the whole file is parsed instead
+		opened, err := file.read()
+		if err != nil {
+			return false, err
+		}
+		defer closeContents(opened)
+		actual = opened
+	}
+
+	reader, ok := actual.(io.ReadSeeker)
+	if !ok || isLive(actual) {
+		return false, nil
+	}
+
+	offset, err := reader.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		if _, seekErr := reader.Seek(offset, io.SeekStart); seekErr != nil && err == nil {
+			streamed, err = false, seekErr
+		}
+	}()
+
+	stream := &streamedLog{
+		levels:  map[lager.LogLevel]int{},
+		sources: map[string]int{},
+	}
+	matched := []int{}
+
+	// resume is the offset right after the last matched entry
+	resume := offset
+
+	parsed, err := scanLines(reader, offset, func(entry LogEntry, end int64) (bool, error) {
+		stream.entries++
+		stream.levels[entry.LogLevel]++
+		stream.sources[entry.Source]++
+
+		containsEntry, err := entry.contains(lm.expected[len(matched)])
+		if err != nil || !containsEntry {
+			return true, err
+		}
+
+		matched = append(matched, stream.entries-1)
+		stream.matched = append(stream.matched, entry)
+		resume = end
+
+		return len(matched) < len(lm.expected), nil
+	})
+	if err != nil || !parsed {
+		return false, err
+	}
+
+	lm.actual, lm.matched, lm.stream = nil, matched, stream
+
+	stream.first = lm.divergence()
+	if len(matched) > 0 {
+		stream.first--
+		stream.window = logEntries{stream.matched[len(matched)-1]}
+	}
+
+	if len(matched) == len(lm.expected) {
+		return true, nil
+	}
+
+	if _, err := reader.Seek(resume, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	expected := lm.expected[len(matched)]
+	index := lm.divergence()
+	var candidate closestCandidate
+
+	_, err = scanLines(reader, resume, func(entry LogEntry, _ int64) (bool, error) {
+		if stream.first+len(stream.window) < lm.divergence()+divergenceWindow {
+			stream.window = append(stream.window, entry)
+		}
+
+		mismatches, err := logEntries{entry}.dataMismatches(expected, index, lm.differ)
+		if err != nil {
+			return false, err
+		}
+		lm.mismatches = append(lm.mismatches, mismatches...)
+
+		if err := candidate.consider(expected, entry, index); err != nil {
+			return false, err
+		}
+
+		index++
+		return true, nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if len(lm.mismatches) == 0 {
+		lm.closest = candidate.String()
+	}
+
+	return true, nil
+}
+
+// scanLines decodes the entries of the given reader line by line and passes
+// each of them to the given function, along with the offset right after it,
+// excluding the trailing newline, until the function returns false. The
+// offsets start at the given offset. It reports whether all lines that have
+// been read could be parsed.
+func scanLines(reader io.Reader, offset int64, fn func(entry LogEntry, end int64) (bool, error)) (bool, error) {
+	buffered := bufio.NewReader(reader)
+
+	for {
+		line, readErr := buffered.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return false, readErr
+		}
+
+		offset += int64(len(line))
+		end := offset
+		if bytes.HasSuffix(line, []byte("\n")) {
+			line = line[:len(line)-1]
+			end--
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry LogEntry
+			if err := json.Unmarshal(line, &entry); err != nil {
+				return false, nil
+			}
+
+			if more, err := fn(entry, end); err != nil || !more {
+				return true, err
+			}
+		}
+
+		if readErr == io.EOF {
+			return true, nil
+		}
+	}
+}
+
+// plainSequence reports whether the given sequence can be matched greedily,
+// i.e. whether it contains neither repeated, nor negated, nor immediate
+// entries.
+func plainSequence(sequence []logEntry) bool {
+	for _, entry := range sequence {
+		if entry.repeated.times > 0 || entry.immediate || len(entry.absentBefore) > 0 || len(entry.absentAfter) > 0 {
+			return false
+		}
+	}
+	return true
+}
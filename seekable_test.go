@@ -0,0 +1,104 @@
+package glager_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+// countingReader counts the bytes read from a seekable reader.
+type countingReader struct {
+	io.ReadSeeker
+	read int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadSeeker.Read(p)
+	r.read += n
+	return n, err
+}
+
+var _ = Describe("seekable logs", func() {
+	var log string
+
+	BeforeEach(func() {
+		lines := []string{}
+		for i := 0; i < 1000; i++ {
+			lines = append(lines, fmt.Sprintf(`{"timestamp":"%d","source":"app","message":"app.request","log_level":1,"data":{"i":%d}}`, i, i))
+		}
+		log = strings.Join(lines, "\n") + "\n"
+	})
+
+	It("stops reading once the sequence has been matched", func() {
+		reader := &countingReader{ReadSeeker: strings.NewReader(log)}
+
+		Expect(reader).To(ContainSequence(Info(Data("i", 1)), Info(Data("i", 10))))
+		Expect(reader.read).To(BeNumerically("<", len(log)/2))
+	})
+
+	It("rewinds the reader after matching", func() {
+		reader := strings.NewReader(log)
+
+		Expect(reader).To(ContainSequence(Info(Data("i", 10))))
+		Expect(reader).To(ContainSequence(Info(Data("i", 0))))
+		Expect(reader.Len()).To(Equal(len(log)))
+	})
+
+	It("reports failures like for any other log", func() {
+		reader := strings.NewReader(log)
+
+		matcher := ContainSequence(Info(Data("i", 10)), Info(Data("i", 5)))
+		Expect(matcher.Match(reader)).To(BeFalse())
+		Expect(matcher.FailureMessage(reader)).To(ContainSubstring("0: matched entry 10\n\t1: not found"))
+		Expect(reader.Len()).To(Equal(len(log)))
+	})
+
+	It("builds the failure message from the entries it has read", func() {
+		reader := strings.NewReader(log)
+
+		matcher := ContainSequence(Info(Data("i", 10)), Info(Data("i", 5)))
+		Expect(matcher.Match(reader)).To(BeFalse())
+
+		message := matcher.FailureMessage(reader)
+		Expect(message).To(HavePrefix("Expected\n\t... 10 entries\n\tentry 10: "))
+		Expect(message).To(ContainSubstring("\n\tentry 15: "))
+		Expect(message).To(ContainSubstring("\n\t... 984 entries\nto contain log sequence"))
+		Expect(message).ToNot(ContainSubstring(`Timestamp: "16"`))
+		Expect(message).To(ContainSubstring("entry 11: data.i: expected 5, got 11"))
+		Expect(message).To(ContainSubstring("Log levels: info=1000\nLog sources: \"app\"=1000"))
+	})
+
+	It("reports the closest candidate", func() {
+		reader := strings.NewReader(log)
+
+		matcher := ContainSequence(Info(Data("i", 10)), Error(AnyErr, Data("i", 12)))
+		Expect(matcher.Match(reader)).To(BeFalse())
+		Expect(matcher.FailureMessage(reader)).To(ContainSubstring("Closest candidate, entry 12:\n\tlevel: expected error, got info"))
+	})
+
+	It("matches files starting at their current offset", func() {
+		dir, err := os.MkdirTemp("", "glager")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "app.log")
+		Expect(os.WriteFile(path, []byte(log), 0600)).To(Succeed())
+
+		file, err := os.Open(path)
+		Expect(err).ToNot(HaveOccurred())
+		defer file.Close()
+
+		_, err = file.Seek(int64(strings.Index(log, `"timestamp":"5"`))-1, io.SeekStart)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(file).To(ContainSequence(Info(Data("i", 5)), Info(Data("i", 999))))
+		Expect(file).ToNot(ContainSequence(Info(Data("i", 4))))
+	})
+})
@@ -0,0 +1,151 @@
+package glager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// Sequence groups the given entries into a sequence that can be passed to
+// SatisfyAllSequences.
+func Sequence(entries ...ExpectedEntry) []ExpectedEntry {
+	return entries
+}
+
+type allSequencesMatcher struct {
+	actual    logEntries
+	sequences []*logMatcher
+}
+
+// SatisfyAllSequences checks that the log contains every one of the given
+// sequences, each of them just like ContainSequence would. The sequences are
+// independent of each other, i.e. their entries may interleave. The log is
+// parsed and scanned only once for all sequences, which makes it considerably
+// faster than separate assertions for large logs. Failures of all sequences
+// are reported together.
+//
+// Example:
+//
+//	Expect(logger).To(SatisfyAllSequences(
+//	  Sequence(Info(Action("api.start")), Info(Action("api.ready"))),
+//	  Sequence(Info(Action("worker.start")), Info(Action("worker.ready"))),
+//	))
+func SatisfyAllSequences(sequences ...[]ExpectedEntry) types.GomegaMatcher {
+	matchers := make([]*logMatcher, len(sequences))
+	for i, sequence := range sequences {
+		matchers[i] = &logMatcher{expected: sequence}
+	}
+
+	return &allSequencesMatcher{
+		sequences: matchers,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (am *allSequencesMatcher) Match(actual interface{}) (success bool, err error) {
+	am.actual, err = parseEntries("SatisfyAllSequences", actual)
+	if err != nil {
+		return false, err
+	}
+
+	for _, lm := range am.sequences {
+		lm.actual = am.actual
+		lm.matched = []int{}
+		lm.mismatches = nil
+	}
+
+	for i, entry := range am.actual {
+		for _, lm := range am.sequences {
+			if len(lm.matched) == len(lm.expected) {
+				continue
+			}
+
+			matches, err := entry.contains(lm.expected[len(lm.matched)])
+			if err != nil {
+				return false, err
+			}
+
+			if matches {
+				lm.matched = append(lm.matched, i)
+			}
+		}
+	}
+
+	success = true
+	for _, lm := range am.sequences {
+		if len(lm.matched) < len(lm.expected) {
+			offset := lm.divergence()
+			lm.mismatches, err = lm.actual[offset:].dataMismatches(lm.expected[len(lm.matched)], offset, lm.differ)
+			if err != nil {
+				return false, err
+			}
+			success = false
+		}
+	}
+
+	if !success {
+		return false, nil
+	}
+
+	for _, lm := range am.sequences {
+		for n, i := range lm.matched {
+			lm.expected[n].capture(lm.actual[i])
+		}
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions. The log is only
+// included once, the failures of the individual sequences are rendered using
+// at most VerbosityWindow.
+func (am *allSequencesMatcher) FailureMessage(actual interface{}) (message string) {
+	failures := []string{}
+	for i, lm := range am.sequences {
+		if len(lm.matched) == len(lm.expected) {
+			continue
+		}
+
+		verbosity := FailureVerbosity
+		if verbosity > VerbosityWindow {
+			verbosity = VerbosityWindow
+		}
+
+		configured := *lm
+		configured.verbosity = &verbosity
+		failures = append(failures, fmt.Sprintf("Sequence %d:\n%s", i, format.IndentString(configured.FailureMessage(actual), 1)))
+	}
+
+	message = fmt.Sprintf("Expected log to satisfy all %d log sequences, %d failed", len(am.sequences), len(failures))
+	if FailureVerbosity == VerbosityFull {
+		message = fmt.Sprintf(
+			"Expected\n\t%s\nto satisfy all %d log sequences, %d failed",
+			format.Object(am.actual, 0),
+			len(am.sequences),
+			len(failures),
+		)
+	}
+
+	return message + "\n" + strings.Join(failures, "\n")
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (am *allSequencesMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	sequences := make([]string, len(am.sequences))
+	for i, lm := range am.sequences {
+		sequences[i] = fmt.Sprintf("Sequence %d:\n\t%s", i, renderSequence(lm.expected))
+	}
+
+	return fmt.Sprintf(
+		"Expected log not to satisfy all %d log sequences\n%s",
+		len(am.sequences),
+		strings.Join(sequences, "\n"),
+	)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (am *allSequencesMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
@@ -0,0 +1,101 @@
+package glager_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".SatisfyAllSequences", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		logger.Info("api-start")
+		logger.Info("worker-start")
+		logger.Info("api-ready")
+		logger.Error("worker-failed", errors.New("boom"))
+		logger.Info("worker-ready")
+	})
+
+	AfterEach(func() {
+		FailureVerbosity = VerbosityFull
+	})
+
+	It("matches if all sequences are contained", func() {
+		Expect(logger).To(SatisfyAllSequences(
+			Sequence(Info(Action("test.api-start")), Info(Action("test.api-ready"))),
+			Sequence(Info(Action("test.worker-start")), Error(AnyErr), Info(Action("test.worker-ready"))),
+		))
+	})
+
+	It("matches sequences sharing entries", func() {
+		Expect(logger).To(SatisfyAllSequences(
+			Sequence(Info(Action("test.api-start")), Info(Action("test.api-ready"))),
+			Sequence(Info(Action("test.api-start")), Info(Action("test.worker-ready"))),
+		))
+	})
+
+	It("does not match if any sequence is not contained", func() {
+		Expect(logger).ToNot(SatisfyAllSequences(
+			Sequence(Info(Action("test.api-start")), Info(Action("test.api-ready"))),
+			Sequence(Info(Action("test.worker-ready")), Info(Action("test.worker-start"))),
+		))
+	})
+
+	It("behaves like ContainSequence for every sequence", func() {
+		sequences := [][]ExpectedEntry{
+			Sequence(Info(), Info(), Info(), Info()),
+			Sequence(Error(AnyErr), Info()),
+			Sequence(Info(Action("test.api-ready")), Info(Action("test.api-start"))),
+			Sequence(),
+		}
+
+		for _, sequence := range sequences {
+			steps := []SequenceEntry{}
+			for _, entry := range sequence {
+				steps = append(steps, entry)
+			}
+
+			expected, err := ContainSequence(steps...).Match(logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(SatisfyAllSequences(sequence).Match(logger)).To(Equal(expected))
+		}
+	})
+
+	It("reports the failures of all sequences together", func() {
+		FailureVerbosity = VerbositySummary
+
+		matcher := SatisfyAllSequences(
+			Sequence(Info(Action("test.api-start")), Debug()),
+			Sequence(Info(Action("test.api-start"))),
+			Sequence(Fatal(AnyErr)),
+		)
+
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		message := matcher.FailureMessage(logger)
+		Expect(message).To(HavePrefix("Expected log to satisfy all 3 log sequences, 2 failed\n"))
+		Expect(message).To(ContainSubstring("Sequence 0:\n    Expected log to contain entry 1 of log sequence"))
+		Expect(message).To(ContainSubstring("Sequence 2:\n    Expected log to contain entry 0 of log sequence"))
+		Expect(message).ToNot(ContainSubstring("Sequence 1:"))
+	})
+
+	It("includes the log only once", func() {
+		matcher := SatisfyAllSequences(Sequence(Debug()), Sequence(Fatal(AnyErr)))
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		message := matcher.FailureMessage(logger)
+		Expect(message).To(HavePrefix("Expected\n"))
+		Expect(message).To(ContainSubstring("to satisfy all 2 log sequences, 2 failed"))
+	})
+
+	It("captures matched entries", func() {
+		var ready LogEntry
+		Expect(logger).To(SatisfyAllSequences(Sequence(Info(Action("test.api-ready"), CaptureInto(&ready)))))
+		Expect(ready.Message).To(Equal("test.api-ready"))
+	})
+})
@@ -0,0 +1,123 @@
+package glager
+
+import "sort"
+
+// SessionNode is a session in the hierarchy of sessions that have written
+// entries to a log. Sessions that have not written any entries themselves but
+// have descendants that did, are part of the hierarchy as well.
+type SessionNode struct {
+	// ID is the identifier of the session, e.g. "3.1". The root of the
+	// hierarchy has an empty identifier.
+	ID string
+
+	// Sources are the distinct sources of the entries written by the session,
+	// in order of appearance.
+	Sources []string
+
+	// Entries are the entries written by the session itself, i.e. not by any
+	// of its descendants. The entries of the root are the ones that have not
+	// been written by any session.
+	Entries []LogEntry
+
+	// Children are the direct child sessions, ordered by their identifiers.
+	Children []*SessionNode
+}
+
+// SessionTree parses the given actual and returns the hierarchy of the
+// sessions that have written entries to it. The actual can be anything that is
+// accepted by the ContainSequence matcher. Use it for custom assertions about
+// request fan-out that cannot be expressed using the matchers.
+//
+// Example:
+//
+//	tree, err := SessionTree(logger)
+//	Expect(err).ToNot(HaveOccurred())
+//	Expect(tree.Find("3").Children).To(HaveLen(4))
+func SessionTree(actual interface{}) (*SessionNode, error) {
+	entries, err := parseEntries("SessionTree", actual)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &SessionNode{}
+	nodes := map[string]*SessionNode{"": root}
+
+	var node func(id string) *SessionNode
+	node = func(id string) *SessionNode {
+		if n, found := nodes[id]; found {
+			return n
+		}
+
+		n := &SessionNode{ID: id}
+		nodes[id] = n
+
+		parent, _ := splitSession(id)
+		p := node(parent)
+		p.Children = append(p.Children, n)
+
+		return n
+	}
+
+	for _, entry := range entries {
+		n := node(entry.session())
+		n.Entries = append(n.Entries, entry)
+
+		if !containsSource(n.Sources, entry.Source) {
+			n.Sources = append(n.Sources, entry.Source)
+		}
+	}
+
+	for _, n := range nodes {
+		sort.SliceStable(n.Children, func(a, b int) bool {
+			return lessSession(n.Children[a].ID, n.Children[b].ID)
+		})
+	}
+
+	return root, nil
+}
+
+// Find returns the session with the given identifier, or nil if the session
+// is not part of the hierarchy below the node.
+func (n *SessionNode) Find(id string) *SessionNode {
+	if n.ID == id {
+		return n
+	}
+
+	for _, child := range n.Children {
+		if isSessionUnder(id, child.ID) {
+			return child.Find(id)
+		}
+	}
+
+	return nil
+}
+
+// EntryCount returns the number of entries written by the session and all of
+// its descendants.
+func (n *SessionNode) EntryCount() int {
+	count := len(n.Entries)
+	for _, child := range n.Children {
+		count += child.EntryCount()
+	}
+	return count
+}
+
+func containsSource(sources []string, source string) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// lessSession orders sibling sessions by their numeric identifiers.
+func lessSession(a, b string) bool {
+	_, idA := splitSession(a)
+	_, idB := splitSession(b)
+
+	if idA != idB {
+		return idA < idB
+	}
+	return a < b
+}
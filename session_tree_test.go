@@ -0,0 +1,64 @@
+package glager_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("SessionTree", func() {
+	var tree *SessionNode
+
+	BeforeEach(func() {
+		logger := NewLogger("test")
+		logger.Info("start")
+
+		server := logger.Session("server")
+		for i := 0; i < 10; i++ {
+			request := server.Session("request")
+			request.Info("start")
+			request.Session("db").Info("query")
+		}
+
+		var err error
+		tree, err = SessionTree(logger)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("returns the entries without session as root", func() {
+		Expect(tree.ID).To(BeEmpty())
+		Expect(tree.Entries).To(HaveLen(1))
+		Expect(tree.Sources).To(Equal([]string{"test"}))
+		Expect(tree.EntryCount()).To(Equal(21))
+	})
+
+	It("includes sessions that have not written any entries", func() {
+		Expect(tree.Children).To(HaveLen(1))
+		Expect(tree.Children[0].ID).To(Equal("1"))
+		Expect(tree.Children[0].Entries).To(BeEmpty())
+	})
+
+	It("orders children by their identifiers", func() {
+		children := tree.Find("1").Children
+		Expect(children).To(HaveLen(10))
+		Expect(children[1].ID).To(Equal("1.2"))
+		Expect(children[9].ID).To(Equal("1.10"))
+	})
+
+	It("finds sessions by their identifiers", func() {
+		request := tree.Find("1.10")
+		Expect(request).ToNot(BeNil())
+		Expect(request.Entries).To(HaveLen(1))
+		Expect(request.Entries[0].Message).To(Equal("test.server.request.start"))
+		Expect(request.EntryCount()).To(Equal(2))
+
+		Expect(tree.Find("1.10.1").Entries[0].Message).To(Equal("test.server.request.db.query"))
+		Expect(tree.Find("1.11")).To(BeNil())
+	})
+
+	It("returns an error for invalid actuals", func() {
+		_, err := SessionTree(42)
+		Expect(err).To(MatchError(ContainSubstring("SessionTree must be passed")))
+	})
+})
@@ -0,0 +1,523 @@
+package glager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// SessionKey is the data key lager uses to store the identifier of the session
+// that wrote a given log entry. Session identifiers are dotted paths, e.g.
+// "3.1.2" identifies the second child of the first child of session "3".
+const SessionKey = "session"
+
+// SessionUnder specifies that a given log entry must have been written by the
+// session with the given identifier or by any of its descendants, e.g.
+// SessionUnder("3") matches entries of the sessions "3", "3.1", and "3.1.2" but
+// not those of session "31".
+func SessionUnder(id string) Option {
+	return withCheck(fmt.Sprintf("session under %q", id), func(actual LogEntry) (bool, error) {
+		return isSessionUnder(actual.session(), id), nil
+	})
+}
+
+// Session specifies that a given log entry must have been written by the
+// session with the given hierarchy of names. lager prefixes the message of such
+// an entry with the source and the session names, e.g. an entry logged by
+// logger.Session("request").Session("auth") has a message like
+// "source.request.auth.action" and a session identifier like "3.1". Entries
+// written by descendants of the session are not matched.
+//
+// Example:
+//
+//	Expect(logger).To(HaveLogged(
+//	  Info(Session("request", "auth"), Data("user", "admin")),
+//	))
+func Session(names ...string) Option {
+	description := fmt.Sprintf("session %q", strings.Join(names, "."))
+	return withCheck(description, func(actual LogEntry) (bool, error) {
+		prefix := strings.Join(append([]string{actual.Source}, names...), ".") + "."
+		if !strings.HasPrefix(actual.Message, prefix) {
+			return false, nil
+		}
+
+		session := actual.session()
+		if len(names) == 0 {
+			return session == "", nil
+		}
+
+		return session != "" && strings.Count(session, ".") == len(names)-1, nil
+	})
+}
+
+func isSessionUnder(session, id string) bool {
+	return session == id || strings.HasPrefix(session, id+".")
+}
+
+func (entry LogEntry) session() string {
+	session, _ := entry.Data[SessionKey].(string)
+	return session
+}
+
+type sessionsMatcher struct {
+	name       string
+	entry      logEntry
+	sequential bool
+	sessions   []string
+	reason     string
+}
+
+// HaveDistinctSessions checks that every entry matching the given entry has
+// been written by a different session. Use it with an entry that is logged
+// once per request to catch bugs where a session logger is shared across
+// requests.
+//
+// Example:
+//
+//	Expect(logger).To(HaveDistinctSessions(Info(Action("server.request.start"))))
+func HaveDistinctSessions(entry logEntry) types.GomegaMatcher {
+
+	return &sessionsMatcher{
+		name:  "HaveDistinctSessions",
+		entry: entry,
+	}
+}
+
+// HaveSequentialSessions checks that every entry matching the given entry has
+// been written by a different session and that these sessions have been
+// numbered sequentially, e.g. "1", "2", "3" or "4.1", "4.2", "4.3". Use it to
+// verify that exactly one new session has been created per request.
+func HaveSequentialSessions(entry logEntry) types.GomegaMatcher {
+
+	return &sessionsMatcher{
+		name:       "HaveSequentialSessions",
+		entry:      entry,
+		sequential: true,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (sm *sessionsMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries(sm.name, actual)
+	if err != nil {
+		return false, err
+	}
+
+	entries, err = entries.filter([]logEntry{sm.entry})
+	if err != nil {
+		return false, err
+	}
+
+	sm.sessions = []string{}
+	sm.reason = ""
+
+	seen := map[string]bool{}
+
+	for _, entry := range entries {
+		session := entry.session()
+
+		switch {
+		case session == "":
+			sm.reason = fmt.Sprintf("entry %q has not been written by a session", entry.Message)
+		case seen[session]:
+			sm.reason = fmt.Sprintf("session %q has been used more than once", session)
+		case sm.sequential && len(sm.sessions) > 0 && !isNextSession(sm.sessions[len(sm.sessions)-1], session):
+			sm.reason = fmt.Sprintf("session %q does not follow session %q", session, sm.sessions[len(sm.sessions)-1])
+		}
+
+		sm.sessions = append(sm.sessions, session)
+
+		if sm.reason != "" {
+			return false, nil
+		}
+
+		seen[session] = true
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (sm *sessionsMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected entries matching\n%s\nto have %s, but %s. Sessions: %v",
+		format.Object(sm.entry, 1),
+		sm.description(),
+		sm.reason,
+		sm.sessions,
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (sm *sessionsMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected entries matching\n%s\nnot to have %s. Sessions: %v",
+		format.Object(sm.entry, 1),
+		sm.description(),
+		sm.sessions,
+	)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (sm *sessionsMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+func (sm *sessionsMatcher) description() string {
+	if sm.sequential {
+		return "sequentially numbered sessions"
+	}
+	return "distinct sessions"
+}
+
+// isNextSession reports whether next is the sibling session that has been
+// created right after the given session.
+func isNextSession(session, next string) bool {
+	parent, id := splitSession(session)
+	nextParent, nextID := splitSession(next)
+
+	return parent == nextParent && id >= 0 && nextID == id+1
+}
+
+func splitSession(session string) (string, int) {
+	parent := ""
+	last := session
+
+	if i := strings.LastIndex(session, "."); i >= 0 {
+		parent, last = session[:i], session[i+1:]
+	}
+
+	id, err := strconv.Atoi(last)
+	if err != nil {
+		return parent, -1
+	}
+
+	return parent, id
+}
+
+type sessionMatcher struct {
+	id      string
+	matcher types.GomegaMatcher
+}
+
+// InSession applies the given matcher to the entries written by the session
+// with the given identifier and its descendants only. All other entries are
+// removed from the log before matching.
+//
+// Example:
+//
+//	Expect(logger).To(InSession("3", ContainSequence(
+//	  Info(Action("server.request.start")),
+//	  Info(Action("server.request.done")),
+//	)))
+func InSession(id string, matcher types.GomegaMatcher) types.GomegaMatcher {
+	return &sessionMatcher{
+		id:      id,
+		matcher: matcher,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (sm *sessionMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("InSession", actual)
+	if err != nil {
+		return false, err
+	}
+
+	return sm.matcher.Match(entries.inSession(sm.id))
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (sm *sessionMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("In session %q:\n%s", sm.id, sm.matcher.FailureMessage(actual))
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (sm *sessionMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("In session %q:\n%s", sm.id, sm.matcher.NegatedFailureMessage(actual))
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (sm *sessionMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+type withinSessionMatcher struct {
+	name    string
+	matcher types.GomegaMatcher
+	entries logEntries
+}
+
+// WithinSession applies the given matcher to the entries written by sessions
+// with the given name only, e.g. the session of a single request amid
+// interleaved ones. The name is the one passed to lager's Session, nested
+// sessions are given as dotted path, e.g. "server.request-42". The messages of
+// the matched entries are stripped of the source and session name prefix,
+// i.e. an entry logged as "api.request-42.start" is matched as "start". Entries
+// of descendant sessions are included, e.g. "auth.start". All other entries are
+// removed from the log before matching.
+//
+// Example:
+//
+//	Expect(logger).To(WithinSession("request-42", ContainSequence(
+//	  Info(Action("start")),
+//	  Info(Action("done")),
+//	)))
+func WithinSession(name string, matcher types.GomegaMatcher) types.GomegaMatcher {
+	return &withinSessionMatcher{
+		name:    name,
+		matcher: matcher,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (wm *withinSessionMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("WithinSession", actual)
+	if err != nil {
+		return false, err
+	}
+
+	wm.entries = entries.withinSession(wm.name)
+	return wm.matcher.Match(wm.entries)
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (wm *withinSessionMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Within session %q:\n%s", wm.name, wm.matcher.FailureMessage(wm.entries))
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (wm *withinSessionMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Within session %q:\n%s", wm.name, wm.matcher.NegatedFailureMessage(wm.entries))
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (wm *withinSessionMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+// withinSession returns the entries that have been written by sessions with
+// the given name, with their messages stripped of the source and session name.
+func (entries logEntries) withinSession(name string) logEntries {
+	result := logEntries{}
+	for _, entry := range entries {
+		prefix := entry.Source + "." + name + "."
+		if entry.session() == "" || !strings.HasPrefix(entry.Message, prefix) {
+			continue
+		}
+
+		entry.Message = strings.TrimPrefix(entry.Message, prefix)
+		result = append(result, entry)
+	}
+	return result
+}
+
+type eachSessionMatcher struct {
+	matcher  types.GomegaMatcher
+	sessions []string
+	failed   string
+	failure  string
+	negation string
+}
+
+// EachSession applies the given matcher to the entries of every outermost
+// session found in the log, i.e. every session whose parent has not written
+// any entries. The entries of each session, including those of its
+// descendants, are matched in isolation. Entries that have not been written by
+// a session are ignored. The matcher fails if the log does not contain any
+// session entries at all. Use it to check lifecycle invariants across all
+// concurrent requests at once.
+//
+// Example:
+//
+//	Expect(logger).To(EachSession(ContainSequence(
+//	  Info(Action("server.request.start")),
+//	  Info(Action("server.request.done")),
+//	)))
+func EachSession(matcher types.GomegaMatcher) types.GomegaMatcher {
+	return &eachSessionMatcher{
+		matcher: matcher,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (em *eachSessionMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("EachSession", actual)
+	if err != nil {
+		return false, err
+	}
+
+	em.sessions = entries.outermostSessions()
+	em.failed = ""
+
+	if len(em.sessions) == 0 {
+		return false, nil
+	}
+
+	for _, session := range em.sessions {
+		sessionEntries := entries.inSession(session)
+
+		success, err := em.matcher.Match(sessionEntries)
+		if err != nil {
+			return false, err
+		}
+
+		if !success {
+			em.failed = session
+			em.failure = em.matcher.FailureMessage(sessionEntries)
+			return false, nil
+		}
+
+		em.negation = em.matcher.NegatedFailureMessage(sessionEntries)
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (em *eachSessionMatcher) FailureMessage(actual interface{}) (message string) {
+	if em.failed == "" {
+		return fmt.Sprintf(
+			"Expected\n%s\nto contain entries tagged with data key %q",
+			format.Object(actual, 1),
+			SessionKey,
+		)
+	}
+
+	return fmt.Sprintf("In session %q:\n%s", em.failed, em.failure)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (em *eachSessionMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected at least one of the sessions %v to fail. For the last session:\n%s",
+		em.sessions,
+		em.negation,
+	)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (em *eachSessionMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+func (entries logEntries) inSession(id string) logEntries {
+	result := logEntries{}
+	for _, entry := range entries {
+		if isSessionUnder(entry.session(), id) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// outermostSessions returns the sessions that have written entries while none
+// of their ancestors have, in order of appearance.
+func (entries logEntries) outermostSessions() []string {
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		if session := entry.session(); session != "" {
+			seen[session] = true
+		}
+	}
+
+	sessions := []string{}
+	added := map[string]bool{}
+	for _, entry := range entries {
+		session := entry.session()
+		if session == "" || added[session] || hasAncestor(session, seen) {
+			continue
+		}
+
+		added[session] = true
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+func hasAncestor(session string, sessions map[string]bool) bool {
+	for i := strings.LastIndex(session, "."); i >= 0; i = strings.LastIndex(session, ".") {
+		session = session[:i]
+		if sessions[session] {
+			return true
+		}
+	}
+	return false
+}
+
+type sessionKeyMatcher struct {
+	id        string
+	key       string
+	offending *LogEntry
+}
+
+// HaveDataKeyInSession checks that every entry written by the session with the
+// given identifier, or by any of its descendants, contains the given data key,
+// regardless of its value. The matcher fails if the session has not written
+// any entries. Use it to verify that context, e.g. an app guid, is propagated
+// through nested call chains.
+//
+// Example:
+//
+//	Expect(logger).To(HaveDataKeyInSession("3", "app_guid"))
+func HaveDataKeyInSession(id, key string) types.GomegaMatcher {
+	return &sessionKeyMatcher{
+		id:  id,
+		key: key,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (sm *sessionKeyMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("HaveDataKeyInSession", actual)
+	if err != nil {
+		return false, err
+	}
+
+	entries = entries.inSession(sm.id)
+	sm.offending = nil
+
+	if len(entries) == 0 {
+		return false, nil
+	}
+
+	for i, entry := range entries {
+		if _, found := entry.Data[sm.key]; !found {
+			sm.offending = &entries[i]
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (sm *sessionKeyMatcher) FailureMessage(actual interface{}) (message string) {
+	if sm.offending == nil {
+		return fmt.Sprintf("Expected session %q to have written log entries, found none", sm.id)
+	}
+
+	return fmt.Sprintf(
+		"Expected all log entries of session %q to contain data key %q, found\n%s",
+		sm.id,
+		sm.key,
+		format.IndentString(sm.offending.String(), 1),
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (sm *sessionKeyMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected at least one log entry of session %q not to contain data key %q",
+		sm.id,
+		sm.key,
+	)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (sm *sessionKeyMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
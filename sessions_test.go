@@ -0,0 +1,296 @@
+package glager_test
+
+import (
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("sessions", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+	})
+
+	Describe(".SessionUnder", func() {
+		BeforeEach(func() {
+			logger.Info("root")
+
+			var session lager.Logger
+			for i := 0; i < 3; i++ {
+				session = logger.Session("request")
+			}
+			session.Info("handle")
+			child := session.Session("db")
+			child.Info("query")
+			child.Session("tx").Info("commit")
+		})
+
+		It("matches entries of the session and its descendants", func() {
+			Expect(logger).To(ContainSequence(
+				Info(SessionUnder("3"), Action("test.request.handle")),
+				Info(SessionUnder("3"), Action("test.request.db.query")),
+				Info(SessionUnder("3"), Action("test.request.db.tx.commit")),
+			))
+
+			Expect(logger).To(ContainSequence(
+				Info(SessionUnder("3.1"), Action("test.request.db.tx.commit")),
+			))
+		})
+
+		It("does not match entries outside of the session", func() {
+			Expect(logger).ToNot(ContainSequence(Info(SessionUnder("3"), Action("test.root"))))
+			Expect(logger).ToNot(ContainSequence(Info(SessionUnder("3.1"), Action("test.request.handle"))))
+			Expect(logger).ToNot(ContainSequence(Info(SessionUnder("1"))))
+		})
+
+		It("does not match sessions sharing a prefix", func() {
+			for i := 0; i < 28; i++ {
+				logger.Session("request")
+			}
+			logger.Session("request").Info("later")
+
+			Expect(logger).To(ContainSequence(Info(SessionUnder("32"), Action("test.request.later"))))
+			Expect(logger).ToNot(ContainSequence(Info(SessionUnder("3"), Action("test.request.later"))))
+		})
+	})
+
+	Describe(".Session", func() {
+		BeforeEach(func() {
+			logger.Info("root")
+
+			session := logger.Session("request")
+			session.Info("handle")
+			child := session.Session("db")
+			child.Info("query")
+			child.Session("tx").Info("commit")
+		})
+
+		It("matches entries of the session with the given hierarchy of names", func() {
+			Expect(logger).To(ContainSequence(
+				Info(Session()),
+				Info(Session("request")),
+				Info(Session("request", "db")),
+				Info(Session("request", "db", "tx")),
+			))
+
+			Expect(logger).To(ContainSequence(Info(Session("request", "db"), Action("test.request.db.query"))))
+		})
+
+		It("does not match entries of descendants or other sessions", func() {
+			Expect(logger).ToNot(ContainSequence(Info(Session("request"), Action("test.request.db.query"))))
+			Expect(logger).ToNot(ContainSequence(Info(Session("db"))))
+			Expect(logger).ToNot(ContainSequence(Info(Session("request", "tx"))))
+		})
+	})
+
+	Describe("session counters", func() {
+		handle := func(session lager.Logger) {
+			session.Info("start")
+			session.Info("done")
+		}
+
+		Context("when a new session is created per request", func() {
+			BeforeEach(func() {
+				logger.Session("setup").Info("start")
+				server := logger.Session("server")
+				for i := 0; i < 3; i++ {
+					handle(server.Session("request"))
+				}
+			})
+
+			It("matches distinct sessions", func() {
+				Expect(logger).To(HaveDistinctSessions(Info(Action("test.server.request.start"))))
+			})
+
+			It("matches sequential sessions", func() {
+				Expect(logger).To(HaveSequentialSessions(Info(Action("test.server.request.start"))))
+			})
+		})
+
+		Context("when a session is shared across requests", func() {
+			BeforeEach(func() {
+				session := logger.Session("request")
+				handle(session)
+				handle(session)
+			})
+
+			It("does not match distinct sessions", func() {
+				matcher := HaveDistinctSessions(Info(Action("test.request.start")))
+				Expect(matcher.Match(logger)).To(BeFalse())
+				Expect(matcher.FailureMessage(logger)).To(ContainSubstring(`session "1" has been used more than once`))
+			})
+		})
+
+		Context("when sessions are not numbered sequentially", func() {
+			BeforeEach(func() {
+				handle(logger.Session("request"))
+				logger.Session("other")
+				handle(logger.Session("request"))
+			})
+
+			It("matches distinct sessions", func() {
+				Expect(logger).To(HaveDistinctSessions(Info(Action("test.request.start"))))
+			})
+
+			It("does not match sequential sessions", func() {
+				matcher := HaveSequentialSessions(Info(Action("test.request.start")))
+				Expect(matcher.Match(logger)).To(BeFalse())
+				Expect(matcher.FailureMessage(logger)).To(ContainSubstring(`session "3" does not follow session "1"`))
+			})
+		})
+
+		Context("when entries have not been written by a session", func() {
+			It("does not match", func() {
+				logger.Info("start")
+
+				matcher := HaveDistinctSessions(Info(Action("test.start")))
+				Expect(matcher.Match(logger)).To(BeFalse())
+				Expect(matcher.FailureMessage(logger)).To(ContainSubstring(`entry "test.start" has not been written by a session`))
+			})
+		})
+	})
+
+	Describe(".InSession", func() {
+		BeforeEach(func() {
+			one := logger.Session("request")
+			two := logger.Session("request")
+
+			one.Info("start")
+			two.Info("start")
+			two.Info("done")
+			one.Session("nested").Info("done")
+		})
+
+		It("matches the entries of the session and its descendants", func() {
+			Expect(logger).To(InSession("1", ContainSequence(
+				Info(Action("test.request.start")),
+				Info(Action("test.request.nested.done")),
+			)))
+		})
+
+		It("ignores the entries of other sessions", func() {
+			matcher := InSession("1", ContainSequence(Info(Action("test.request.done"))))
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(HavePrefix(`In session "1":`))
+		})
+	})
+
+	Describe(".WithinSession", func() {
+		BeforeEach(func() {
+			one := logger.Session("request-1")
+			two := logger.Session("request-2")
+
+			one.Info("start")
+			two.Info("start")
+			two.Info("done")
+			one.Session("auth").Info("done")
+			one.Info("done")
+			logger.Info("request-1.done")
+		})
+
+		It("matches the entries of the sessions with their messages stripped", func() {
+			Expect(logger).To(WithinSession("request-1", ContainExactSequence(
+				Info(Action("start")),
+				Info(Action("auth.done")),
+				Info(Action("done")),
+			)))
+		})
+
+		It("matches nested sessions given as dotted path", func() {
+			Expect(logger).To(WithinSession("request-1.auth", ContainExactSequence(
+				Info(Action("done")),
+			)))
+		})
+
+		It("ignores the entries of other sessions", func() {
+			matcher := WithinSession("request-2", ContainSequence(Info(Action("auth.done"))))
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(HavePrefix(`Within session "request-2":`))
+			Expect(matcher.FailureMessage(logger)).ToNot(ContainSubstring("request-1"))
+		})
+	})
+
+	Describe(".EachSession", func() {
+		var server lager.Logger
+
+		BeforeEach(func() {
+			server = logger.Session("server")
+			one := server.Session("request")
+			two := server.Session("request")
+
+			one.Info("start")
+			two.Info("start")
+			one.Session("nested").Info("work")
+			two.Info("done")
+			one.Info("done")
+		})
+
+		It("matches a sequence logged by every session", func() {
+			Expect(logger).To(EachSession(ContainSequence(
+				Info(Message("test.server.request.start")),
+				Info(Message("test.server.request.done")),
+			)))
+		})
+
+		It("does not match a sequence missing for one session", func() {
+			matcher := EachSession(ContainSequence(
+				Info(Message("test.server.request.start")),
+				Info(Message("test.server.request.nested.work")),
+			))
+
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(HavePrefix(`In session "1.2":`))
+		})
+
+		It("only matches outermost sessions", func() {
+			server.Info("stopped")
+
+			Expect(logger).To(EachSession(ContainSequence(
+				Info(Message("test.server.request.start")),
+				Info(Message("test.server.request.start")),
+				Info(Message("test.server.stopped")),
+			)))
+		})
+
+		It("does not match a log without sessions", func() {
+			other := NewLogger("test")
+			other.Info("untagged")
+
+			matcher := EachSession(ContainSequence())
+			Expect(matcher.Match(other)).To(BeFalse())
+			Expect(matcher.FailureMessage(other)).To(ContainSubstring("to contain entries tagged"))
+		})
+	})
+
+	Describe(".HaveDataKeyInSession", func() {
+		BeforeEach(func() {
+			request := logger.Session("request", lager.Data{"app_guid": "abc"})
+			request.Info("start")
+			request.Session("nested").Info("work", lager.Data{"app_guid": nil})
+			request.Info("done")
+			logger.Session("request").Info("start")
+		})
+
+		It("matches if all entries of the session contain the key", func() {
+			Expect(logger).To(HaveDataKeyInSession("1", "app_guid"))
+			Expect(logger).To(HaveDataKeyInSession("1.1", "app_guid"))
+		})
+
+		It("does not match if an entry of the session does not contain the key", func() {
+			matcher := HaveDataKeyInSession("2", "app_guid")
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring(`of session "2" to contain data key "app_guid"`))
+		})
+
+		It("does not match if the session has not written any entries", func() {
+			matcher := HaveDataKeyInSession("3", "app_guid")
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(ContainSubstring("found none"))
+		})
+	})
+})
@@ -0,0 +1,93 @@
+package glager
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/onsi/gomega"
+)
+
+// SoftAssertions collects the failures of several assertions and reports them
+// together when calling Verify, rather than aborting the test at the first
+// failure. Use it for long integration scenarios, where a single broken log
+// line would otherwise hide all other divergences.
+//
+// SoftAssertions embeds gomega.Gomega, i.e. it provides Expect, Eventually,
+// and Consistently, which work with any matcher.
+//
+// Example:
+//
+//	soft := NewSoftAssertions()
+//	defer soft.Verify()
+//
+//	soft.Expect(logger).To(ContainSequence(Info(Action("api.start"))))
+//	soft.Expect(logger).To(HaveNoEntriesBelow(lager.INFO))
+type SoftAssertions struct {
+	gomega.Gomega
+	lock     sync.Mutex
+	failures []string
+}
+
+// NewSoftAssertions returns a new SoftAssertions without any failures.
+func NewSoftAssertions() *SoftAssertions {
+	soft := &SoftAssertions{}
+	soft.Gomega = gomega.NewGomega(soft.record)
+	return soft
+}
+
+func (s *SoftAssertions) record(message string, callerSkip ...int) {
+	skip := 0
+	if len(callerSkip) > 0 {
+		skip = callerSkip[0]
+	}
+
+	if _, file, line, ok := runtime.Caller(skip + 1); ok {
+		message = fmt.Sprintf("%s:%d\n%s", filepath.Base(file), line, message)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.failures = append(s.failures, message)
+}
+
+// Failures returns the failures collected so far.
+func (s *SoftAssertions) Failures() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]string{}, s.failures...)
+}
+
+// Verify fails the current test, using Gomega's fail handler, if any of the
+// assertions has failed. The failure message includes all collected failures.
+func (s *SoftAssertions) Verify() {
+	gomega.ExpectWithOffset(1, s.Failures()).To(&softMatcher{})
+}
+
+type softMatcher struct{}
+
+// Match is doing the actual matching for the collected failures.
+func (sm *softMatcher) Match(actual interface{}) (success bool, err error) {
+	return len(actual.([]string)) == 0, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (sm *softMatcher) FailureMessage(actual interface{}) (message string) {
+	failures := actual.([]string)
+
+	rendered := make([]string, len(failures))
+	for i, failure := range failures {
+		rendered[i] = fmt.Sprintf("Failure %d of %d at %s", i+1, len(failures), failure)
+	}
+
+	return fmt.Sprintf("%d soft assertions failed\n\n%s", len(failures), strings.Join(rendered, "\n\n"))
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (sm *softMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return "Expected soft assertions to fail"
+}
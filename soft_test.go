@@ -0,0 +1,54 @@
+package glager_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("SoftAssertions", func() {
+	var (
+		logger *TestLogger
+		soft   *SoftAssertions
+	)
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		logger.Info("start")
+		logger.Error("failed", errors.New("boom"))
+
+		soft = NewSoftAssertions()
+	})
+
+	It("does not fail if all assertions pass", func() {
+		soft.Expect(logger).To(ContainSequence(Info(Action("test.start"))))
+		soft.Expect(logger).To(ContainSequence(Error(AnyErr)))
+
+		Expect(soft.Failures()).To(BeEmpty())
+		Expect(InterceptGomegaFailures(soft.Verify)).To(BeEmpty())
+	})
+
+	It("collects all failures and reports them together", func() {
+		soft.Expect(logger).To(ContainSequence(Debug()))
+		soft.Expect(logger).To(ContainSequence(Info(Action("test.start"))))
+		soft.Expect(logger).To(ContainSequence(Fatal(AnyErr)))
+
+		Expect(soft.Failures()).To(HaveLen(2))
+		Expect(soft.Failures()[0]).To(HavePrefix("soft_test.go:"))
+		Expect(soft.Failures()[0]).To(ContainSubstring("Debug()"))
+		Expect(soft.Failures()[1]).To(ContainSubstring("Fatal(AnyErr)"))
+
+		failures := InterceptGomegaFailures(soft.Verify)
+		Expect(failures).To(HaveLen(1))
+		Expect(failures[0]).To(HavePrefix("2 soft assertions failed\n\nFailure 1 of 2 at soft_test.go:"))
+		Expect(failures[0]).To(ContainSubstring("Failure 2 of 2 at soft_test.go:"))
+	})
+
+	It("supports Eventually", func() {
+		soft.Eventually(logger, "10ms", "1ms").Should(ContainSequence(Debug()))
+		Expect(soft.Failures()).To(HaveLen(1))
+	})
+})
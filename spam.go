@@ -0,0 +1,103 @@
+package glager
+
+import (
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+type spamMatcher struct {
+	maxRepeats int
+	window     time.Duration
+	entry      *LogEntry
+	count      int
+}
+
+// HaveNoRepeatedErrors checks that identical error entries, i.e. error or
+// fatal entries with the same message and the same error, occur at most
+// maxRepeats times within any time window of the given duration. Use it to
+// verify that retry loops implement log suppression correctly.
+//
+// Example:
+//
+//	Expect(logger).To(HaveNoRepeatedErrors(3, time.Minute))
+func HaveNoRepeatedErrors(maxRepeats int, window time.Duration) types.GomegaMatcher {
+	return &spamMatcher{
+		maxRepeats: maxRepeats,
+		window:     window,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (sm *spamMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("HaveNoRepeatedErrors", actual)
+	if err != nil {
+		return false, err
+	}
+
+	sm.entry = nil
+	sm.count = 0
+
+	type errorKey struct {
+		message string
+		err     string
+	}
+
+	times := map[errorKey][]time.Time{}
+
+	for i, entry := range entries {
+		if severity(entry.LogLevel) < severity(lager.ERROR) {
+			continue
+		}
+
+		t, err := entry.Time()
+		if err != nil {
+			return false, err
+		}
+
+		key := errorKey{entry.Message, fmt.Sprint(entry.Data["error"])}
+
+		// only keep the occurrences that are still inside the window
+		occurrences := append(times[key], t)
+		for len(occurrences) > 0 && t.Sub(occurrences[0]) > sm.window {
+			occurrences = occurrences[1:]
+		}
+		times[key] = occurrences
+
+		if len(occurrences) > sm.maxRepeats {
+			sm.entry = &entries[i]
+			sm.count = len(occurrences)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (sm *spamMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected error entry\n%s\nto occur at most %d times within %s, got %d occurrences",
+		format.IndentString(sm.entry.String(), 1),
+		sm.maxRepeats,
+		sm.window,
+		sm.count,
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (sm *spamMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected an error entry to occur more than %d times within %s",
+		sm.maxRepeats,
+		sm.window,
+	)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (sm *spamMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
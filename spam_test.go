@@ -0,0 +1,67 @@
+package glager_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".HaveNoRepeatedErrors", func() {
+	errorLine := func(seconds int, message, err string) string {
+		return fmt.Sprintf(
+			`{"timestamp":"%d.000000000","source":"test","message":"%s","log_level":2,"data":{"error":"%s"}}`+"\n",
+			1500000000+seconds, message, err,
+		)
+	}
+
+	It("matches a log with a few repeated errors", func() {
+		logger := NewLogger("test")
+		for i := 0; i < 3; i++ {
+			logger.Error("retry", errors.New("connection refused"))
+		}
+
+		Expect(logger).To(HaveNoRepeatedErrors(3, time.Minute))
+		Expect(logger).ToNot(HaveNoRepeatedErrors(2, time.Minute))
+	})
+
+	It("only counts errors within the window", func() {
+		log := errorLine(0, "test.retry", "refused") +
+			errorLine(30, "test.retry", "refused") +
+			errorLine(65, "test.retry", "refused") +
+			errorLine(100, "test.retry", "refused")
+
+		Expect(strings.NewReader(log)).To(HaveNoRepeatedErrors(2, time.Minute))
+		Expect(strings.NewReader(log)).ToNot(HaveNoRepeatedErrors(2, 2*time.Minute))
+	})
+
+	It("distinguishes errors by message and error", func() {
+		log := errorLine(0, "test.retry", "refused") +
+			errorLine(1, "test.retry", "timeout") +
+			errorLine(2, "test.other", "refused")
+
+		Expect(strings.NewReader(log)).To(HaveNoRepeatedErrors(1, time.Minute))
+	})
+
+	It("ignores entries below error level", func() {
+		logger := NewLogger("test")
+		logger.Info("retry")
+		logger.Info("retry")
+
+		Expect(logger).To(HaveNoRepeatedErrors(1, time.Minute))
+	})
+
+	It("reports the repeated entry", func() {
+		log := errorLine(0, "test.retry", "refused") + errorLine(1, "test.retry", "refused")
+
+		matcher := HaveNoRepeatedErrors(1, time.Minute)
+		Expect(matcher.Match(strings.NewReader(log))).To(BeFalse())
+		Expect(matcher.FailureMessage(nil)).To(ContainSubstring("test.retry"))
+		Expect(matcher.FailureMessage(nil)).To(ContainSubstring("to occur at most 1 times within 1m0s, got 2 occurrences"))
+	})
+})
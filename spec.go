@@ -0,0 +1,230 @@
+package glager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"gopkg.in/yaml.v3"
+)
+
+// SpecEntry is the declarative representation of an expected log entry, e.g.
+// as read from a YAML or JSON file. Error and Fatal entries without an error
+// match any error, just like AnyErr does. Data values, including nested ones,
+// can be templated using PlaceholderUUID, PlaceholderURL, PlaceholderIP,
+// PlaceholderAny, and PlaceholderRegexp.
+type SpecEntry struct {
+	Level   string                 `json:"level" yaml:"level"`
+	Source  string                 `json:"source,omitempty" yaml:"source,omitempty"`
+	Message string                 `json:"message,omitempty" yaml:"message,omitempty"`
+	Error   string                 `json:"error,omitempty" yaml:"error,omitempty"`
+	Data    map[string]interface{} `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+// Entry converts the spec into an expected entry that can be used with the
+// HaveLogged and ContainSequence matchers.
+func (s SpecEntry) Entry() (ExpectedEntry, error) {
+	level, err := ParseLevel(s.Level)
+	if err != nil {
+		return logEntry{}, err
+	}
+
+	options := []Option{}
+	if s.Source != "" {
+		options = append(options, Source(s.Source))
+	}
+
+	if s.Message != "" {
+		options = append(options, Message(s.Message))
+	}
+
+	if s.Error != "" {
+		if level != LevelError && level != LevelFatal {
+			return logEntry{}, fmt.Errorf("Invalid error for %s entry. Only error and fatal entries can have an error.", level)
+		}
+		options = append(options, Data("error", s.Error))
+	}
+
+	for key, value := range s.Data {
+		expected, err := specValue(value)
+		if err != nil {
+			return logEntry{}, err
+		}
+		options = append(options, Data(key, expected))
+	}
+
+	return Entry(level.LogLevel(), options...), nil
+}
+
+// specValue replaces the placeholders in the given value, which may be nested,
+// by the corresponding matchers.
+func specValue(value interface{}) (interface{}, error) {
+	switch x := value.(type) {
+	case map[string]interface{}:
+		expected := make(map[string]interface{}, len(x))
+		for key, v := range x {
+			e, err := specValue(v)
+			if err != nil {
+				return nil, err
+			}
+			expected[key] = e
+		}
+		return expected, nil
+	case []interface{}:
+		expected := make([]interface{}, len(x))
+		for i, v := range x {
+			e, err := specValue(v)
+			if err != nil {
+				return nil, err
+			}
+			expected[i] = e
+		}
+		return expected, nil
+	case string:
+		switch {
+		case x == PlaceholderUUID:
+			return BeAUUID(), nil
+		case x == PlaceholderURL:
+			return BeAURL(), nil
+		case x == PlaceholderIP:
+			return BeAnIP(), nil
+		case x == PlaceholderAny:
+			return gomega.Not(gomega.BeNil()), nil
+		case strings.HasPrefix(x, placeholderRegexpPrefix) && strings.HasSuffix(x, placeholderSuffix):
+			pattern := strings.TrimSuffix(strings.TrimPrefix(x, placeholderRegexpPrefix), placeholderSuffix)
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("Invalid placeholder %q: %s.", x, err)
+			}
+			return gomega.MatchRegexp(pattern), nil
+		}
+	}
+
+	return value, nil
+}
+
+// ParseSequence parses a sequence of expected entries from the given JSON
+// array of SpecEntry objects.
+//
+// Example:
+//
+//	[
+//	  {"level": "info", "source": "app", "message": "app.start"},
+//	  {"level": "error", "message": "app.failed", "error": "boom"}
+//	]
+func ParseSequence(spec []byte) ([]SequenceEntry, error) {
+	specs := []SpecEntry{}
+	if err := json.Unmarshal(spec, &specs); err != nil {
+		return nil, fmt.Errorf("Invalid sequence spec: %s.", err)
+	}
+
+	return specSequence(specs)
+}
+
+// ParseSequenceYAML parses a sequence of expected entries from the given YAML
+// list of SpecEntry objects.
+//
+// Example:
+//
+//   - level: info
+//     source: app
+//     message: app.start
+//   - level: error
+//     message: app.failed
+//     error: boom
+func ParseSequenceYAML(spec []byte) ([]SequenceEntry, error) {
+	specs := []SpecEntry{}
+	if err := yaml.Unmarshal(spec, &specs); err != nil {
+		return nil, fmt.Errorf("Invalid sequence spec: %s.", err)
+	}
+
+	return specSequence(specs)
+}
+
+// SequenceFromJSON reads a sequence of expected entries from the given reader,
+// e.g. a fixture file, and parses it using ParseSequence.
+//
+// Example:
+//
+//	fixture, err := os.Open("testdata/startup_sequence.json")
+//	sequence, err := SequenceFromJSON(fixture)
+//	Expect(logger).To(ContainSequence(sequence...))
+func SequenceFromJSON(reader io.Reader) ([]SequenceEntry, error) {
+	spec, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSequence(spec)
+}
+
+// LoadSequence reads a sequence of expected entries from the given file. Files
+// with the extension ".yml" or ".yaml" are parsed using ParseSequenceYAML, all
+// others using ParseSequence.
+func LoadSequence(path string) ([]SequenceEntry, error) {
+	spec, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch filepath.Ext(path) {
+	case ".yml", ".yaml":
+		return ParseSequenceYAML(spec)
+	default:
+		return ParseSequence(spec)
+	}
+}
+
+// SequenceFromFile returns a ContainSequence matcher for the sequence read from
+// the given file using LoadSequence. Use it to maintain expected log contracts
+// as fixtures instead of Go code. The matcher fails with an error if the file
+// cannot be loaded.
+//
+// Example:
+//
+//	Expect(logger).To(SequenceFromFile("testdata/startup_sequence.yml"))
+func SequenceFromFile(path string) types.GomegaMatcher {
+	sequence, err := LoadSequence(path)
+	if err != nil {
+		return &errorMatcher{err: fmt.Errorf("SequenceFromFile failed to load %s: %s", path, err)}
+	}
+
+	return ContainSequence(sequence...)
+}
+
+// errorMatcher fails every match with the given error.
+type errorMatcher struct {
+	err error
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (em *errorMatcher) Match(actual interface{}) (success bool, err error) {
+	return false, em.err
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (em *errorMatcher) FailureMessage(actual interface{}) (message string) {
+	return em.err.Error()
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (em *errorMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return em.err.Error()
+}
+
+func specSequence(specs []SpecEntry) ([]SequenceEntry, error) {
+	sequence := make([]SequenceEntry, len(specs))
+	for i, spec := range specs {
+		entry, err := spec.Entry()
+		if err != nil {
+			return nil, fmt.Errorf("Invalid entry %d of sequence spec: %s", i, err)
+		}
+		sequence[i] = entry
+	}
+	return sequence, nil
+}
@@ -0,0 +1,192 @@
+package glager_test
+
+import (
+	"errors"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("sequence specs", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("app")
+		logger.Info("start", lager.Data{"port": 8080})
+		logger.Error("failed", errors.New("boom"))
+		logger.Error("exit", errors.New("bye"))
+	})
+
+	Describe("SpecEntry.Entry", func() {
+		It("converts the spec into an expected entry", func() {
+			entry, err := SpecEntry{
+				Level:   "info",
+				Source:  "app",
+				Message: "app.start",
+				Data:    map[string]interface{}{"port": 8080},
+			}.Entry()
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logger).To(HaveLogged(entry))
+			Expect(entry).To(Equal(Info(Source("app"), Message("app.start"), Data("port", 8080))))
+		})
+
+		It("matches any error if no error is specified", func() {
+			entry, err := SpecEntry{Level: "error"}.Entry()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entry).To(Equal(Error(AnyErr)))
+		})
+
+		It("matches the specified error", func() {
+			entry, err := SpecEntry{Level: "fatal", Error: "bye"}.Entry()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entry).To(Equal(Fatal(errors.New("bye"))))
+		})
+
+		It("rejects errors for levels other than error and fatal", func() {
+			_, err := SpecEntry{Level: "info", Error: "boom"}.Entry()
+			Expect(err).To(MatchError("Invalid error for info entry. Only error and fatal entries can have an error."))
+		})
+
+		It("rejects invalid levels", func() {
+			_, err := SpecEntry{Level: "loud"}.Entry()
+			Expect(err).To(MatchError(`Invalid log level "loud".`))
+		})
+	})
+
+	Describe(".ParseSequence", func() {
+		It("parses a JSON array of specs", func() {
+			sequence, err := ParseSequence([]byte(`[
+				{"level": "info", "message": "app.start", "data": {"port": 8080}},
+				{"level": "error", "message": "app.failed", "error": "boom"},
+				{"level": "error", "error": "bye"}
+			]`))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sequence).To(HaveLen(3))
+			Expect(logger).To(ContainSequence(sequence...))
+		})
+
+		It("returns an error for invalid JSON", func() {
+			_, err := ParseSequence([]byte(`{"level": "info"}`))
+			Expect(err).To(MatchError(HavePrefix("Invalid sequence spec: ")))
+		})
+
+		It("returns an error for invalid entries", func() {
+			_, err := ParseSequence([]byte(`[{"level": "info"}, {"level": "loud"}]`))
+			Expect(err).To(MatchError(`Invalid entry 1 of sequence spec: Invalid log level "loud".`))
+		})
+	})
+
+	Describe(".SequenceFromJSON", func() {
+		It("parses the specs read from the reader", func() {
+			sequence, err := SequenceFromJSON(strings.NewReader(`[
+				{"level": "info", "message": "app.start", "data": {"port": 8080}},
+				{"level": "error", "error": "boom"}
+			]`))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logger).To(ContainSequence(sequence...))
+		})
+
+		It("returns an error for invalid specs", func() {
+			_, err := SequenceFromJSON(strings.NewReader(`{"level": "info"}`))
+			Expect(err).To(MatchError(HavePrefix("Invalid sequence spec: ")))
+		})
+	})
+
+	Describe("placeholders", func() {
+		BeforeEach(func() {
+			logger = NewLogger("app")
+			logger.Info("request", lager.Data{"id": "req-42", "started": 1700000000, "peer": map[string]interface{}{"ip": "10.0.0.1", "port": 50412}})
+		})
+
+		It("match any value", func() {
+			sequence, err := ParseSequence([]byte(`[{"level": "info", "data": {"started": "{{any}}"}}]`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logger).To(ContainSequence(sequence...))
+
+			sequence, err = ParseSequence([]byte(`[{"level": "info", "data": {"finished": "{{any}}"}}]`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logger).ToNot(ContainSequence(sequence...))
+		})
+
+		It("match regular expressions", func() {
+			sequence, err := ParseSequence([]byte(`[{"level": "info", "data": {"id": "` + PlaceholderRegexp(`^req-\\d+$`) + `"}}]`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logger).To(ContainSequence(sequence...))
+
+			sequence, err = ParseSequence([]byte(`[{"level": "info", "data": {"id": "{{regexp:^job-}}"}}]`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logger).ToNot(ContainSequence(sequence...))
+		})
+
+		It("are replaced in nested values", func() {
+			sequence, err := ParseSequenceYAML([]byte(`
+- level: info
+  data:
+    peer:
+      ip: "{{ip}}"
+      port: "{{any}}"
+`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logger).To(ContainSequence(sequence...))
+		})
+
+		It("reject invalid regular expressions", func() {
+			_, err := ParseSequence([]byte(`[{"level": "info", "data": {"id": "{{regexp:(}}"}}]`))
+			Expect(err).To(MatchError(HavePrefix(`Invalid entry 0 of sequence spec: Invalid placeholder "{{regexp:(}}": `)))
+		})
+	})
+
+	Describe(".ParseSequenceYAML", func() {
+		It("parses a YAML list of specs", func() {
+			sequence, err := ParseSequenceYAML([]byte(`
+- level: info
+  message: app.start
+  data:
+    port: 8080
+- level: error
+  error: boom
+`))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sequence).To(HaveLen(2))
+			Expect(logger).To(ContainSequence(sequence...))
+		})
+
+		It("returns an error for invalid YAML", func() {
+			_, err := ParseSequenceYAML([]byte(`level: info`))
+			Expect(err).To(MatchError(HavePrefix("Invalid sequence spec: ")))
+		})
+	})
+
+	Describe(".SequenceFromFile", func() {
+		BeforeEach(func() {
+			logger = NewLogger("app")
+			logger.Info("start", lager.Data{"port": 8080})
+			logger.Info("listening")
+			logger.Error("failed", errors.New("boom"))
+		})
+
+		It("matches the sequence read from a YAML file", func() {
+			Expect(logger).To(SequenceFromFile("testdata/startup_sequence.yml"))
+		})
+
+		It("does not match a log that does not contain the sequence", func() {
+			logger = NewLogger("app")
+			logger.Info("start", lager.Data{"port": 8080})
+			Expect(logger).ToNot(SequenceFromFile("testdata/startup_sequence.yml"))
+		})
+
+		It("fails with an error if the file cannot be loaded", func() {
+			_, err := SequenceFromFile("testdata/missing.yml").Match(logger)
+			Expect(err).To(MatchError(HavePrefix("SequenceFromFile failed to load testdata/missing.yml: ")))
+		})
+	})
+})
@@ -0,0 +1,87 @@
+package glager
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Stream is an incrementally parsed view of a live log, e.g. the output of a
+// service under test. Every time it is matched, a Stream reads whatever has
+// been written to the underlying reader since the last match and only parses
+// the new, complete entries. Entries that have been parsed before are reused.
+// This keeps polling a long log using Eventually cheap.
+//
+// Streams are safe for concurrent use and can be matched any number of times.
+// The underlying reader is read until it returns io.EOF on every match, i.e.
+// it must not block, e.g. a file that is being appended to.
+type Stream struct {
+	reader   io.Reader
+	contents []byte
+	parsed   int
+	entries  logEntries
+	lock     sync.Mutex
+}
+
+// NewStream returns a new Stream reading from the given reader.
+//
+// Example:
+//
+//	stream := NewStream(logFile)
+//	Eventually(stream).Should(ContainSequence(
+//	  Info(Action("app.start")),
+//	  Info(Action("app.ready")),
+//	))
+func NewStream(reader io.Reader) *Stream {
+	return &Stream{
+		reader: reader,
+	}
+}
+
+// Contents implements ContentsProvider. It returns everything that has been
+// read from the underlying reader so far.
+func (s *Stream) Contents() []byte {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.read()
+	return s.contents
+}
+
+// read appends all data that is currently available to the contents of the
+// stream. Read errors other than io.EOF are ignored, the data read so far is
+// retained.
+func (s *Stream) read() {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := s.reader.Read(buf)
+		s.contents = append(s.contents, buf[:n]...)
+		if err != nil || n == 0 {
+			return
+		}
+	}
+}
+
+// parse reads all available data and returns the entries of the stream. An
+// entry that is still being written is ignored until it is complete.
+func (s *Stream) parse() (logEntries, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.read()
+
+	complete := bytes.LastIndexByte(s.contents[s.parsed:], '\n')
+	if complete < 0 {
+		return s.entries, nil
+	}
+
+	entries, _, err := decodeEntries(bytes.NewReader(s.contents[s.parsed : s.parsed+complete+1]))
+	if err != nil {
+		return nil, err
+	}
+
+	s.entries = append(s.entries, entries...)
+	s.parsed += complete + 1
+
+	return s.entries, nil
+}
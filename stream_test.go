@@ -0,0 +1,94 @@
+package glager_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("Stream", func() {
+	var (
+		log    *bytes.Buffer
+		logger lager.Logger
+		stream *Stream
+	)
+
+	BeforeEach(func() {
+		log = &bytes.Buffer{}
+		logger = lager.NewLogger("test")
+		logger.RegisterSink(lager.NewWriterSink(log, lager.DEBUG))
+		stream = NewStream(log)
+	})
+
+	It("can be matched repeatedly", func() {
+		logger.Info("start")
+
+		Expect(stream).To(ContainSequence(Info(Action("test.start"))))
+		Expect(stream).To(ContainSequence(Info(Action("test.start"))))
+	})
+
+	It("picks up entries written after the last match", func() {
+		logger.Info("start")
+		Expect(stream).ToNot(ContainSequence(Info(Action("test.start")), Info(Action("test.ready"))))
+
+		logger.Info("ready")
+		Expect(stream).To(ContainSequence(Info(Action("test.start")), Info(Action("test.ready"))))
+	})
+
+	It("ignores an entry that is still being written", func() {
+		logger.Info("start")
+		log.WriteString(`{"timestamp":"1.0","source":"test","message":"test.rea`)
+
+		Expect(stream).To(ContainSequence(Info(Action("test.start"))))
+		Expect(stream).ToNot(ContainSequence(Info(Action("test.ready"))))
+
+		log.WriteString(`dy","log_level":1,"data":{}}` + "\n")
+		Expect(stream).To(ContainSequence(Info(Action("test.start")), Info(Action("test.ready"))))
+	})
+
+	It("tails a file that is being appended to", func() {
+		dir, err := os.MkdirTemp("", "glager")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "app.log")
+		file, err := os.Create(path)
+		Expect(err).ToNot(HaveOccurred())
+		defer file.Close()
+
+		reader, err := os.Open(path)
+		Expect(err).ToNot(HaveOccurred())
+		defer reader.Close()
+
+		fileLogger := lager.NewLogger("app")
+		fileLogger.RegisterSink(lager.NewWriterSink(file, lager.DEBUG))
+		stream := NewStream(reader)
+
+		go func() {
+			defer GinkgoRecover()
+			fileLogger.Info("start")
+			fileLogger.Info("ready")
+		}()
+
+		Eventually(stream).Should(ContainSequence(Info(Action("app.start")), Info(Action("app.ready"))))
+	})
+
+	It("can be passed to other matchers", func() {
+		logger.Info("start")
+		Expect(stream).To(BeNDJSON())
+		Expect(stream.Contents()).To(ContainSubstring("test.start"))
+	})
+
+	It("returns an error for malformed entries", func() {
+		log.WriteString("not json\n")
+		_, err := ContainSequence(Info()).Match(stream)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,78 @@
+package glager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// summary renders which of the expected entries have been matched, along with
+// histograms of the levels and sources of the actual log. This is often enough
+// to spot issues like a component that never got past startup at a glance.
+func (lm *logMatcher) summary() string {
+	steps := make([]string, len(lm.expected))
+	for n := range lm.expected {
+		switch {
+		case n < len(lm.matched):
+			steps[n] = fmt.Sprintf("%d: matched entry %d", n, lm.matched[n])
+		case n == len(lm.matched):
+			steps[n] = fmt.Sprintf("%d: not found", n)
+		default:
+			steps[n] = fmt.Sprintf("%d: not checked", n)
+		}
+	}
+
+	levels, sources := lm.counts()
+
+	return fmt.Sprintf(
+		"\nSequence:\n\t%s\nLog levels: %s\nLog sources: %s",
+		strings.Join(steps, "\n\t"),
+		levelHistogram(levels),
+		sourceHistogram(sources),
+	)
+}
+
+// counts returns the number of actual entries per level and per source.
+func (lm *logMatcher) counts() (map[lager.LogLevel]int, map[string]int) {
+	if lm.stream != nil {
+		return lm.stream.levels, lm.stream.sources
+	}
+
+	levels, sources := map[lager.LogLevel]int{}, map[string]int{}
+	for _, entry := range lm.actual {
+		levels[entry.LogLevel]++
+		sources[entry.Source]++
+	}
+	return levels, sources
+}
+
+func levelHistogram(counts map[lager.LogLevel]int) string {
+	levels := make([]lager.LogLevel, 0, len(counts))
+	for level := range counts {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(a, b int) bool { return levels[a] < levels[b] })
+
+	buckets := make([]string, len(levels))
+	for i, level := range levels {
+		buckets[i] = fmt.Sprintf("%s=%d", levelName(level), counts[level])
+	}
+	return histogram(buckets)
+}
+
+func sourceHistogram(counts map[string]int) string {
+	buckets := []string{}
+	for _, source := range sortedKeys(counts) {
+		buckets = append(buckets, fmt.Sprintf("%q=%d", source, counts[source]))
+	}
+	return histogram(buckets)
+}
+
+func histogram(buckets []string) string {
+	if len(buckets) == 0 {
+		return "none"
+	}
+	return strings.Join(buckets, ", ")
+}
@@ -0,0 +1,42 @@
+package glager_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("failure summary", func() {
+	It("summarizes the sequence and the log", func() {
+		logger := NewLogger("server")
+		logger.Info("starting")
+		logger.Debug("config")
+		logger.Error("bind", errors.New("address in use"))
+
+		matcher := WithVerbosity(VerbositySummary, ContainSequence(
+			Info(Action("server.starting")),
+			Info(Action("server.started")),
+			Info(Action("server.listening")),
+		))
+
+		Expect(matcher.Match(logger)).To(BeFalse())
+		Expect(matcher.FailureMessage(logger)).To(HaveSuffix(`
+Sequence:
+	0: matched entry 0
+	1: not found
+	2: not checked
+Log levels: debug=1, info=1, error=1
+Log sources: "server"=3`))
+	})
+
+	It("handles empty logs", func() {
+		logger := NewLogger("server")
+
+		matcher := ContainSequence(Info())
+		Expect(matcher.Match(logger)).To(BeFalse())
+		Expect(matcher.FailureMessage(logger)).To(HaveSuffix("Log levels: none\nLog sources: none"))
+	})
+})
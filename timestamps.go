@@ -0,0 +1,149 @@
+package glager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// LoggedAfter specifies that a given log entry must have been logged after the
+// given time.
+func LoggedAfter(t time.Time) Option {
+	return loggedAt(fmt.Sprintf("logged after %s", t.Format(time.RFC3339Nano)), func(logged time.Time) bool {
+		return logged.After(t)
+	})
+}
+
+// LoggedBefore specifies that a given log entry must have been logged before
+// the given time.
+func LoggedBefore(t time.Time) Option {
+	return loggedAt(fmt.Sprintf("logged before %s", t.Format(time.RFC3339Nano)), func(logged time.Time) bool {
+		return logged.Before(t)
+	})
+}
+
+// LoggedWithin specifies that a given log entry must have been logged within
+// the given duration from the given start time, both ends included.
+//
+// Example:
+//
+//	start := time.Now()
+//	server.Start(logger)
+//	Expect(logger).To(HaveLogged(Info(Action("server.ready"), LoggedWithin(start, 5*time.Second))))
+func LoggedWithin(start time.Time, d time.Duration) Option {
+	description := fmt.Sprintf("logged within %s from %s", d, start.Format(time.RFC3339Nano))
+	return loggedAt(description, func(logged time.Time) bool {
+		return !logged.Before(start) && !logged.After(start.Add(d))
+	})
+}
+
+// loggedAt returns a check of the timestamp of an entry. Invalid timestamps
+// cause the matcher to fail with an error.
+func loggedAt(description string, match func(logged time.Time) bool) Option {
+	return withCheck(description, func(actual LogEntry) (bool, error) {
+		logged, err := actual.Time()
+		if err != nil {
+			return false, err
+		}
+		return match(logged), nil
+	})
+}
+
+type monotonicMatcher struct {
+	perSource bool
+	previous  *LogEntry
+	offending *LogEntry
+	index     int
+}
+
+// HaveMonotonicTimestamps checks that the timestamps of the entries in the log
+// never decrease, i.e. that entries have been written in the order they have
+// been logged. Use it to catch buffering or interleaving bugs of sinks.
+// Invalid timestamps cause the matcher to fail with an error.
+//
+// Example:
+//
+//	Expect(buffer).To(HaveMonotonicTimestamps())
+func HaveMonotonicTimestamps() types.GomegaMatcher {
+	return &monotonicMatcher{}
+}
+
+// HaveMonotonicTimestampsPerSource is like HaveMonotonicTimestamps, but only
+// compares the timestamps of entries with the same source. Use it for logs
+// that interleave the output of several components, each of which is expected
+// to write its entries in order.
+func HaveMonotonicTimestampsPerSource() types.GomegaMatcher {
+	return &monotonicMatcher{perSource: true}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (mm *monotonicMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries(mm.name(), actual)
+	if err != nil {
+		return false, err
+	}
+
+	mm.previous, mm.offending = nil, nil
+
+	latest := map[string]int{}
+	for i, entry := range entries {
+		logged, err := entry.Time()
+		if err != nil {
+			return false, err
+		}
+
+		var source string
+		if mm.perSource {
+			source = entry.Source
+		}
+
+		if j, found := latest[source]; found {
+			previous, _ := entries[j].Time()
+			if logged.Before(previous) {
+				mm.previous, mm.offending, mm.index = &entries[j], &entries[i], i
+				return false, nil
+			}
+		}
+
+		latest[source] = i
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (mm *monotonicMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected log entries%s to have non-decreasing timestamps, entry %d has been logged before\n%s\nfound\n%s",
+		mm.scope(),
+		mm.index,
+		format.IndentString(mm.previous.String(), 1),
+		format.IndentString(mm.offending.String(), 1),
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (mm *monotonicMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected log entries%s not to have non-decreasing timestamps", mm.scope())
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (mm *monotonicMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+func (mm *monotonicMatcher) name() string {
+	if mm.perSource {
+		return "HaveMonotonicTimestampsPerSource"
+	}
+	return "HaveMonotonicTimestamps"
+}
+
+func (mm *monotonicMatcher) scope() string {
+	if mm.perSource {
+		return " of every source"
+	}
+	return ""
+}
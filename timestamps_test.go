@@ -0,0 +1,92 @@
+package glager_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("timestamp options", func() {
+	const log = `{"timestamp":"100.0","source":"test","message":"test.failed","log_level":1,"data":{}}
+{"timestamp":"107.5","source":"test","message":"test.retry","log_level":1,"data":{}}
+`
+
+	It("matches entries logged after a given time", func() {
+		Expect(strings.NewReader(log)).To(ContainSequence(Info(Action("test.retry"), LoggedAfter(time.Unix(105, 0)))))
+		Expect(strings.NewReader(log)).ToNot(ContainSequence(Info(Action("test.failed"), LoggedAfter(time.Unix(100, 0)))))
+	})
+
+	It("matches entries logged before a given time", func() {
+		Expect(strings.NewReader(log)).To(ContainSequence(Info(Action("test.failed"), LoggedBefore(time.Unix(101, 0)))))
+		Expect(strings.NewReader(log)).ToNot(ContainSequence(Info(Action("test.retry"), LoggedBefore(time.Unix(107, 0)))))
+	})
+
+	It("matches entries logged within a given duration", func() {
+		Expect(strings.NewReader(log)).To(ContainSequence(
+			Info(Action("test.failed"), LoggedWithin(time.Unix(100, 0), 0)),
+			Info(Action("test.retry"), LoggedWithin(time.Unix(100, 0), 10*time.Second)),
+		))
+		Expect(strings.NewReader(log)).ToNot(ContainSequence(Info(Action("test.retry"), LoggedWithin(time.Unix(100, 0), 5*time.Second))))
+	})
+
+	It("returns an error for invalid timestamps", func() {
+		_, err := ContainSequence(Info(LoggedAfter(time.Unix(0, 0)))).Match(strings.NewReader(
+			`{"timestamp":"yesterday","source":"test","message":"test.start","log_level":1,"data":{}}` + "\n",
+		))
+		Expect(err).To(MatchError(`Invalid timestamp "yesterday".`))
+	})
+})
+
+var _ = Describe("timestamp matchers", func() {
+	const log = `{"timestamp":"100.0","source":"api","message":"api.request","log_level":1,"data":{}}
+{"timestamp":"100.0","source":"api","message":"api.handle","log_level":1,"data":{}}
+{"timestamp":"100.5","source":"worker","message":"worker.start","log_level":1,"data":{}}
+{"timestamp":"101.0","source":"api","message":"api.response","log_level":1,"data":{}}
+`
+
+	Describe(".HaveMonotonicTimestamps", func() {
+		It("matches a log with non-decreasing timestamps", func() {
+			Expect(strings.NewReader(log)).To(HaveMonotonicTimestamps())
+		})
+
+		It("does not match a log with entries written out of order", func() {
+			log := log + `{"timestamp":"100.5","source":"api","message":"api.done","log_level":1,"data":{}}` + "\n"
+
+			matcher := HaveMonotonicTimestamps()
+			Expect(matcher.Match(strings.NewReader(log))).To(BeFalse())
+
+			message := matcher.FailureMessage(strings.NewReader(log))
+			Expect(message).To(ContainSubstring("to have non-decreasing timestamps, entry 4 has been logged before"))
+			Expect(message).To(ContainSubstring("api.response"))
+			Expect(message).To(ContainSubstring("api.done"))
+		})
+
+		It("returns an error for invalid timestamps", func() {
+			_, err := HaveMonotonicTimestamps().Match(strings.NewReader(
+				`{"timestamp":"yesterday","source":"test","message":"test.start","log_level":1,"data":{}}` + "\n",
+			))
+			Expect(err).To(MatchError(`Invalid timestamp "yesterday".`))
+		})
+	})
+
+	Describe(".HaveMonotonicTimestampsPerSource", func() {
+		It("only compares entries of the same source", func() {
+			log := log + `{"timestamp":"100.8","source":"worker","message":"worker.stop","log_level":1,"data":{}}` + "\n"
+
+			Expect(strings.NewReader(log)).ToNot(HaveMonotonicTimestamps())
+			Expect(strings.NewReader(log)).To(HaveMonotonicTimestampsPerSource())
+		})
+
+		It("does not match a source with entries written out of order", func() {
+			log := log + `{"timestamp":"100.5","source":"api","message":"api.done","log_level":1,"data":{}}` + "\n"
+
+			matcher := HaveMonotonicTimestampsPerSource()
+			Expect(matcher.Match(strings.NewReader(log))).To(BeFalse())
+			Expect(matcher.FailureMessage(strings.NewReader(log))).To(ContainSubstring("Expected log entries of every source to have non-decreasing timestamps, entry 4"))
+		})
+	})
+})
@@ -0,0 +1,120 @@
+package glager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+type unorderedMatcher struct {
+	expected []logEntry
+	actual   logEntries
+	missing  []int
+}
+
+// ContainAll checks if every one of the specified entries appears inside the
+// log, in any order. Every expected entry has to match a different actual
+// entry. Use it for entries logged concurrently, whose order is not
+// deterministic.
+//
+// Example:
+//
+//	Expect(logger).To(ContainAll(
+//	  Info(Action("worker.done"), Data("worker", 1)),
+//	  Info(Action("worker.done"), Data("worker", 2)),
+//	))
+func ContainAll(expected ...UnorderedEntry) types.GomegaMatcher {
+	return &unorderedMatcher{
+		expected: expandRepetitions(sequenceOf(expected)),
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (um *unorderedMatcher) Match(actual interface{}) (success bool, err error) {
+	um.actual, err = parseEntries("ContainAll", actual)
+	if err != nil {
+		return false, err
+	}
+
+	candidates := make([][]int, len(um.expected))
+	for n, expected := range um.expected {
+		for i, entry := range um.actual {
+			containsEntry, err := entry.contains(expected)
+			if err != nil {
+				return false, err
+			}
+
+			if containsEntry {
+				candidates[n] = append(candidates[n], i)
+			}
+		}
+	}
+
+	um.missing = unassigned(candidates, len(um.actual))
+	return len(um.missing) == 0, nil
+}
+
+// unassigned assigns every expected entry a different one of its candidate
+// actual entries, maximizing the number of assigned expected entries. It
+// returns the indices of the expected entries that could not be assigned.
+func unassigned(candidates [][]int, actual int) []int {
+	owner := make([]int, actual)
+	for i := range owner {
+		owner[i] = -1
+	}
+
+	var assign func(n int, visited []bool) bool
+	assign = func(n int, visited []bool) bool {
+		for _, i := range candidates[n] {
+			if visited[i] {
+				continue
+			}
+			visited[i] = true
+
+			if owner[i] < 0 || assign(owner[i], visited) {
+				owner[i] = n
+				return true
+			}
+		}
+		return false
+	}
+
+	missing := []int{}
+	for n := range candidates {
+		if !assign(n, make([]bool, actual)) {
+			missing = append(missing, n)
+		}
+	}
+	return missing
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (um *unorderedMatcher) FailureMessage(actual interface{}) (message string) {
+	missing := make([]string, len(um.missing))
+	for i, n := range um.missing {
+		missing[i] = fmt.Sprintf("%d: %s", n, um.expected[n].GomegaString())
+	}
+
+	return fmt.Sprintf(
+		"Expected\n\t%s\nto contain all log entries\n\t%s\nMissing entries:\n\t%s",
+		format.Object(um.actual, 0),
+		renderSequence(um.expected),
+		strings.Join(missing, "\n\t"),
+	)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (um *unorderedMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected\n\t%s\nnot to contain all log entries\n\t%s",
+		format.Object(um.actual, 0),
+		renderSequence(um.expected),
+	)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (um *unorderedMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
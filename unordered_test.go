@@ -0,0 +1,67 @@
+package glager_test
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".ContainAll", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+	})
+
+	It("matches entries logged in any order", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				logger.Info("done", lager.Data{"worker": worker})
+			}(i)
+		}
+		wg.Wait()
+
+		Expect(logger).To(ContainAll(
+			Info(Action("test.done"), Data("worker", 4)),
+			Info(Action("test.done"), Data("worker", 0)),
+			Info(Action("test.done"), Data("worker", 2)),
+		))
+	})
+
+	It("requires every expected entry to match a different entry", func() {
+		logger.Info("done")
+
+		Expect(logger).To(ContainAll(Info(Action("test.done"))))
+		Expect(logger).ToNot(ContainAll(Info(Action("test.done")), Info(Action("test.done"))))
+	})
+
+	It("assigns overlapping entries optimally", func() {
+		logger.Info("start")
+		logger.Info("done")
+
+		Expect(logger).To(ContainAll(Info(), Info(Action("test.start"))))
+	})
+
+	It("lists the missing entries", func() {
+		logger.Info("start")
+
+		matcher := ContainAll(Info(Action("test.start")), Info(Action("test.done")))
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		message := matcher.FailureMessage(logger)
+		Expect(message).To(HaveSuffix("Missing entries:\n\t1: Info(Message(\"test.done\"))"))
+	})
+
+	It("returns an error for invalid actuals", func() {
+		_, err := ContainAll(Info()).Match(42)
+		Expect(err).To(MatchError(HavePrefix("ContainAll must be passed")))
+	})
+})
@@ -0,0 +1,105 @@
+package glager
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// ParseError is returned by the matchers if a line of the log cannot be parsed
+// as log entry, e.g. because the log is interleaved with plain-text output, or
+// cannot be converted by the Format of the log.
+type ParseError struct {
+	// Line is the number of the line that could not be parsed, starting at 1.
+	Line int
+	// Content is the content of the line.
+	Content string
+	// Err is the error that occurred while parsing the line.
+	Err error
+}
+
+// Error implements error.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("Failed to parse line %d of log: %s. Got:\n%s", e.Line, strings.TrimSuffix(e.Err.Error(), "."), e.Content)
+}
+
+// Unwrap returns the error that occurred while parsing the line.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// unparseableLine returns a ParseError for the first non-empty line of the
+// given contents following the given offset.
+func unparseableLine(contents []byte, offset int64, err error) error {
+	start := int(offset)
+	for start < len(contents) && (contents[start] == ' ' || contents[start] == '\t' || contents[start] == '\n' || contents[start] == '\r') {
+		start++
+	}
+
+	end := bytes.IndexByte(contents[start:], '\n')
+	if end < 0 {
+		end = len(contents)
+	} else {
+		end += start
+	}
+
+	return &ParseError{
+		Line:    bytes.Count(contents[:start], []byte("\n")) + 1,
+		Content: string(contents[start:end]),
+		Err:     err,
+	}
+}
+
+// unparseableLineAt is like unparseableLine, but reads the line from the given
+// reader, starting at the given base offset, instead of from retained contents.
+func unparseableLineAt(reader io.ReaderAt, base, offset int64, err error) error {
+	buffered := bufio.NewReader(io.NewSectionReader(reader, base, math.MaxInt64-base))
+
+	var end int64
+	for line := 1; ; line++ {
+		content, readErr := buffered.ReadString('\n')
+
+		start := end
+		end += int64(len(content))
+		if end <= offset && readErr == nil {
+			continue
+		}
+
+		switch {
+		case end <= offset:
+			content = ""
+		case start < offset:
+			content = content[offset-start:]
+		}
+
+		content = strings.TrimLeft(content, " \t\r\n")
+		if content != "" || readErr != nil {
+			return &ParseError{Line: line, Content: strings.TrimSuffix(content, "\n"), Err: err}
+		}
+	}
+}
+
+// IgnoringUnparseableLines skips all lines of the given log that cannot be
+// parsed as log entries, so that it can be passed to any of the matchers. Use
+// it for logs that are interleaved with plain-text output, e.g. panics or
+// prints of libraries in the stdout of a process under test. The log can be
+// anything that is accepted by the ContainSequence matcher.
+//
+// Example:
+//
+//	Eventually(IgnoringUnparseableLines(session.Out)).Should(ContainSequence(
+//	  Info(Action("app.ready")),
+//	))
+func IgnoringUnparseableLines(log interface{}) formattedLog {
+	return WithFormat(func(line []byte) ([]byte, error) {
+		var entry LogEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, nil
+		}
+		return line, nil
+	}, log)
+}
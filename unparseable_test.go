@@ -0,0 +1,85 @@
+package glager_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("unparseable lines", func() {
+	const log = `{"timestamp":"1.0","source":"app","message":"app.start","log_level":1,"data":{}}
+listening on :8080
+{"timestamp":"2.0","source":"app","message":"app.ready","log_level":1,"data":{}}
+panic: runtime error
+42
+`
+
+	It("reports the line that could not be parsed", func() {
+		_, err := ContainSequence(Info(Action("app.ready"))).Match(strings.NewReader(log))
+
+		var parseErr *ParseError
+		Expect(errors.As(err, &parseErr)).To(BeTrue())
+		Expect(parseErr.Line).To(Equal(2))
+		Expect(parseErr.Content).To(Equal("listening on :8080"))
+		Expect(err).To(MatchError(HavePrefix("Failed to parse line 2 of log: invalid character")))
+	})
+
+	It("reports the line of readers that cannot be read again", func() {
+		_, err := ContainSequence(Info(Action("app.ready"))).Match(bufio.NewReader(strings.NewReader(log)))
+
+		var parseErr *ParseError
+		Expect(errors.As(err, &parseErr)).To(BeTrue())
+		Expect(parseErr.Line).To(Equal(2))
+		Expect(parseErr.Content).To(Equal("listening on :8080"))
+	})
+
+	It("reports the line relative to the offset of seekable readers", func() {
+		reader := strings.NewReader("garbage\n" + log)
+		_, err := reader.Seek(int64(len("garbage\n")), io.SeekStart)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = ContainSequence(Info(Action("app.ready"))).Match(reader)
+
+		var parseErr *ParseError
+		Expect(errors.As(err, &parseErr)).To(BeTrue())
+		Expect(parseErr.Line).To(Equal(2))
+		Expect(parseErr.Content).To(Equal("listening on :8080"))
+	})
+
+	It("reports the line if the log is wrapped using WithFormat", func() {
+		format := func(line []byte) ([]byte, error) { return line, nil }
+
+		_, err := ContainSequence(Info(Action("app.ready"))).Match(WithFormat(format, strings.NewReader(log)))
+		Expect(err).To(MatchError(ContainSubstring("Failed to parse line 2 of log")))
+	})
+
+	Describe("IgnoringUnparseableLines", func() {
+		It("skips lines that cannot be parsed", func() {
+			Expect(IgnoringUnparseableLines(strings.NewReader(log))).To(ContainSequence(
+				Info(Action("app.start")),
+				Info(Action("app.ready")),
+			))
+
+			entries, err := Entries(IgnoringUnparseableLines(strings.NewReader(log)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+		})
+
+		It("picks up a trailing entry once it is complete", func() {
+			buffer := gbytes.NewBuffer()
+			buffer.Write([]byte("starting\n" + `{"timestamp":"1.0","source":"app","message":"app.st`))
+
+			Expect(IgnoringUnparseableLines(buffer)).ToNot(ContainSequence(Info(Action("app.start"))))
+
+			buffer.Write([]byte(`art","log_level":1,"data":{}}` + "\n"))
+			Expect(IgnoringUnparseableLines(buffer)).To(ContainSequence(Info(Action("app.start"))))
+		})
+	})
+})
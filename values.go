@@ -0,0 +1,74 @@
+package glager
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+type formatMatcher struct {
+	name  string
+	valid func(string) bool
+}
+
+// BeAUUID succeeds if actual is a string containing a UUID in its canonical
+// textual representation. It can be used as a Data value.
+//
+// Example:
+//
+//	Info(Data("guid", BeAUUID()))
+func BeAUUID() types.GomegaMatcher {
+	return &formatMatcher{
+		name:  "a UUID",
+		valid: uuidRegexp.MatchString,
+	}
+}
+
+// BeAURL succeeds if actual is a string containing an absolute URL, i.e. a URL
+// with a scheme and a host. It can be used as a Data value.
+func BeAURL() types.GomegaMatcher {
+	return &formatMatcher{
+		name: "a URL",
+		valid: func(s string) bool {
+			u, err := url.Parse(s)
+			return err == nil && u.Scheme != "" && u.Host != ""
+		},
+	}
+}
+
+// BeAnIP succeeds if actual is a string containing an IPv4 or IPv6 address. It
+// can be used as a Data value.
+func BeAnIP() types.GomegaMatcher {
+	return &formatMatcher{
+		name: "an IP address",
+		valid: func(s string) bool {
+			return net.ParseIP(s) != nil
+		},
+	}
+}
+
+// Match is doing the actual matching for a given value.
+func (fm *formatMatcher) Match(actual interface{}) (success bool, err error) {
+	s, ok := actual.(string)
+	if !ok {
+		return false, nil
+	}
+
+	return fm.valid(s), nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (fm *formatMatcher) FailureMessage(actual interface{}) (message string) {
+	return format.Message(actual, fmt.Sprintf("to be %s", fm.name))
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (fm *formatMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return format.Message(actual, fmt.Sprintf("not to be %s", fm.name))
+}
@@ -0,0 +1,80 @@
+package glager_test
+
+import (
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("value matchers", func() {
+	Describe(".BeAUUID", func() {
+		It("matches UUIDs", func() {
+			Expect("0b4e7a0e-5fe1-4b6a-8b16-9c3ff2e5e7a2").To(BeAUUID())
+			Expect("0B4E7A0E-5FE1-4B6A-8B16-9C3FF2E5E7A2").To(BeAUUID())
+		})
+
+		It("does not match other values", func() {
+			Expect("0b4e7a0e-5fe1-4b6a-8b16").ToNot(BeAUUID())
+			Expect("not-a-uuid").ToNot(BeAUUID())
+			Expect(42).ToNot(BeAUUID())
+		})
+	})
+
+	Describe(".BeAURL", func() {
+		It("matches absolute URLs", func() {
+			Expect("https://example.com/v2/apps?page=1").To(BeAURL())
+			Expect("http://10.0.0.1:8080").To(BeAURL())
+		})
+
+		It("does not match other values", func() {
+			Expect("/v2/apps").ToNot(BeAURL())
+			Expect("example.com").ToNot(BeAURL())
+			Expect(true).ToNot(BeAURL())
+		})
+	})
+
+	Describe(".BeAnIP", func() {
+		It("matches IPv4 and IPv6 addresses", func() {
+			Expect("10.0.0.1").To(BeAnIP())
+			Expect("::1").To(BeAnIP())
+		})
+
+		It("does not match other values", func() {
+			Expect("10.0.0.256").ToNot(BeAnIP())
+			Expect("localhost").ToNot(BeAnIP())
+			Expect(nil).ToNot(BeAnIP())
+		})
+	})
+
+	It("describes the expected format in failure messages", func() {
+		Expect(BeAUUID().FailureMessage("foo")).To(ContainSubstring("to be a UUID"))
+		Expect(BeAURL().NegatedFailureMessage("foo")).To(ContainSubstring("not to be a URL"))
+	})
+
+	Context("when used as data values", func() {
+		var logger *TestLogger
+
+		BeforeEach(func() {
+			logger = NewLogger("test")
+			logger.Info("request", lager.Data{
+				"guid": "0b4e7a0e-5fe1-4b6a-8b16-9c3ff2e5e7a2",
+				"url":  "https://example.com/v2/apps",
+				"ip":   "10.0.0.1",
+			})
+		})
+
+		It("matches values of the right format", func() {
+			Expect(logger).To(ContainSequence(
+				Info(Data("guid", BeAUUID(), "url", BeAURL(), "ip", BeAnIP())),
+			))
+		})
+
+		It("does not match values of the wrong format", func() {
+			Expect(logger).ToNot(ContainSequence(Info(Data("guid", BeAnIP()))))
+			Expect(logger).ToNot(ContainSequence(Info(Data("missing", BeAUUID()))))
+		})
+	})
+})
@@ -0,0 +1,116 @@
+package glager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/gomega/types"
+)
+
+// Verbosity specifies how much of the actual log is included in the failure
+// messages of the ContainSequence and HaveLogged matchers.
+type Verbosity int
+
+const (
+	// VerbositySummary only includes the expected entry that could not be
+	// found and the data mismatches of similar entries.
+	VerbositySummary Verbosity = iota
+
+	// VerbosityWindow additionally includes the entries of the actual log
+	// around the point where matching diverged from the expected sequence.
+	VerbosityWindow
+
+	// VerbosityFull includes all entries of the actual log.
+	VerbosityFull
+)
+
+// FailureVerbosity is the verbosity used by all matchers that have not been
+// configured using WithVerbosity. Defaults to VerbosityFull.
+var FailureVerbosity = VerbosityFull
+
+// divergenceWindow is the number of entries following the point of divergence
+// that are included in failure messages when using VerbosityWindow.
+const divergenceWindow = 5
+
+// WithVerbosity returns a copy of the given ContainSequence or HaveLogged
+// matcher that uses the given verbosity for its failure messages, regardless
+// of FailureVerbosity. It panics for any other matcher.
+//
+// Example:
+//
+//	Expect(log).To(WithVerbosity(VerbositySummary, ContainSequence(
+//	  Info(Action("test.start")),
+//	)))
+func WithVerbosity(verbosity Verbosity, matcher types.GomegaMatcher) types.GomegaMatcher {
+	lm, ok := matcher.(*logMatcher)
+	if !ok {
+		panic(fmt.Errorf("WithVerbosity must be passed a ContainSequence or HaveLogged matcher. Got %T.", matcher))
+	}
+
+	configured := *lm
+	configured.verbosity = &verbosity
+	return &configured
+}
+
+func (lm *logMatcher) currentVerbosity() Verbosity {
+	if lm.verbosity != nil {
+		return *lm.verbosity
+	}
+	return FailureVerbosity
+}
+
+// divergence returns the index of the first actual entry following the last
+// entry that has been matched.
+func (lm *logMatcher) divergence() int {
+	if len(lm.matched) == 0 {
+		return 0
+	}
+	return lm.matched[len(lm.matched)-1] + 1
+}
+
+// renderWindow renders the actual entries around the point of divergence,
+// including the last matched entry.
+func (lm *logMatcher) renderWindow() string {
+	if lm.stream != nil {
+		return renderWindow(lm.stream.window, lm.stream.first, lm.stream.entries)
+	}
+
+	from := lm.divergence() - 1
+	if from < 0 {
+		from = 0
+	}
+
+	to := lm.divergence() + divergenceWindow
+	if to > len(lm.actual) {
+		to = len(lm.actual)
+	}
+
+	if from >= to {
+		return "<no entries>"
+	}
+
+	return renderWindow(lm.actual[from:to], from, len(lm.actual))
+}
+
+// renderWindow renders the given entries, the first of which is the entry with
+// the given index out of the given total number of entries.
+func renderWindow(entries logEntries, from, total int) string {
+	if len(entries) == 0 {
+		return "<no entries>"
+	}
+
+	lines := []string{}
+	if from > 0 {
+		lines = append(lines, fmt.Sprintf("... %d entries", from))
+	}
+
+	for i, entry := range entries {
+		lines = append(lines, fmt.Sprintf("entry %d: %s", from+i, entry.GoString()))
+	}
+
+	if to := from + len(entries); to < total {
+		lines = append(lines, fmt.Sprintf("... %d entries", total-to))
+	}
+
+	return strings.Join(lines, "\n\t")
+}
@@ -0,0 +1,84 @@
+package glager_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("failure verbosity", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+		for i := 0; i < 20; i++ {
+			logger.Info(fmt.Sprintf("step-%d", i))
+		}
+	})
+
+	failureMessage := func(verbosity Verbosity) string {
+		matcher := WithVerbosity(verbosity, ContainSequence(
+			Info(Action("test.step-3")),
+			Info(Action("test.missing")),
+		))
+
+		Expect(matcher.Match(logger)).To(BeFalse())
+		return matcher.FailureMessage(logger)
+	}
+
+	Describe("VerbositySummary", func() {
+		It("only includes the missing entry", func() {
+			message := failureMessage(VerbositySummary)
+			Expect(message).To(HavePrefix("Expected log to contain entry 1 of log sequence\n\t" +
+				`Info(Message("test.missing"))` + "\nafter log entry 4\n"))
+			Expect(message).ToNot(ContainSubstring("test.step-0"))
+		})
+	})
+
+	Describe("VerbosityWindow", func() {
+		It("includes the entries around the divergence", func() {
+			message := failureMessage(VerbosityWindow)
+			Expect(message).To(ContainSubstring("... 3 entries\n\tentry 3: "))
+			Expect(message).To(ContainSubstring(`entry 8: glager.LogEntry{`))
+			Expect(message).To(ContainSubstring("... 11 entries"))
+			Expect(message).ToNot(ContainSubstring("entry 2:"))
+			Expect(message).ToNot(ContainSubstring("entry 9:"))
+		})
+	})
+
+	Describe("VerbosityFull", func() {
+		It("includes all entries", func() {
+			message := failureMessage(VerbosityFull)
+			Expect(message).To(ContainSubstring("test.step-0"))
+			Expect(message).To(ContainSubstring("test.step-19"))
+		})
+	})
+
+	Describe("FailureVerbosity", func() {
+		AfterEach(func() {
+			FailureVerbosity = VerbosityFull
+		})
+
+		It("configures all matchers", func() {
+			FailureVerbosity = VerbositySummary
+
+			matcher := ContainSequence(Info(Action("test.missing")))
+			Expect(matcher.Match(logger)).To(BeFalse())
+			Expect(matcher.FailureMessage(logger)).To(HavePrefix("Expected log to contain entry 0"))
+		})
+
+		It("is overridden by WithVerbosity", func() {
+			FailureVerbosity = VerbositySummary
+			Expect(failureMessage(VerbosityFull)).To(ContainSubstring("test.step-19"))
+		})
+	})
+
+	Describe(".WithVerbosity", func() {
+		It("panics for unsupported matchers", func() {
+			Expect(func() { WithVerbosity(VerbositySummary, BeNDJSON()) }).To(Panic())
+		})
+	})
+})
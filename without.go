@@ -0,0 +1,181 @@
+package glager
+
+import "fmt"
+
+// Without specifies that no entry matching the given log entry may appear at
+// the given position of a sequence, i.e. between the entries matched for the
+// expected entries surrounding it. At the start of a sequence it applies to
+// the log up to the first matched entry, at its end to the log following the
+// last matched entry. Use it to verify that nothing went wrong in between two
+// entries. Negated entries are supported by ContainSequence, HaveLogged,
+// BeginWith, EndWith, and the functions scanning for sequences. Every sequence
+// has to contain at least one entry that is not negated.
+//
+// Example:
+//
+//	Expect(logger).To(ContainSequence(
+//	  Info(Action("test.starting")),
+//	  Without(Error(AnyErr)),
+//	  Info(Action("test.finished")),
+//	))
+func Without(entry ExpectedEntry) absentEntry {
+	entry.negated = true
+	return absentEntry(entry)
+}
+
+// absentEntry is an entry passed to Without.
+type absentEntry logEntry
+
+func (entry absentEntry) sequenceEntry() logEntry { return logEntry(entry) }
+
+// compileSequence expands the repeated entries of the given sequence and
+// attaches negated entries to the entry that follows them, or to the last
+// entry if there is none.
+func compileSequence(sequence []logEntry) []logEntry {
+	compiled := []logEntry{}
+	pending := []logEntry{}
+
+	for _, entry := range expandRepetitions(sequence) {
+		if entry.negated {
+			entry.negated = false
+			pending = append(pending, entry)
+			continue
+		}
+
+		if len(pending) > 0 {
+			entry.absentBefore = append(append([]logEntry{}, entry.absentBefore...), pending...)
+			pending = []logEntry{}
+		}
+		compiled = append(compiled, entry)
+	}
+
+	if len(compiled) > 0 && compiled[0].immediate {
+		panic(fmt.Errorf("Immediately can not be applied to the first entry of a sequence. Got %s.", compiled[0].GomegaString()))
+	}
+
+	if len(pending) > 0 {
+		if len(compiled) == 0 {
+			panic(fmt.Errorf("A sequence must contain at least one entry that is not passed to Without. Got %d negated entries.", len(pending)))
+		}
+
+		last := &compiled[len(compiled)-1]
+		last.absentAfter = append(append([]logEntry{}, last.absentAfter...), pending...)
+	}
+
+	return compiled
+}
+
+// lastOccurrence returns the index of the last entry in entries[start:end]
+// that matches any of the given entries, or -1 if there is none.
+func (entries logEntries) lastOccurrence(absent []logEntry, start, end int) (int, error) {
+	for i := end - 1; i >= start; i-- {
+		for _, expected := range absent {
+			containsEntry, err := entries[i].contains(expected)
+			if err != nil {
+				return 0, err
+			}
+			if containsEntry {
+				return i, nil
+			}
+		}
+	}
+	return -1, nil
+}
+
+// violation returns the index of the last entry between the entry matched for
+// expectedSequence[k], or the start of the log if k is negative, and end that
+// matches any of the given absent entries, or -1 if there is none.
+func (entries logEntries) violation(matched []int, k int, absent []logEntry, end int) (int, error) {
+	if len(absent) == 0 {
+		return -1, nil
+	}
+
+	start := 0
+	if k >= 0 {
+		start = matched[k] + 1
+	}
+
+	return entries.lastOccurrence(absent, start, end)
+}
+
+// movable reports whether the entry matched for expectedSequence[k] can be
+// moved to a later occurrence without changing the number of repetitions of a
+// repeated entry, or separating an entry passed to Immediately from the entry
+// it follows.
+func movable(expectedSequence []logEntry, k int) bool {
+	if k < 0 || expectedSequence[k].repeated.times > 0 || expectedSequence[k].immediate {
+		return false
+	}
+	return k == 0 || !expectedSequence[k-1].lastRepetition()
+}
+
+// ensureAbsent checks that none of the given entries appears between the
+// entry matched for expectedSequence[k] and end. If one does, the match of
+// expectedSequence[k] is moved past it, if that is possible without violating
+// any other constraint. It reports whether the entries are absent afterwards.
+func (entries logEntries) ensureAbsent(expectedSequence []logEntry, matched []int, k int, absent []logEntry, end int) (bool, error) {
+	j, err := entries.violation(matched, k, absent, end)
+	if err != nil || j < 0 {
+		return err == nil, err
+	}
+
+	if !movable(expectedSequence, k) {
+		return false, nil
+	}
+
+	i, found, err := entries[j+1 : end].indexOf(expectedSequence[k])
+	if err != nil || !found {
+		return false, err
+	}
+
+	previous := 0
+	if k > 0 {
+		previous = matched[k-1] + 1
+	}
+
+	// moving the match extends the range its own negated entries apply to
+	violation, err := entries.lastOccurrence(expectedSequence[k].absentBefore, previous, j+1+i)
+	if err != nil || violation >= 0 {
+		return false, err
+	}
+
+	matched[k] = j + 1 + i
+	return true, nil
+}
+
+// forbidden describes the first actual entry following the divergence of the
+// sequence that matches any of the entries that must not appear around the
+// first expected entry that has not been matched.
+func (lm *logMatcher) forbidden() (string, error) {
+	if len(lm.matched) >= len(lm.expected) {
+		return "", nil
+	}
+
+	expected := lm.expected[len(lm.matched)]
+	absent := append(append([]logEntry{}, expected.absentBefore...), expected.absentAfter...)
+
+	offset := lm.divergence()
+	for i, actual := range lm.actual[offset:] {
+		for _, entry := range absent {
+			containsEntry, err := actual.contains(entry)
+			if err != nil {
+				return "", err
+			}
+			if containsEntry {
+				return fmt.Sprintf("\nForbidden entry %d matching %s:\n\t%s", offset+i, entry.GomegaString(), actual.GoString()), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// renderAbsent renders the given entries the way they have been passed to
+// Without.
+func renderAbsent(absent []logEntry) []string {
+	rendered := make([]string, len(absent))
+	for i, entry := range absent {
+		rendered[i] = fmt.Sprintf("Without(%s)", entry.GomegaString())
+	}
+	return rendered
+}
@@ -0,0 +1,184 @@
+package glager_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe(".Without", func() {
+	var logger *TestLogger
+
+	BeforeEach(func() {
+		logger = NewLogger("test")
+	})
+
+	It("matches if no forbidden entry appears between the surrounding entries", func() {
+		logger.Error("before", errors.New("boom"))
+		logger.Info("starting")
+		logger.Info("working")
+		logger.Info("finished")
+		logger.Error("after", errors.New("boom"))
+
+		Expect(logger).To(ContainSequence(
+			Info(Action("test.starting")),
+			Without(Error(AnyErr)),
+			Info(Action("test.finished")),
+		))
+	})
+
+	It("does not match if a forbidden entry appears between the surrounding entries", func() {
+		logger.Info("starting")
+		logger.Error("failed", errors.New("boom"))
+		logger.Info("finished")
+
+		Expect(logger).ToNot(ContainSequence(
+			Info(Action("test.starting")),
+			Without(Error(AnyErr)),
+			Info(Action("test.finished")),
+		))
+	})
+
+	It("matches a later occurrence of the preceding entry", func() {
+		logger.Info("starting")
+		logger.Error("failed", errors.New("boom"))
+		logger.Info("starting")
+		logger.Info("finished")
+
+		Expect(ScanSequence(mustEntries(logger),
+			Info(Action("test.starting")),
+			Without(Error(AnyErr)),
+			Info(Action("test.finished")),
+		)).To(Equal([]int{2, 3}))
+	})
+
+	It("matches later occurrences of both surrounding entries", func() {
+		logger.Info("a")
+		logger.Error("failed", errors.New("boom"))
+		logger.Info("b")
+		logger.Info("a")
+		logger.Info("b")
+
+		Expect(logger).To(ContainSequence(
+			Info(Action("test.a")),
+			Without(Error(AnyErr)),
+			Info(Action("test.b")),
+		))
+		Expect(ScanSequence(mustEntries(logger),
+			Info(Action("test.a")),
+			Without(Error(AnyErr)),
+			Info(Action("test.b")),
+		)).To(Equal([]int{3, 4}))
+	})
+
+	It("keeps the entries preceding the moved entry in place", func() {
+		logger.Info("x")
+		logger.Info("a")
+		logger.Error("failed", errors.New("boom"))
+		logger.Info("b")
+		logger.Info("a")
+		logger.Info("b")
+
+		Expect(ScanSequence(mustEntries(logger),
+			Info(Action("test.x")),
+			Info(Action("test.a")),
+			Without(Error(AnyErr)),
+			Info(Action("test.b")),
+		)).To(Equal([]int{0, 4, 5}))
+	})
+
+	It("matches a later occurrence of the last entry if a forbidden entry follows it", func() {
+		logger.Info("a")
+		logger.Error("failed", errors.New("boom"))
+		logger.Info("a")
+
+		Expect(ScanSequence(mustEntries(logger),
+			Info(Action("test.a")),
+			Without(Error(AnyErr)),
+		)).To(Equal([]int{2}))
+	})
+
+	It("applies to the start of the log at the start of the sequence", func() {
+		logger.Error("failed", errors.New("boom"))
+		logger.Info("starting")
+
+		Expect(logger).ToNot(ContainSequence(Without(Error(AnyErr)), Info(Action("test.starting"))))
+		Expect(logger).To(ContainSequence(Without(Debug()), Info(Action("test.starting"))))
+	})
+
+	It("applies to the end of the log at the end of the sequence", func() {
+		logger.Info("starting")
+		logger.Error("failed", errors.New("boom"))
+
+		Expect(logger).ToNot(ContainSequence(Info(Action("test.starting")), Without(Error(AnyErr))))
+		Expect(logger).To(ContainSequence(Error(AnyErr), Without(Error(AnyErr))))
+	})
+
+	It("supports several forbidden entries", func() {
+		logger.Info("starting")
+		logger.Debug("retrying")
+		logger.Info("finished")
+
+		Expect(logger).ToNot(ContainSequence(
+			Info(Action("test.starting")),
+			Without(Error(AnyErr)),
+			Without(Debug(Action("test.retrying"))),
+			Info(Action("test.finished")),
+		))
+	})
+
+	It("is supported by EndWith", func() {
+		logger.Info("starting")
+		logger.Error("failed", errors.New("boom"))
+		logger.Info("exited")
+
+		Expect(logger).ToNot(EndWith(Info(Action("test.starting")), Without(Error(AnyErr)), Info(Action("test.exited"))))
+		Expect(logger).To(EndWith(Info(Action("test.starting")), Without(Debug()), Info(Action("test.exited"))))
+	})
+
+	It("reports the forbidden entry", func() {
+		logger.Info("starting")
+		logger.Error("failed", errors.New("boom"))
+		logger.Info("finished")
+
+		matcher := ContainSequence(Info(Action("test.starting")), Without(Error(AnyErr)), Info(Action("test.finished")))
+		Expect(matcher.Match(logger)).To(BeFalse())
+
+		message := matcher.FailureMessage(logger)
+		Expect(message).To(ContainSubstring(`1: Without(Error(AnyErr)), Info(Message("test.finished"))`))
+		Expect(message).To(ContainSubstring("Forbidden entry 1 matching Error(AnyErr):\n\tglager.LogEntry{"))
+	})
+
+	It("panics if the sequence only contains negated entries", func() {
+		Expect(func() { ContainSequence(Without(Error(AnyErr))) }).To(Panic())
+	})
+
+	It("can only be passed to matchers of sequences", func() {
+		var entry interface{} = Without(Error(AnyErr))
+
+		_, sequence := entry.(SequenceEntry)
+		Expect(sequence).To(BeTrue())
+
+		_, required := entry.(RequiredEntry)
+		Expect(required).To(BeFalse())
+
+		_, single := entry.(ExpectedEntry)
+		Expect(single).To(BeFalse())
+	})
+
+	It("is supported by FindSequence", func() {
+		logger.Info("starting")
+		logger.Error("failed", errors.New("boom"))
+		logger.Info("finished")
+
+		_, err := FindSequence(logger, Info(Action("test.starting")), Without(Error(AnyErr)), Info(Action("test.finished")))
+		Expect(err).To(HaveOccurred())
+
+		found, err := FindSequence(logger, Info(Action("test.starting")), Without(Debug()), Info(Action("test.finished")))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,186 @@
+package glager
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// WorkerKey is the data key used to tag log entries with the label of the
+// goroutine or worker that wrote them.
+const WorkerKey = "worker"
+
+// WorkerLogger returns a child of the given logger that tags every entry it
+// writes with the specified worker label. Hand one of these to each goroutine
+// of a worker pool to be able to tell their interleaved output apart.
+func WorkerLogger(logger lager.Logger, worker string) lager.Logger {
+	return logger.WithData(lager.Data{WorkerKey: worker})
+}
+
+// Worker specifies the label of the worker that is expected to have written a
+// given log entry. It is a shorthand for Data(WorkerKey, worker).
+func Worker(worker string) Option {
+	return Data(WorkerKey, worker)
+}
+
+type workerMatcher struct {
+	worker  string
+	matcher types.GomegaMatcher
+	entries logEntries
+}
+
+// ForWorker applies the given matcher to the entries written by a single
+// worker only, i.e. to all entries tagged with the specified worker label.
+// Entries of other workers are removed from the log before matching.
+//
+// Example:
+//
+//	Expect(log).To(ForWorker("worker-1", ContainSequence(
+//	  Info(Action("test.job"), Data("event", "start")),
+//	  Info(Action("test.job"), Data("event", "done")),
+//	)))
+func ForWorker(worker string, matcher types.GomegaMatcher) types.GomegaMatcher {
+	return &workerMatcher{
+		worker:  worker,
+		matcher: matcher,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (wm *workerMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("ForWorker", actual)
+	if err != nil {
+		return false, err
+	}
+
+	wm.entries = entries.forWorker(wm.worker)
+
+	return wm.matcher.Match(wm.entries)
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (wm *workerMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("For worker %q:\n%s", wm.worker, wm.matcher.FailureMessage(wm.entries))
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (wm *workerMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("For worker %q:\n%s", wm.worker, wm.matcher.NegatedFailureMessage(wm.entries))
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (wm *workerMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+type eachWorkerMatcher struct {
+	matcher  types.GomegaMatcher
+	workers  []string
+	failed   string
+	failure  string
+	negation string
+}
+
+// EachWorker applies the given matcher to the entries of every worker found in
+// the log. The entries of each worker are matched in isolation, i.e. entries of
+// other workers are removed from the log before matching. Entries that have not
+// been tagged with a worker label are ignored. The matcher fails if the log does
+// not contain any tagged entries at all.
+//
+// Example:
+//
+//	Expect(log).To(EachWorker(ContainSequence(
+//	  Info(Data("event", "start")),
+//	  Info(Data("event", "done")),
+//	)))
+func EachWorker(matcher types.GomegaMatcher) types.GomegaMatcher {
+	return &eachWorkerMatcher{
+		matcher: matcher,
+	}
+}
+
+// Match is doing the actual matching for a given log assertion.
+func (em *eachWorkerMatcher) Match(actual interface{}) (success bool, err error) {
+	entries, err := parseEntries("EachWorker", actual)
+	if err != nil {
+		return false, err
+	}
+
+	em.workers = entries.workers()
+	em.failed = ""
+
+	if len(em.workers) == 0 {
+		return false, nil
+	}
+
+	for _, worker := range em.workers {
+		workerEntries := entries.forWorker(worker)
+
+		success, err := em.matcher.Match(workerEntries)
+		if err != nil {
+			return false, err
+		}
+
+		if !success {
+			em.failed = worker
+			em.failure = em.matcher.FailureMessage(workerEntries)
+			return false, nil
+		}
+
+		em.negation = em.matcher.NegatedFailureMessage(workerEntries)
+	}
+
+	return true, nil
+}
+
+// FailureMessage constructs a message for failed assertions.
+func (em *eachWorkerMatcher) FailureMessage(actual interface{}) (message string) {
+	if em.failed == "" {
+		return fmt.Sprintf(
+			"Expected\n%s\nto contain entries tagged with data key %q",
+			format.Object(actual, 1),
+			WorkerKey,
+		)
+	}
+
+	return fmt.Sprintf("For worker %q:\n%s", em.failed, em.failure)
+}
+
+// NegatedFailureMessage constructs a message for failed negative assertions.
+func (em *eachWorkerMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf(
+		"Expected at least one of the workers %v to fail. For the last worker:\n%s",
+		em.workers,
+		em.negation,
+	)
+}
+
+// MatchMayChangeInTheFuture implements gomega's OracleMatcher.
+func (em *eachWorkerMatcher) MatchMayChangeInTheFuture(actual interface{}) bool {
+	return mayChange(actual)
+}
+
+func (entries logEntries) forWorker(worker string) logEntries {
+	result := logEntries{}
+	for _, entry := range entries {
+		if label, ok := entry.Data[WorkerKey].(string); ok && label == worker {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+func (entries logEntries) workers() []string {
+	workers := []string{}
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		label, ok := entry.Data[WorkerKey].(string)
+		if ok && !seen[label] {
+			seen[label] = true
+			workers = append(workers, label)
+		}
+	}
+	return workers
+}
@@ -0,0 +1,148 @@
+package glager_test
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/st3v/glager"
+)
+
+var _ = Describe("worker capture", func() {
+	var (
+		buffer *gbytes.Buffer
+		logger lager.Logger
+	)
+
+	BeforeEach(func() {
+		buffer = gbytes.NewBuffer()
+		logger = lager.NewLogger("pool")
+		logger.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+	})
+
+	Describe(".WorkerLogger", func() {
+		It("tags entries with the worker label", func() {
+			WorkerLogger(logger, "worker-1").Info("job")
+
+			Expect(buffer).To(ContainSequence(
+				Info(
+					Action("pool.job"),
+					Data(WorkerKey, "worker-1"),
+				),
+			))
+		})
+	})
+
+	Describe(".Worker", func() {
+		It("matches entries of the given worker", func() {
+			WorkerLogger(logger, "worker-1").Info("job")
+
+			Expect(buffer).To(ContainSequence(Info(Worker("worker-1"))))
+			Expect(buffer).ToNot(ContainSequence(Info(Worker("worker-2"))))
+		})
+	})
+
+	Context("when workers interleave", func() {
+		BeforeEach(func() {
+			one := WorkerLogger(logger, "worker-1")
+			two := WorkerLogger(logger, "worker-2")
+
+			one.Info("job", lager.Data{"event": "start"})
+			two.Info("job", lager.Data{"event": "start"})
+			two.Info("job", lager.Data{"event": "done"})
+			logger.Info("untagged", lager.Data{"event": "done"})
+		})
+
+		Describe(".ForWorker", func() {
+			It("matches the sequence of the given worker", func() {
+				Expect(buffer).To(ForWorker("worker-2", ContainSequence(
+					Info(Data("event", "start")),
+					Info(Data("event", "done")),
+				)))
+			})
+
+			It("does not match entries of other workers", func() {
+				Expect(buffer).ToNot(ForWorker("worker-1", ContainSequence(
+					Info(Data("event", "start")),
+					Info(Data("event", "done")),
+				)))
+			})
+
+			It("names the worker in the failure message", func() {
+				matcher := ForWorker("worker-1", ContainSequence(Info(Data("event", "done"))))
+				Expect(matcher.Match(buffer)).To(BeFalse())
+				Expect(matcher.FailureMessage(buffer)).To(ContainSubstring(`For worker "worker-1"`))
+			})
+
+			It("passes the entries of the given worker to the failure messages", func() {
+				matcher := ForWorker("worker-2", HaveLen(3))
+				Expect(matcher.Match(buffer)).To(BeFalse())
+				Expect(matcher.FailureMessage(buffer)).To(ContainSubstring("len:2"))
+
+				matcher = ForWorker("worker-2", HaveLen(2))
+				Expect(matcher.Match(buffer)).To(BeTrue())
+				Expect(matcher.NegatedFailureMessage(buffer)).To(ContainSubstring("len:2"))
+			})
+
+			It("returns an error for invalid actuals", func() {
+				_, err := ForWorker("worker-1", ContainSequence()).Match(42)
+				Expect(err).To(MatchError(ContainSubstring("ForWorker must be passed")))
+			})
+		})
+
+		Describe(".EachWorker", func() {
+			It("matches a sequence logged by every worker", func() {
+				Expect(buffer).To(EachWorker(ContainSequence(
+					Info(Data("event", "start")),
+				)))
+			})
+
+			It("does not match a sequence missing for one worker", func() {
+				matcher := EachWorker(ContainSequence(
+					Info(Data("event", "start")),
+					Info(Data("event", "done")),
+				))
+
+				Expect(matcher.Match(buffer)).To(BeFalse())
+				Expect(matcher.FailureMessage(buffer)).To(ContainSubstring(`For worker "worker-1"`))
+			})
+		})
+	})
+
+	Context("when no entries are tagged", func() {
+		It("does not match", func() {
+			logger.Info("untagged")
+
+			matcher := EachWorker(ContainSequence())
+			Expect(matcher.Match(buffer)).To(BeFalse())
+			Expect(matcher.FailureMessage(buffer)).To(ContainSubstring("to contain entries tagged"))
+		})
+	})
+
+	Context("when workers log concurrently", func() {
+		It("matches the sequence of every worker", func() {
+			var wg sync.WaitGroup
+			for _, label := range []string{"a", "b", "c", "d"} {
+				wg.Add(1)
+				go func(worker lager.Logger) {
+					defer GinkgoRecover()
+					defer wg.Done()
+					for _, event := range []string{"start", "progress", "done"} {
+						worker.Info("job", lager.Data{"event": event})
+					}
+				}(WorkerLogger(logger, label))
+			}
+			wg.Wait()
+
+			Expect(buffer).To(EachWorker(ContainSequence(
+				Info(Data("event", "start")),
+				Info(Data("event", "progress")),
+				Info(Data("event", "done")),
+			)))
+		})
+	})
+})